@@ -0,0 +1,48 @@
+package status
+
+import (
+	"encoding/json"
+)
+
+// Component.ImageInfo, Component.RepoURL and GitlabMRMerge.SHA were written as ImageInfo, RepoURL and SHA before the
+// JSON names were made camelCase.  Both names are written so clients reading the old ones keep working until they
+// move over.  encoding/json matches names case-insensitively, so Go clients read either.
+
+func (c Component) MarshalJSON() ([]byte, error) {
+	// the conversion drops this method, so json.Marshal doesn't recurse.
+	type component Component
+	return json.Marshal(struct {
+		component
+		DeprecatedImageInfo ContainerImage `json:"ImageInfo"`
+		DeprecatedRepoURL   *string        `json:"RepoURL"`
+	}{
+		component:           component(c),
+		DeprecatedImageInfo: c.ImageInfo,
+		DeprecatedRepoURL:   c.RepoURL,
+	})
+}
+
+// DeprecatedJSONNames lets the OpenAPI document describe the old names.
+func (c Component) DeprecatedJSONNames() map[string]string {
+	return map[string]string{
+		"ImageInfo": "imageInfo",
+		"RepoURL":   "repoURL",
+	}
+}
+
+func (m GitlabMRMerge) MarshalJSON() ([]byte, error) {
+	type gitlabMRMerge GitlabMRMerge
+	return json.Marshal(struct {
+		gitlabMRMerge
+		DeprecatedSHA string `json:"SHA"`
+	}{
+		gitlabMRMerge: gitlabMRMerge(m),
+		DeprecatedSHA: m.SHA,
+	})
+}
+
+func (m GitlabMRMerge) DeprecatedJSONNames() map[string]string {
+	return map[string]string{
+		"SHA": "sha",
+	}
+}
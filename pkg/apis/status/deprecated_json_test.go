@@ -0,0 +1,39 @@
+package status
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestDeprecatedJSONNames(t *testing.T) {
+	component := &Component{
+		Name:      "Backend",
+		ImageInfo: ContainerImage{Digest: "sha256:abc"},
+		RepoURL:   ptr.To("https://github.com/Azure/ARO-HCP"),
+		SourceSHA: "abc",
+	}
+	componentJSON, err := json.Marshal(component)
+	require.NoError(t, err)
+	fields := map[string]any{}
+	require.NoError(t, json.Unmarshal(componentJSON, &fields))
+	assert.Equal(t, fields["imageInfo"], fields["ImageInfo"])
+	assert.Equal(t, "https://github.com/Azure/ARO-HCP", fields["repoURL"])
+	assert.Equal(t, "https://github.com/Azure/ARO-HCP", fields["RepoURL"])
+	roundTripped := &Component{}
+	require.NoError(t, json.Unmarshal(componentJSON, roundTripped))
+	assert.Equal(t, component, roundTripped)
+
+	// JSON written before the rename still reads.
+	oldComponent := &Component{}
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Backend","ImageInfo":{"digest":"sha256:abc"},"RepoURL":"https://github.com/Azure/ARO-HCP","sourceSHA":"abc"}`), oldComponent))
+	assert.Equal(t, component, oldComponent)
+
+	mergeJSON, err := json.Marshal(GitlabMRMerge{MRNumber: 3, SHA: "def"})
+	require.NoError(t, err)
+	assert.Contains(t, string(mergeJSON), `"sha":"def"`)
+	assert.Contains(t, string(mergeJSON), `"SHA":"def"`)
+}
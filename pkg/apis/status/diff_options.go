@@ -0,0 +1,162 @@
+package status
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// DiffOptions pages the changes of each component in an EnvironmentReleaseDiff.  The zero value is the first page
+// of the server's default size.
+type DiffOptions struct {
+	// Limit is the most changes listed for each component.  The server lowers it to its own maximum.
+	Limit int
+	// Continue is the token from the previous page.
+	Continue string
+}
+
+// ToQuery is the inverse of ParseDiffOptions.
+func (o DiffOptions) ToQuery() url.Values {
+	ret := url.Values{}
+	if o.Limit > 0 {
+		ret.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if len(o.Continue) > 0 {
+		ret.Set("continue", o.Continue)
+	}
+	return ret
+}
+
+// ParseDiffOptions reads the diff query parameters.  Malformed values are BadRequest errors.
+func ParseDiffOptions(query url.Values) (DiffOptions, error) {
+	ret := DiffOptions{
+		Continue: query.Get("continue"),
+	}
+	if limitString := query.Get("limit"); len(limitString) > 0 {
+		var err error
+		ret.Limit, err = strconv.Atoi(limitString)
+		if err != nil || ret.Limit < 1 {
+			return DiffOptions{}, NewBadRequest(fmt.Sprintf("limit must be a positive integer, not %q", limitString))
+		}
+	}
+	if _, err := ret.Offset(); err != nil {
+		return DiffOptions{}, err
+	}
+	return ret, nil
+}
+
+// Offset is how many changes of each component the continue token skips.
+func (o DiffOptions) Offset() (int, error) {
+	if len(o.Continue) == 0 {
+		return 0, nil
+	}
+	offsetBytes, err := base64.RawURLEncoding.DecodeString(o.Continue)
+	if err != nil {
+		return 0, NewBadRequest(fmt.Sprintf("invalid continue token %q", o.Continue))
+	}
+	offset, err := strconv.Atoi(string(offsetBytes))
+	if err != nil || offset < 0 {
+		return 0, NewBadRequest(fmt.Sprintf("invalid continue token %q", o.Continue))
+	}
+	return offset, nil
+}
+
+// DiffContinueToken is the token of the page starting offset changes into each component.
+func DiffContinueToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// PageEnvironmentReleaseDiff pages a diff that was computed before the options were known, like one read from a
+// snapshot.  It can only page through the changes the diff lists.  The diff may be shared, so it is copied rather than
+// mutated.
+func PageEnvironmentReleaseDiff(diff *EnvironmentReleaseDiff, options DiffOptions) (*EnvironmentReleaseDiff, error) {
+	offset, err := options.Offset()
+	if err != nil {
+		return nil, err
+	}
+	if offset == 0 && options.Limit < 1 {
+		return diff, nil
+	}
+
+	ret := &EnvironmentReleaseDiff{
+		TypeMeta:                    diff.TypeMeta,
+		Name:                        diff.Name,
+		OtherEnvironmentReleaseName: diff.OtherEnvironmentReleaseName,
+		DifferentComponents:         map[string]*ComponentDiff{},
+		CIComparison:                diff.CIComparison,
+	}
+	hasMore := false
+	for name, componentDiff := range diff.DifferentComponents {
+		start := min(offset, len(componentDiff.Changes))
+		end := len(componentDiff.Changes)
+		if options.Limit > 0 {
+			end = min(start+options.Limit, end)
+		}
+		if end < len(componentDiff.Changes) {
+			hasMore = true
+		}
+		ret.DifferentComponents[name] = &ComponentDiff{
+			Name:                componentDiff.Name,
+			NumberOfChanges:     componentDiff.NumberOfChanges,
+			Truncated:           end < componentDiff.NumberOfChanges,
+			SearchDepthExceeded: componentDiff.SearchDepthExceeded,
+			Changes:             componentDiff.Changes[start:end],
+		}
+	}
+	if hasMore {
+		ret.Continue = DiffContinueToken(offset + options.Limit)
+	}
+	return ret, nil
+}
+
+// GetAllEnvironmentReleaseDiffPages calls getPage with each continue token until no page is left, and returns the first
+// page with the changes of every later page appended.  Components whose changes are unavailable repeat the same
+// reason on every page, so only the first one is kept.
+func GetAllEnvironmentReleaseDiffPages(ctx context.Context, getPage func(ctx context.Context, options DiffOptions) (*EnvironmentReleaseDiff, error)) (*EnvironmentReleaseDiff, error) {
+	page, err := getPage(ctx, DiffOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := &EnvironmentReleaseDiff{
+		TypeMeta:                    page.TypeMeta,
+		Name:                        page.Name,
+		OtherEnvironmentReleaseName: page.OtherEnvironmentReleaseName,
+		DifferentComponents:         map[string]*ComponentDiff{},
+		CIComparison:                page.CIComparison,
+	}
+	// the pages may be shared with a cache, so they are copied rather than appended to.
+	for name, componentDiff := range page.DifferentComponents {
+		copied := *componentDiff
+		copied.Changes = append([]ComponentChange{}, componentDiff.Changes...)
+		ret.DifferentComponents[name] = &copied
+	}
+
+	for continueToken := page.Continue; len(continueToken) > 0; continueToken = page.Continue {
+		page, err = getPage(ctx, DiffOptions{Continue: continueToken})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the diff page after %q: %w", continueToken, err)
+		}
+		if page.Continue == continueToken {
+			return nil, fmt.Errorf("the diff page after %q returned the same continue token", continueToken)
+		}
+		for name, componentDiff := range page.DifferentComponents {
+			existing, ok := ret.DifferentComponents[name]
+			if !ok {
+				copied := *componentDiff
+				copied.Changes = append([]ComponentChange{}, componentDiff.Changes...)
+				ret.DifferentComponents[name] = &copied
+				continue
+			}
+			if existing.NumberOfChanges < 0 {
+				continue
+			}
+			existing.Changes = append(existing.Changes, componentDiff.Changes...)
+		}
+	}
+	for _, componentDiff := range ret.DifferentComponents {
+		componentDiff.Truncated = componentDiff.NumberOfChanges >= 0 && len(componentDiff.Changes) < componentDiff.NumberOfChanges
+	}
+	return ret, nil
+}
@@ -0,0 +1,53 @@
+package status
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestGetAllEnvironmentReleaseDiffPages(t *testing.T) {
+	changes := []ComponentChange{}
+	for _, sha := range []string{"a", "b", "c", "d", "e"} {
+		changes = append(changes, ComponentChange{ChangeType: "GithubPRMerge", GithubPRMerge: &GithubPRMerge{SHA: sha}})
+	}
+	unavailable := []ComponentChange{{ChangeType: "Unavailable", Unavailable: ptr.To("no git history")}}
+	fullDiff := &EnvironmentReleaseDiff{
+		Name:                        "int---new",
+		OtherEnvironmentReleaseName: "int---old",
+		DifferentComponents: map[string]*ComponentDiff{
+			"Backend":  {Name: "Backend", NumberOfChanges: len(changes), Changes: changes},
+			"Frontend": {Name: "Frontend", NumberOfChanges: 1, Changes: changes[:1]},
+		},
+	}
+
+	pages := 0
+	actual, err := GetAllEnvironmentReleaseDiffPages(context.Background(), func(ctx context.Context, options DiffOptions) (*EnvironmentReleaseDiff, error) {
+		pages++
+		page, err := PageEnvironmentReleaseDiff(fullDiff, DiffOptions{Limit: 2, Continue: options.Continue})
+		if err != nil {
+			return nil, err
+		}
+		// like the server, unavailable changes are reported again on every page.
+		page.DifferentComponents["Maestro"] = &ComponentDiff{Name: "Maestro", NumberOfChanges: -1, Changes: unavailable}
+		return page, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, "int---new", actual.Name)
+	assert.Empty(t, actual.Continue)
+	assert.Equal(t, changes, actual.DifferentComponents["Backend"].Changes)
+	assert.False(t, actual.DifferentComponents["Backend"].Truncated)
+	assert.Equal(t, changes[:1], actual.DifferentComponents["Frontend"].Changes)
+	assert.Equal(t, unavailable, actual.DifferentComponents["Maestro"].Changes)
+	// the pages aren't modified, they may be cached.
+	assert.Len(t, fullDiff.DifferentComponents["Backend"].Changes, len(changes))
+
+	_, err = GetAllEnvironmentReleaseDiffPages(context.Background(), func(ctx context.Context, options DiffOptions) (*EnvironmentReleaseDiff, error) {
+		return &EnvironmentReleaseDiff{Continue: "same"}, nil
+	})
+	assert.ErrorContains(t, err, "returned the same continue token")
+}
@@ -0,0 +1,151 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type StatusReason string
+
+const (
+	StatusReasonNotFound           StatusReason = "NotFound"
+	StatusReasonBadRequest         StatusReason = "BadRequest"
+	StatusReasonServiceUnavailable StatusReason = "ServiceUnavailable"
+	StatusReasonTimeout            StatusReason = "Timeout"
+	// StatusReasonExpired means the requested resource version is older than the server remembers.  Clients must list
+	// again.
+	StatusReasonExpired       StatusReason = "Expired"
+	StatusReasonInternalError StatusReason = "InternalError"
+)
+
+const StatusFailure = "Failure"
+
+// Status is the body of every API error response.
+type Status struct {
+	TypeMeta `json:",inline"`
+	Status   string       `json:"status"`
+	Message  string       `json:"message"`
+	Reason   StatusReason `json:"reason"`
+	Code     int          `json:"code"`
+}
+
+// StatusError carries a Status through the accessor, the handlers and the clients so the kind of failure survives
+// wrapping with %w.
+type StatusError struct {
+	ErrStatus Status
+}
+
+func (e *StatusError) Error() string {
+	return e.ErrStatus.Message
+}
+
+func newStatusError(reason StatusReason, code int, message string) *StatusError {
+	return &StatusError{
+		ErrStatus: Status{
+			TypeMeta: TypeMeta{
+				Kind:       "Status",
+				APIVersion: "service-status.hcm.openshift.io/v1",
+			},
+			Status:  StatusFailure,
+			Message: message,
+			Reason:  reason,
+			Code:    code,
+		},
+	}
+}
+
+func NewNotFound(kind, name string) *StatusError {
+	return newStatusError(StatusReasonNotFound, http.StatusNotFound, fmt.Sprintf("%s %q not found", kind, name))
+}
+
+func NewBadRequest(message string) *StatusError {
+	return newStatusError(StatusReasonBadRequest, http.StatusBadRequest, message)
+}
+
+func NewServiceUnavailable(message string) *StatusError {
+	return newStatusError(StatusReasonServiceUnavailable, http.StatusServiceUnavailable, message)
+}
+
+func NewTimeout(message string) *StatusError {
+	return newStatusError(StatusReasonTimeout, http.StatusGatewayTimeout, message)
+}
+
+func NewExpired(message string) *StatusError {
+	return newStatusError(StatusReasonExpired, http.StatusGone, message)
+}
+
+func NewInternalError(err error) *StatusError {
+	return newStatusError(StatusReasonInternalError, http.StatusInternalServerError, err.Error())
+}
+
+// NewStatusErrorFromResponse rebuilds the error a server sent.  body is used when it is a Status, otherwise the
+// reason is guessed from the HTTP code.
+func NewStatusErrorFromResponse(code int, body []byte) *StatusError {
+	decodedStatus := Status{}
+	if err := json.Unmarshal(body, &decodedStatus); err == nil && decodedStatus.Kind == "Status" && len(decodedStatus.Reason) > 0 {
+		return &StatusError{ErrStatus: decodedStatus}
+	}
+	message := fmt.Sprintf("request failed: %v: %v", code, string(body))
+	switch code {
+	case http.StatusNotFound:
+		return newStatusError(StatusReasonNotFound, code, message)
+	case http.StatusBadRequest:
+		return newStatusError(StatusReasonBadRequest, code, message)
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway:
+		return newStatusError(StatusReasonServiceUnavailable, code, message)
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return newStatusError(StatusReasonTimeout, code, message)
+	case http.StatusGone:
+		return newStatusError(StatusReasonExpired, code, message)
+	default:
+		return newStatusError(StatusReasonInternalError, code, message)
+	}
+}
+
+// StatusForError returns the Status to send for err.  The reason and code come from the StatusError err wraps, if
+// any, and the message is the whole wrapped error so the context added along the way isn't lost.
+func StatusForError(err error) Status {
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &statusErr):
+	case errors.Is(err, context.DeadlineExceeded):
+		statusErr = NewTimeout("")
+	default:
+		statusErr = NewInternalError(err)
+	}
+	ret := statusErr.ErrStatus
+	ret.Message = err.Error()
+	return ret
+}
+
+// ReasonForError returns the reason of the StatusError err wraps, or an empty reason.
+func ReasonForError(err error) StatusReason {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.ErrStatus.Reason
+	}
+	return ""
+}
+
+func IsNotFound(err error) bool {
+	return ReasonForError(err) == StatusReasonNotFound
+}
+
+func IsBadRequest(err error) bool {
+	return ReasonForError(err) == StatusReasonBadRequest
+}
+
+func IsServiceUnavailable(err error) bool {
+	return ReasonForError(err) == StatusReasonServiceUnavailable
+}
+
+func IsExpired(err error) bool {
+	return ReasonForError(err) == StatusReasonExpired
+}
+
+func IsTimeout(err error) bool {
+	return ReasonForError(err) == StatusReasonTimeout || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,174 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatusErrorFromResponse(t *testing.T) {
+	notFoundBody, err := json.Marshal(NewNotFound("EnvironmentRelease", "int---1").ErrStatus)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		code            int
+		body            string
+		expectedReason  StatusReason
+		expectedCode    int
+		expectedMessage string
+	}{
+		{
+			name:            "status body",
+			code:            http.StatusNotFound,
+			body:            string(notFoundBody),
+			expectedReason:  StatusReasonNotFound,
+			expectedCode:    http.StatusNotFound,
+			expectedMessage: `EnvironmentRelease "int---1" not found`,
+		},
+		{
+			name:            "status body wins over the code",
+			code:            http.StatusBadGateway,
+			body:            string(notFoundBody),
+			expectedReason:  StatusReasonNotFound,
+			expectedCode:    http.StatusNotFound,
+			expectedMessage: `EnvironmentRelease "int---1" not found`,
+		},
+		{
+			name:            "non-JSON body",
+			code:            http.StatusNotFound,
+			body:            "404 page not found",
+			expectedReason:  StatusReasonNotFound,
+			expectedCode:    http.StatusNotFound,
+			expectedMessage: "request failed: 404: 404 page not found",
+		},
+		{
+			name:            "JSON that isn't a Status",
+			code:            http.StatusBadRequest,
+			body:            `{"kind": "EnvironmentRelease", "reason": "NotFound"}`,
+			expectedReason:  StatusReasonBadRequest,
+			expectedCode:    http.StatusBadRequest,
+			expectedMessage: `request failed: 400: {"kind": "EnvironmentRelease", "reason": "NotFound"}`,
+		},
+		{
+			name:            "Status without a reason",
+			code:            http.StatusGone,
+			body:            `{"kind": "Status", "message": "too old"}`,
+			expectedReason:  StatusReasonExpired,
+			expectedCode:    http.StatusGone,
+			expectedMessage: `request failed: 410: {"kind": "Status", "message": "too old"}`,
+		},
+		{name: "too many requests", code: http.StatusTooManyRequests, expectedReason: StatusReasonServiceUnavailable, expectedCode: http.StatusTooManyRequests},
+		{name: "bad gateway", code: http.StatusBadGateway, expectedReason: StatusReasonServiceUnavailable, expectedCode: http.StatusBadGateway},
+		{name: "service unavailable", code: http.StatusServiceUnavailable, expectedReason: StatusReasonServiceUnavailable, expectedCode: http.StatusServiceUnavailable},
+		{name: "gateway timeout", code: http.StatusGatewayTimeout, expectedReason: StatusReasonTimeout, expectedCode: http.StatusGatewayTimeout},
+		{name: "request timeout", code: http.StatusRequestTimeout, expectedReason: StatusReasonTimeout, expectedCode: http.StatusRequestTimeout},
+		{name: "other codes", code: http.StatusTeapot, expectedReason: StatusReasonInternalError, expectedCode: http.StatusTeapot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := NewStatusErrorFromResponse(tt.code, []byte(tt.body))
+			assert.Equal(t, tt.expectedReason, actual.ErrStatus.Reason)
+			assert.Equal(t, tt.expectedCode, actual.ErrStatus.Code)
+			assert.Equal(t, "Status", actual.ErrStatus.Kind)
+			assert.Equal(t, StatusFailure, actual.ErrStatus.Status)
+			if len(tt.expectedMessage) > 0 {
+				assert.Equal(t, tt.expectedMessage, actual.Error())
+			}
+		})
+	}
+}
+
+// TestStatusErrorRoundTrip sends each error the way the server does and reads it back the way the clients do.
+func TestStatusErrorRoundTrip(t *testing.T) {
+	tests := []struct {
+		err          *StatusError
+		expectedCode int
+		is           func(error) bool
+	}{
+		{err: NewNotFound("Environment", "int"), expectedCode: http.StatusNotFound, is: IsNotFound},
+		{err: NewBadRequest("bad"), expectedCode: http.StatusBadRequest, is: IsBadRequest},
+		{err: NewServiceUnavailable("busy"), expectedCode: http.StatusServiceUnavailable, is: IsServiceUnavailable},
+		{err: NewTimeout("slow"), expectedCode: http.StatusGatewayTimeout, is: IsTimeout},
+		{err: NewExpired("old"), expectedCode: http.StatusGone, is: IsExpired},
+		{err: NewInternalError(errors.New("broken")), expectedCode: http.StatusInternalServerError, is: func(err error) bool { return ReasonForError(err) == StatusReasonInternalError }},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.err.ErrStatus.Reason), func(t *testing.T) {
+			sent := StatusForError(fmt.Errorf("failed to get: %w", tt.err))
+			assert.Equal(t, tt.expectedCode, sent.Code)
+			body, err := json.Marshal(sent)
+			require.NoError(t, err)
+
+			received := NewStatusErrorFromResponse(sent.Code, body)
+			assert.True(t, tt.is(received))
+			assert.Equal(t, "failed to get: "+tt.err.Error(), received.Error())
+		})
+	}
+}
+
+func TestStatusForError(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedReason  StatusReason
+		expectedCode    int
+		expectedMessage string
+	}{
+		{
+			name:            "status error",
+			err:             NewNotFound("Environment", "int"),
+			expectedReason:  StatusReasonNotFound,
+			expectedCode:    http.StatusNotFound,
+			expectedMessage: `Environment "int" not found`,
+		},
+		{
+			name:            "wrapped status error keeps the context",
+			err:             fmt.Errorf("failed to list releases: %w", fmt.Errorf("failed to read: %w", NewBadRequest("bad name"))),
+			expectedReason:  StatusReasonBadRequest,
+			expectedCode:    http.StatusBadRequest,
+			expectedMessage: "failed to list releases: failed to read: bad name",
+		},
+		{
+			name:            "deadline",
+			err:             fmt.Errorf("failed to scan: %w", context.DeadlineExceeded),
+			expectedReason:  StatusReasonTimeout,
+			expectedCode:    http.StatusGatewayTimeout,
+			expectedMessage: "failed to scan: context deadline exceeded",
+		},
+		{
+			name:            "plain error",
+			err:             errors.New("disk full"),
+			expectedReason:  StatusReasonInternalError,
+			expectedCode:    http.StatusInternalServerError,
+			expectedMessage: "disk full",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := StatusForError(tt.err)
+			assert.Equal(t, tt.expectedReason, actual.Reason)
+			assert.Equal(t, tt.expectedCode, actual.Code)
+			assert.Equal(t, tt.expectedMessage, actual.Message)
+			assert.Equal(t, "service-status.hcm.openshift.io/v1", actual.APIVersion)
+		})
+	}
+}
+
+func TestIsHelpers(t *testing.T) {
+	wrappedNotFound := fmt.Errorf("failed to get: %w", NewNotFound("Environment", "int"))
+	assert.True(t, IsNotFound(wrappedNotFound))
+	assert.False(t, IsBadRequest(wrappedNotFound))
+	assert.False(t, IsNotFound(errors.New("Environment \"int\" not found")), "only a StatusError has a reason")
+	assert.False(t, IsNotFound(nil))
+	assert.Equal(t, StatusReason(""), ReasonForError(nil))
+
+	assert.True(t, IsTimeout(fmt.Errorf("failed to scan: %w", context.DeadlineExceeded)))
+	assert.False(t, IsTimeout(context.Canceled))
+}
@@ -0,0 +1,252 @@
+package status
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListOptions narrows an EnvironmentReleaseList.  The zero value lists everything.
+type ListOptions struct {
+	// Environment only lists releases of this environment.
+	Environment string
+	// Since and Until bound the time in the release name, Since inclusive and Until exclusive.
+	Since *time.Time
+	Until *time.Time
+	// Component only lists releases with this component and drops the other components from each release.
+	Component string
+	// Digest only lists releases with a component image of this digest.
+	Digest string
+	// Limit is the most releases returned.  When more match, the list has a Continue token for the next page.
+	Limit int
+	// Continue is the token from the previous page.  It is only valid with the same filters.
+	Continue string
+	// Fields are the JSON names of the release fields to return, for instance name and sha.  Empty returns them all.
+	Fields []string
+}
+
+// ToQuery is the inverse of ParseListOptions.
+func (o ListOptions) ToQuery() url.Values {
+	ret := url.Values{}
+	if len(o.Environment) > 0 {
+		ret.Set("environment", o.Environment)
+	}
+	if o.Since != nil {
+		ret.Set("since", o.Since.Format(time.RFC3339))
+	}
+	if o.Until != nil {
+		ret.Set("until", o.Until.Format(time.RFC3339))
+	}
+	if len(o.Component) > 0 {
+		ret.Set("component", o.Component)
+	}
+	if len(o.Digest) > 0 {
+		ret.Set("digest", o.Digest)
+	}
+	if o.Limit > 0 {
+		ret.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if len(o.Continue) > 0 {
+		ret.Set("continue", o.Continue)
+	}
+	if len(o.Fields) > 0 {
+		ret.Set("fields", strings.Join(o.Fields, ","))
+	}
+	return ret
+}
+
+// ParseListOptions reads the list query parameters.  Malformed values are BadRequest errors.
+func ParseListOptions(query url.Values) (ListOptions, error) {
+	ret := ListOptions{
+		Environment: query.Get("environment"),
+		Component:   query.Get("component"),
+		Digest:      query.Get("digest"),
+		Continue:    query.Get("continue"),
+	}
+
+	parseTime := func(name string) (*time.Time, error) {
+		value := query.Get(name)
+		if len(value) == 0 {
+			return nil, nil
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, NewBadRequest(fmt.Sprintf("%s must be an RFC3339 time, not %q", name, value))
+		}
+		return &parsed, nil
+	}
+	var err error
+	if ret.Since, err = parseTime("since"); err != nil {
+		return ListOptions{}, err
+	}
+	if ret.Until, err = parseTime("until"); err != nil {
+		return ListOptions{}, err
+	}
+
+	if limitString := query.Get("limit"); len(limitString) > 0 {
+		ret.Limit, err = strconv.Atoi(limitString)
+		if err != nil || ret.Limit < 1 {
+			return ListOptions{}, NewBadRequest(fmt.Sprintf("limit must be a positive integer, not %q", limitString))
+		}
+	}
+
+	if fields := query.Get("fields"); len(fields) > 0 {
+		for _, field := range strings.Split(fields, ",") {
+			if field = strings.TrimSpace(field); len(field) > 0 {
+				ret.Fields = append(ret.Fields, field)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// FilterEnvironmentReleaseList returns the page of releases matching options, keeping the order of list.  The list
+// may be shared with a cache, so it is copied rather than mutated.  Fields are not applied, see
+// ProjectEnvironmentReleases.
+func FilterEnvironmentReleaseList(list *EnvironmentReleaseList, options ListOptions) (*EnvironmentReleaseList, error) {
+	ret := &EnvironmentReleaseList{
+		TypeMeta:        list.TypeMeta,
+		ResourceVersion: list.ResourceVersion,
+		Items:           []EnvironmentRelease{},
+	}
+
+	matching := []EnvironmentRelease{}
+	for _, environmentRelease := range list.Items {
+		if curr, ok := matchEnvironmentRelease(environmentRelease, options); ok {
+			matching = append(matching, curr)
+		}
+	}
+
+	start := 0
+	if len(options.Continue) > 0 {
+		lastName, err := parseListContinueToken(options.Continue)
+		if err != nil {
+			return nil, NewBadRequest(err.Error())
+		}
+		start = -1
+		for i, environmentRelease := range matching {
+			if environmentRelease.Name == lastName {
+				start = i + 1
+				break
+			}
+		}
+		if start == -1 {
+			return nil, NewExpired(fmt.Sprintf("environment release %q from the continue token is no longer listed, list again", lastName))
+		}
+	}
+
+	end := len(matching)
+	if options.Limit > 0 {
+		end = min(start+options.Limit, end)
+	}
+	ret.Items = append(ret.Items, matching[start:end]...)
+	if end < len(matching) && end > start {
+		ret.Continue = listContinueToken(matching[end-1].Name)
+	}
+	return ret, nil
+}
+
+func matchEnvironmentRelease(environmentRelease EnvironmentRelease, options ListOptions) (EnvironmentRelease, bool) {
+	if len(options.Environment) > 0 && environmentRelease.Environment != options.Environment {
+		return environmentRelease, false
+	}
+	if options.Since != nil || options.Until != nil {
+		_, releaseTime, _, ok := SplitReleaseName(environmentRelease.ReleaseName)
+		if !ok {
+			return environmentRelease, false
+		}
+		if options.Since != nil && releaseTime.Before(*options.Since) {
+			return environmentRelease, false
+		}
+		if options.Until != nil && !releaseTime.Before(*options.Until) {
+			return environmentRelease, false
+		}
+	}
+	if len(options.Component) == 0 && len(options.Digest) == 0 {
+		return environmentRelease, true
+	}
+
+	matchingComponents := map[string]*Component{}
+	for name, component := range environmentRelease.Components {
+		if len(options.Component) > 0 && component.Name != options.Component {
+			continue
+		}
+		if len(options.Digest) > 0 && component.ImageInfo.Digest != options.Digest {
+			continue
+		}
+		matchingComponents[name] = component
+	}
+	if len(matchingComponents) == 0 {
+		return environmentRelease, false
+	}
+	if len(options.Component) > 0 {
+		environmentRelease.Components = matchingComponents
+	}
+	return environmentRelease, true
+}
+
+func listContinueToken(lastName string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastName))
+}
+
+func parseListContinueToken(token string) (string, error) {
+	lastName, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(lastName) == 0 {
+		return "", fmt.Errorf("invalid continue token %q", token)
+	}
+	return string(lastName), nil
+}
+
+// environmentReleaseFieldNames are the JSON names of the EnvironmentRelease fields that can be selected.
+var environmentReleaseFieldNames = func() []string {
+	ret := []string{}
+	environmentReleaseType := reflect.TypeOf(EnvironmentRelease{})
+	for i := 0; i < environmentReleaseType.NumField(); i++ {
+		name, _, _ := strings.Cut(environmentReleaseType.Field(i).Tag.Get("json"), ",")
+		if len(name) > 0 && name != "-" {
+			ret = append(ret, name)
+		}
+	}
+	slices.Sort(ret)
+	return ret
+}()
+
+// ProjectEnvironmentReleases keeps only fields, by JSON name, of each release.  Unknown fields are a BadRequest.
+func ProjectEnvironmentReleases(environmentReleases []EnvironmentRelease, fields []string) ([]map[string]json.RawMessage, error) {
+	unknown := []string{}
+	for _, field := range fields {
+		if !slices.Contains(environmentReleaseFieldNames, field) && !slices.Contains(unknown, field) {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return nil, NewBadRequest(fmt.Sprintf("unknown fields %v, must be some of %v", unknown, environmentReleaseFieldNames))
+	}
+
+	ret := []map[string]json.RawMessage{}
+	for _, environmentRelease := range environmentReleases {
+		environmentReleaseJSON, err := json.Marshal(environmentRelease)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %q: %w", environmentRelease.Name, err)
+		}
+		allFields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(environmentReleaseJSON, &allFields); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %q: %w", environmentRelease.Name, err)
+		}
+		projected := map[string]json.RawMessage{}
+		for _, field := range fields {
+			if value, ok := allFields[field]; ok {
+				projected[field] = value
+			}
+		}
+		ret = append(ret, projected)
+	}
+	return ret, nil
+}
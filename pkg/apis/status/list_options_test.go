@@ -0,0 +1,78 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func newListOptionsTestRelease(environment, releaseTime, frontendDigest string) EnvironmentRelease {
+	releaseName := MakeReleaseName(releaseTime, "abcde")
+	return EnvironmentRelease{
+		Name:        MakeEnvironmentReleaseName(environment, releaseName),
+		ReleaseName: releaseName,
+		SHA:         "abcde",
+		Environment: environment,
+		Components: map[string]*Component{
+			"Frontend": {Name: "Frontend", ImageInfo: ContainerImage{Digest: frontendDigest}},
+			"Backend":  {Name: "Backend", ImageInfo: ContainerImage{Digest: "sha256:backend"}},
+		},
+	}
+}
+
+func TestFilterEnvironmentReleaseList(t *testing.T) {
+	list := &EnvironmentReleaseList{
+		Items: []EnvironmentRelease{
+			newListOptionsTestRelease("int", "2025-01-03T00:00:00Z", "sha256:three"),
+			newListOptionsTestRelease("int", "2025-01-02T00:00:00Z", "sha256:two"),
+			newListOptionsTestRelease("stg", "2025-01-02T00:00:00Z", "sha256:two"),
+			newListOptionsTestRelease("int", "2025-01-01T00:00:00Z", "sha256:one"),
+		},
+	}
+	names := func(list *EnvironmentReleaseList) []string {
+		ret := []string{}
+		for _, item := range list.Items {
+			ret = append(ret, item.Name)
+		}
+		return ret
+	}
+
+	filtered, err := FilterEnvironmentReleaseList(list, ListOptions{
+		Environment: "int",
+		Since:       ptr.To(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{list.Items[0].Name, list.Items[1].Name}, names(filtered))
+
+	filtered, err = FilterEnvironmentReleaseList(list, ListOptions{Component: "Frontend", Digest: "sha256:two"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{list.Items[1].Name, list.Items[2].Name}, names(filtered))
+	assert.Len(t, filtered.Items[0].Components, 1)
+	assert.Len(t, list.Items[1].Components, 2, "the listed releases must not be changed")
+
+	firstPage, err := FilterEnvironmentReleaseList(list, ListOptions{Limit: 3})
+	require.NoError(t, err)
+	assert.Len(t, firstPage.Items, 3)
+	require.NotEmpty(t, firstPage.Continue)
+	secondPage, err := FilterEnvironmentReleaseList(list, ListOptions{Limit: 3, Continue: firstPage.Continue})
+	require.NoError(t, err)
+	assert.Equal(t, []string{list.Items[3].Name}, names(secondPage))
+	assert.Empty(t, secondPage.Continue)
+
+	_, err = FilterEnvironmentReleaseList(list, ListOptions{Continue: listContinueToken("int---gone")})
+	assert.True(t, IsExpired(err))
+}
+
+func TestProjectEnvironmentReleases(t *testing.T) {
+	projected, err := ProjectEnvironmentReleases([]EnvironmentRelease{newListOptionsTestRelease("int", "2025-01-01T00:00:00Z", "sha256:one")}, []string{"name", "sha"})
+	require.NoError(t, err)
+	require.Len(t, projected, 1)
+	assert.Len(t, projected[0], 2)
+	assert.JSONEq(t, `"abcde"`, string(projected[0]["sha"]))
+
+	_, err = ProjectEnvironmentReleases(nil, []string{"bogus"})
+	assert.True(t, IsBadRequest(err))
+}
@@ -42,8 +42,28 @@ type JobRunResults struct {
 	JobName       string           `json:"jobName"`
 	OverallResult JobOverallResult `json:"overall_result"`
 	URL           string           `json:"url"`
+
+	// Attribution says how this job run was tied to the release.
+	Attribution JobRunAttribution `json:"attribution,omitempty"`
+	// TestedSHA is the ARO-HCP commit the job run exercised, when the job recorded it.
+	TestedSHA string `json:"testedSHA,omitempty"`
+
+	TestFailures    int      `json:"testFailures,omitempty"`
+	FailedTestNames []string `json:"failedTestNames,omitempty"`
+	TestFlakes      int      `json:"testFlakes,omitempty"`
+	FlakedTestNames []string `json:"flakedTestNames,omitempty"`
 }
 
+type JobRunAttribution string
+
+const (
+	// JobRunAttributionTestedCommit means the job run recorded an ARO-HCP commit contained in this release.
+	JobRunAttributionTestedCommit JobRunAttribution = "TestedCommit"
+	// JobRunAttributionWallClock means the job run only started while this release was the newest one.  It may have
+	// tested the previous release.
+	JobRunAttributionWallClock JobRunAttribution = "WallClock"
+)
+
 type JobOverallResult string
 
 const (
@@ -59,28 +79,179 @@ const (
 )
 
 type EnvironmentRelease struct {
-	TypeMeta               `json:",inline"`
-	Name                   string                     `json:"name"`
+	TypeMeta `json:",inline"`
+	Name     string `json:"name"`
+	// ResourceVersion is only set on releases sent by a watch.  It orders changes so a watch can be resumed.
+	ResourceVersion        string                     `json:"resourceVersion,omitempty"`
 	ReleaseName            string                     `json:"releaseName"`
 	SHA                    string                     `json:"sha"`
 	Environment            string                     `json:"environment"`
 	Components             map[string]*Component      `json:"components"`
 	BlockingJobRunResults  map[string][]JobRunResults `json:"blockingJobRunResults"`
 	InformingJobRunResults map[string][]JobRunResults `json:"informingJobRunResults"`
+	Health                 *ReleaseHealth             `json:"health,omitempty"`
+	TestResults            *ReleaseTestResults        `json:"testResults,omitempty"`
+	// Conditions report what went wrong reading the release.  A release whose config could not be read is still
+	// listed, without components, so it can be told apart from no release at all.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ReleaseTestResults aggregates the individual test failures of every job run attributed to a release.
+type ReleaseTestResults struct {
+	// MostFailedTests is sorted by failures, most first.  Only the top of the list is kept.
+	MostFailedTests []TestResultSummary `json:"mostFailedTests"`
+	// NewlyFailingTests failed in this release, but never in PreviousEnvironmentReleaseName.
+	NewlyFailingTests              []TestResultSummary `json:"newlyFailingTests"`
+	PreviousEnvironmentReleaseName string              `json:"previousEnvironmentReleaseName,omitempty"`
+}
+
+type TestResultSummary struct {
+	Name string `json:"name"`
+	// Failures and Flakes count job runs, not test attempts.
+	Failures int `json:"failures"`
+	Flakes   int `json:"flakes"`
+}
+
+// ReleaseHealth summarizes the CI results of an environment release.
+type ReleaseHealth struct {
+	Verdict ReleaseHealthVerdict `json:"verdict"`
+	// Reason explains the verdict in a sentence.
+	Reason string `json:"reason"`
+	// MinimumRuns is how many finished runs a blocking job variant needs before it can be trusted.
+	MinimumRuns int `json:"minimumRuns"`
+	// RequiredPassRate is the pass rate, between 0 and 1, a blocking job variant must reach.
+	RequiredPassRate float64            `json:"requiredPassRate"`
+	JobVariants      []JobVariantHealth `json:"jobVariants"`
+}
+
+type ReleaseHealthVerdict string
+
+const (
+	ReleaseHealthBlockingGreen    ReleaseHealthVerdict = "BlockingGreen"
+	ReleaseHealthBlockingRed      ReleaseHealthVerdict = "BlockingRed"
+	ReleaseHealthInsufficientData ReleaseHealthVerdict = "InsufficientData"
+)
+
+type JobVariantHealth struct {
+	Name string `json:"name"`
+	// Category is either Blocking or Informing.
+	Category string `json:"category"`
+	// Runs only counts finished runs.
+	Runs      int `json:"runs"`
+	Succeeded int `json:"succeeded"`
+	// PassRate is Succeeded/Runs, or zero when there are no runs.
+	PassRate float64 `json:"passRate"`
+}
+
+// CICoverage reports which CI jobs were seen for an environment and what happened to their results.
+type CICoverage struct {
+	TypeMeta    `json:",inline"`
+	Environment string          `json:"environment"`
+	Jobs        []CIJobCoverage `json:"jobs"`
+	// UnattributedJobRuns are runs of classified jobs that couldn't be tied to any release, usually because they are
+	// older than every listed release.
+	UnattributedJobRuns []JobRunResults `json:"unattributedJobRuns"`
+}
+
+type CIJobCoverage struct {
+	JobName string `json:"jobName"`
+	// JobVariant and Category are empty when no rule matched, in which case the results are ignored.
+	JobVariant string `json:"jobVariant,omitempty"`
+	Category   string `json:"category,omitempty"`
+
+	Runs           int `json:"runs"`
+	AttributedRuns int `json:"attributedRuns"`
+
+	// NeedsClassification flags periodic jobs no rule matched.  They are likely new and someone should decide whether
+	// they are blocking or informing.
+	NeedsClassification bool `json:"needsClassification,omitempty"`
 }
 
 type EnvironmentReleaseList struct {
 	TypeMeta `json:",inline"`
+	// ResourceVersion is the latest change the server has seen.  Watching from it sends every change made after the
+	// list, and possibly a few made before.
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// Continue is set when a limit left releases out.  Pass it back as ?continue= to get the next page.
+	Continue string               `json:"continue,omitempty"`
 	Items    []EnvironmentRelease `json:"items"`
 }
 
+type WatchEventType string
+
+const (
+	WatchEventAdded    WatchEventType = "ADDED"
+	WatchEventModified WatchEventType = "MODIFIED"
+)
+
+// EnvironmentReleaseWatchEvent is sent as the data of a server-sent event named after its Type.
+type EnvironmentReleaseWatchEvent struct {
+	Type   WatchEventType     `json:"type"`
+	Object EnvironmentRelease `json:"object"`
+}
+
 type Component struct {
-	Name                     string `json:"name"`
-	ImageInfo                ContainerImage
-	ImageCreationTime        *time.Time `json:"imageCreationTime,omitempty"`
-	RepoURL                  *string    `json:"RepoURL"`
-	SourceSHA                string     `json:"sourceSHA"`
-	PermanentURLForSourceSHA *string    `json:"permanentURLForSourceSHA,omitempty"`
+	Name                     string         `json:"name"`
+	ImageInfo                ContainerImage `json:"imageInfo"`
+	ImageCreationTime        *time.Time     `json:"imageCreationTime,omitempty"`
+	RepoURL                  *string        `json:"repoURL"`
+	SourceSHA                string         `json:"sourceSHA"`
+	PermanentURLForSourceSHA *string        `json:"permanentURLForSourceSHA,omitempty"`
+	// SourceBranches lists the branches containing SourceSHA, the master branch first when it contains it.
+	SourceBranches []string `json:"sourceBranches,omitempty"`
+	// SourceDescription places SourceSHA relative to the nearest tag and branch, for instance "v0.1.52-12-gabc1234 on release-4.18".
+	SourceDescription string `json:"sourceDescription,omitempty"`
+	// Conditions report what went wrong looking up the image.  SourceSHA is empty when it could not be read.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+type ConditionType string
+
+const (
+	// ConditionDegraded means some of the information could not be read.  What is missing is in the reason and message.
+	ConditionDegraded ConditionType = "Degraded"
+)
+
+type ConditionStatus string
+
+const (
+	ConditionTrue  ConditionStatus = "True"
+	ConditionFalse ConditionStatus = "False"
+)
+
+const (
+	// ConditionReasonConfigUnreadable means the ARO-HCP config of the release could not be read, so it has no components.
+	ConditionReasonConfigUnreadable = "ConfigUnreadable"
+	// ConditionReasonComponentsDegraded means at least one component of the release is degraded.
+	ConditionReasonComponentsDegraded = "ComponentsDegraded"
+	// ConditionReasonImagePullLocationUnknown means the registry of the component's image is not known.
+	ConditionReasonImagePullLocationUnknown = "ImagePullLocationUnknown"
+	// ConditionReasonImageInfoUnavailable means the component's image could not be pulled or inspected.
+	ConditionReasonImageInfoUnavailable = "ImageInfoUnavailable"
+)
+
+// Condition is modeled on the kube condition, without the times since releases are recomputed rather than updated.
+type Condition struct {
+	Type    ConditionType   `json:"type"`
+	Status  ConditionStatus `json:"status"`
+	Reason  string          `json:"reason"`
+	Message string          `json:"message"`
+}
+
+// FindCondition returns nil when conditions has no condition of conditionType.
+func FindCondition(conditions []Condition, conditionType ConditionType) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsConditionTrue is false when the condition is missing.
+func IsConditionTrue(conditions []Condition, conditionType ConditionType) bool {
+	condition := FindCondition(conditions, conditionType)
+	return condition != nil && condition.Status == ConditionTrue
 }
 
 type ContainerImage struct {
@@ -95,13 +266,56 @@ type EnvironmentReleaseDiff struct {
 	OtherEnvironmentReleaseName string `json:"otherEnvironmentReleaseName"`
 
 	DifferentComponents map[string]*ComponentDiff `json:"differentComponents"`
+
+	// CIComparison compares the CI pass rates of the two releases.
+	CIComparison *CIComparison `json:"ciComparison,omitempty"`
+
+	// Continue is set when a component has more changes than were returned.  Pass it back as ?continue= to get the next page.
+	Continue string `json:"continue,omitempty"`
+}
+
+// CIComparison compares pass rates of an environment release against another one using Fisher's exact test.
+type CIComparison struct {
+	// SignificanceLevel is the p-value below which a difference is reported as a regression or improvement.
+	SignificanceLevel float64              `json:"significanceLevel"`
+	JobVariants       []PassRateComparison `json:"jobVariants"`
+	// Tests only lists tests with a significant change.
+	Tests []PassRateComparison `json:"tests"`
+}
+
+type PassRateComparison struct {
+	Name string `json:"name"`
+	// Category is only set for job variants.
+	Category string `json:"category,omitempty"`
+
+	Runs           int `json:"runs"`
+	Succeeded      int `json:"succeeded"`
+	OtherRuns      int `json:"otherRuns"`
+	OtherSucceeded int `json:"otherSucceeded"`
+
+	PValue  float64           `json:"pValue"`
+	Verdict ComparisonVerdict `json:"verdict"`
 }
 
+type ComparisonVerdict string
+
+const (
+	ComparisonRegressed           ComparisonVerdict = "Regressed"
+	ComparisonImproved            ComparisonVerdict = "Improved"
+	ComparisonNoSignificantChange ComparisonVerdict = "NoSignificantChange"
+)
+
 type ComponentDiff struct {
 	Name string `json:"name"`
 
-	NumberOfChanges int               `json:"numberOfChanges"`
-	Changes         []ComponentChange `json:"changes"`
+	// NumberOfChanges counts every change between the two releases, even the ones not listed in Changes.
+	NumberOfChanges int `json:"numberOfChanges"`
+	// Truncated is set when more changes follow the ones listed in Changes.
+	Truncated bool `json:"truncated,omitempty"`
+	// SearchDepthExceeded is set when the other release wasn't found within the server's commit search depth.
+	// NumberOfChanges then only counts the changes found, there are more.
+	SearchDepthExceeded bool              `json:"searchDepthExceeded,omitempty"`
+	Changes             []ComponentChange `json:"changes"`
 }
 
 type ComponentChange struct {
@@ -116,11 +330,141 @@ type GithubPRMerge struct {
 	SHA           string   `json:"sha"`
 	ChangeSummary string   `json:"topLineCommitMessage"`
 	JIRARefs      []string `json:"jiraRefs,omitempty"`
+
+	// the following are only set when pull request enrichment is enabled.
+	Title        string   `json:"title,omitempty"`
+	Author       string   `json:"author,omitempty"`
+	MergedBy     string   `json:"mergedBy,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	LinkedIssues []string `json:"linkedIssues,omitempty"`
+	URL          string   `json:"url,omitempty"`
 }
 
 type GitlabMRMerge struct {
 	MRNumber      int32    `json:"mrNumber"`
-	SHA           string   `json:"SHA"`
+	SHA           string   `json:"sha"`
 	ChangeSummary string   `json:"topLineCommitMessage"`
 	JIRARefs      []string `json:"jiraRefs,omitempty"`
+
+	// the following are only set when merge request enrichment is enabled.
+	Title        string   `json:"title,omitempty"`
+	Author       string   `json:"author,omitempty"`
+	MergedBy     string   `json:"mergedBy,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	LinkedIssues []string `json:"linkedIssues,omitempty"`
+	URL          string   `json:"url,omitempty"`
+}
+
+type RepositoryList struct {
+	TypeMeta `json:",inline"`
+	Items    []Repository `json:"items"`
+}
+
+// Repository is the refresh state of a local git mirror.
+type Repository struct {
+	TypeMeta                `json:",inline"`
+	Name                    string     `json:"name"`
+	URL                     string     `json:"url"`
+	LastFetchTime           *time.Time `json:"lastFetchTime,omitempty"`
+	LastSuccessfulFetchTime *time.Time `json:"lastSuccessfulFetchTime,omitempty"`
+	NextFetchTime           *time.Time `json:"nextFetchTime,omitempty"`
+	LastError               string     `json:"lastError,omitempty"`
+	ConsecutiveFailures     int        `json:"consecutiveFailures"`
+}
+
+type CacheList struct {
+	TypeMeta `json:",inline"`
+	Items    []Cache `json:"items"`
+}
+
+// Cache is the state of one cached accessor method.
+type Cache struct {
+	TypeMeta `json:",inline"`
+	Name     string `json:"name"`
+	// TTL is how long a value is served before it is recomputed.  Stale values are still served while that happens.
+	TTL string `json:"ttl"`
+	// RefreshInterval is how often values are recomputed in the background.  Empty means only on request.
+	RefreshInterval string       `json:"refreshInterval,omitempty"`
+	Entries         []CacheEntry `json:"entries"`
+}
+
+type CacheEntry struct {
+	Key             string     `json:"key"`
+	LastRefreshTime *time.Time `json:"lastRefreshTime,omitempty"`
+	// Age is how long ago the served value was computed.
+	Age        string `json:"age,omitempty"`
+	Stale      bool   `json:"stale"`
+	Refreshing bool   `json:"refreshing"`
+	// LastError is from the latest refresh when it failed.  The previous value is still served.
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// ServerStatus is what the release server is serving and how its last scans went.
+type ServerStatus struct {
+	TypeMeta `json:",inline"`
+	// Ready is true once every environment has been scanned.
+	Ready           bool                    `json:"ready"`
+	Environments    []EnvironmentScanStatus `json:"environments"`
+	Repositories    []Repository            `json:"repositories"`
+	Caches          []Cache                 `json:"caches"`
+	SubsystemErrors []SubsystemError        `json:"subsystemErrors"`
+}
+
+// EnvironmentScanStatus describes the latest scan of the ARO-HCP repo for one environment.
+type EnvironmentScanStatus struct {
+	Environment string `json:"environment"`
+	// AROHCPHead is the ARO-HCP commit the last successful scan read.
+	AROHCPHead        string     `json:"aroHCPHead,omitempty"`
+	LastScanStartTime *time.Time `json:"lastScanStartTime,omitempty"`
+	LastScanDuration  string     `json:"lastScanDuration,omitempty"`
+	// LastScanError is set when the latest scan failed.  The fields describing a successful scan keep their values.
+	LastScanError  string `json:"lastScanError,omitempty"`
+	ScansCompleted int    `json:"scansCompleted"`
+	// DegradedReleases counts the releases of the last successful scan whose config could not be read.  They are
+	// listed with a Degraded condition and no components.
+	DegradedReleases         int    `json:"degradedReleases"`
+	LastDegradedReleaseError string `json:"lastDegradedReleaseError,omitempty"`
+}
+
+// SubsystemError is the latest failed call to git, podman or sippy.
+type SubsystemError struct {
+	System    string    `json:"system"`
+	Operation string    `json:"operation"`
+	Time      time.Time `json:"time"`
+	Error     string    `json:"error"`
+}
+
+type ComponentStatusList struct {
+	TypeMeta `json:",inline"`
+	Items    []ComponentStatus `json:"items"`
+}
+
+// ComponentStatus is the version of one component in the latest release of each environment.
+type ComponentStatus struct {
+	TypeMeta `json:",inline"`
+	Name     string  `json:"name"`
+	RepoURL  *string `json:"repoURL,omitempty"`
+	// Environments are in promotion order.  Environments without the component are left out.
+	Environments []ComponentEnvironmentStatus `json:"environments"`
+}
+
+type ComponentEnvironmentStatus struct {
+	Environment string `json:"environment"`
+	// EnvironmentReleaseName is the latest release of the environment, where this version came from.
+	EnvironmentReleaseName   string         `json:"environmentReleaseName"`
+	Image                    ContainerImage `json:"image"`
+	SourceSHA                string         `json:"sourceSHA,omitempty"`
+	PermanentURLForSourceSHA *string        `json:"permanentURLForSourceSHA,omitempty"`
+	// SourceDescription places SourceSHA relative to the nearest tag, like Component.SourceDescription.
+	SourceDescription string     `json:"sourceDescription,omitempty"`
+	ImageCreationTime *time.Time `json:"imageCreationTime,omitempty"`
+	// ImageAge is how old the image was when the response was sent.
+	ImageAge string `json:"imageAge,omitempty"`
+	// PreviousEnvironment is the environment releases are promoted from, if any.
+	PreviousEnvironment string `json:"previousEnvironment,omitempty"`
+	// CommitsBehindPreviousEnvironment counts the commits in the previous environment's source SHA that are not in this
+	// one.  It is unset when either source SHA is unknown or the history could not be read.
+	CommitsBehindPreviousEnvironment *int        `json:"commitsBehindPreviousEnvironment,omitempty"`
+	Conditions                       []Condition `json:"conditions,omitempty"`
 }
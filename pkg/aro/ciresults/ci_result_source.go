@@ -0,0 +1,105 @@
+package ciresults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/utils/set"
+)
+
+// JobRun is a single CI job run, independent of where it was read from.
+type JobRun struct {
+	Job           string                  `json:"job"`
+	URL           string                  `json:"url"`
+	OverallResult status.JobOverallResult `json:"overallResult"`
+	StartTime     time.Time               `json:"startTime"`
+
+	// TestedSHA is the ARO-HCP commit the job run exercised, when the source knows it.
+	TestedSHA string `json:"testedSHA,omitempty"`
+
+	TestFailures    int      `json:"testFailures,omitempty"`
+	FailedTestNames []string `json:"failedTestNames,omitempty"`
+	TestFlakes      int      `json:"testFlakes,omitempty"`
+	FlakedTestNames []string `json:"flakedTestNames,omitempty"`
+}
+
+// CIResultSource provides the CI job runs for an environment.
+type CIResultSource interface {
+	// ListJobRuns returns the job runs for environmentName that started at or after since.
+	ListJobRuns(ctx context.Context, environmentName string, since time.Time) ([]JobRun, error)
+}
+
+type multiCIResultSource struct {
+	sources []CIResultSource
+}
+
+// NewMultiCIResultSource returns the job runs of every source.  A job run reported by more than one source, matched by
+// URL, is only returned once, from the first source reporting it.  A failing source doesn't hide the others, the
+// errors are returned alongside whatever was read.
+func NewMultiCIResultSource(sources ...CIResultSource) CIResultSource {
+	return &multiCIResultSource{
+		sources: sources,
+	}
+}
+
+func (m *multiCIResultSource) ListJobRuns(ctx context.Context, environmentName string, since time.Time) ([]JobRun, error) {
+	ret := []JobRun{}
+	seenURLs := set.New[string]()
+	errs := []error{}
+	for _, source := range m.sources {
+		jobRuns, err := source.ListJobRuns(ctx, environmentName, since)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, jobRun := range jobRuns {
+			if len(jobRun.URL) > 0 && seenURLs.Has(jobRun.URL) {
+				continue
+			}
+			seenURLs.Insert(jobRun.URL)
+			ret = append(ret, jobRun)
+		}
+	}
+	return ret, errors.Join(errs...)
+}
+
+type perEnvironmentCIResultSource struct {
+	environmentToSource map[string]CIResultSource
+	defaultSource       CIResultSource
+}
+
+// NewPerEnvironmentCIResultSource reads each environment from its own source.  Environments without one use
+// defaultSource, which may be nil to return nothing.
+func NewPerEnvironmentCIResultSource(environmentToSource map[string]CIResultSource, defaultSource CIResultSource) CIResultSource {
+	return &perEnvironmentCIResultSource{
+		environmentToSource: environmentToSource,
+		defaultSource:       defaultSource,
+	}
+}
+
+func (p *perEnvironmentCIResultSource) ListJobRuns(ctx context.Context, environmentName string, since time.Time) ([]JobRun, error) {
+	source, ok := p.environmentToSource[environmentName]
+	if !ok {
+		source = p.defaultSource
+	}
+	if source == nil {
+		return nil, nil
+	}
+	jobRuns, err := source.ListJobRuns(ctx, environmentName, since)
+	if err != nil {
+		return jobRuns, fmt.Errorf("failed to list job runs for %q: %w", environmentName, err)
+	}
+	return jobRuns, nil
+}
+
+type dummyCIResultSource struct{}
+
+func NewDummyCIResultSource() CIResultSource {
+	return &dummyCIResultSource{}
+}
+
+func (d *dummyCIResultSource) ListJobRuns(ctx context.Context, environmentName string, since time.Time) ([]JobRun, error) {
+	return nil, nil
+}
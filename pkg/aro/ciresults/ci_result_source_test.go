@@ -0,0 +1,62 @@
+package ciresults
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProwArtifactsCIResultSource(t *testing.T) {
+	artifactsDir := t.TempDir()
+	writeFile := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(artifactsDir, path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(artifactsDir, path), []byte(content), 0644))
+	}
+	writeFile("int/manual-e2e/1/prowjob.json", `{
+		"spec": {"job": "manual-e2e", "refs": {"org": "Azure", "repo": "ARO-HCP", "base_sha": "abc123"}},
+		"status": {"startTime": "2025-08-05T10:00:00Z", "state": "failure", "url": "https://prow.example.com/1"}
+	}`)
+	writeFile("int/manual-e2e/1/artifacts/junit_e2e.xml", `<testsuites>
+		<testsuite name="e2e">
+			<testcase name="create cluster"></testcase>
+			<testcase name="create nodepool"><failure>boom</failure></testcase>
+			<testcase name="delete cluster"><failure>boom</failure></testcase>
+			<testcase name="delete cluster"></testcase>
+			<testcase name="upgrade"><skipped/></testcase>
+		</testsuite>
+	</testsuites>`)
+	writeFile("int/manual-e2e/2/prowjob.json", `{
+		"spec": {"job": "manual-e2e"},
+		"status": {"startTime": "2025-07-01T10:00:00Z", "state": "success", "url": "https://prow.example.com/2"}
+	}`)
+
+	source := NewMultiCIResultSource(
+		NewProwArtifactsCIResultSource(artifactsDir),
+		NewProwArtifactsCIResultSource(artifactsDir),
+	)
+	actual, err := source.ListJobRuns(context.Background(), "int", time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []JobRun{
+		{
+			Job:             "manual-e2e",
+			URL:             "https://prow.example.com/1",
+			OverallResult:   status.JobTestFailure,
+			StartTime:       time.Date(2025, 8, 5, 10, 0, 0, 0, time.UTC),
+			TestedSHA:       "abc123",
+			TestFailures:    1,
+			FailedTestNames: []string{"create nodepool"},
+			TestFlakes:      1,
+			FlakedTestNames: []string{"delete cluster"},
+		},
+	}, actual)
+
+	actual, err = source.ListJobRuns(context.Background(), "stg", time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
@@ -0,0 +1,68 @@
+package ciresults
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type jsonFeedCIResultSource struct {
+	feedURL string
+	client  *http.Client
+}
+
+// NewJSONFeedCIResultSource reads job runs from any service willing to serve them.  The feed is requested as
+// <feedURL>?environment=<environmentName>&since=<RFC3339 time> and must answer with {"items": [<JobRun>...]}.
+func NewJSONFeedCIResultSource(feedURL string) CIResultSource {
+	return &jsonFeedCIResultSource{
+		feedURL: feedURL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type jsonFeed struct {
+	Items []JobRun `json:"items"`
+}
+
+func (j *jsonFeedCIResultSource) ListJobRuns(ctx context.Context, environmentName string, since time.Time) ([]JobRun, error) {
+	currURL, err := url.Parse(j.feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feed URL: %w", err)
+	}
+	queryParams := currURL.Query()
+	queryParams.Set("environment", environmentName)
+	queryParams.Set("since", since.Format(time.RFC3339))
+	currURL.RawQuery = queryParams.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, currURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode > 299 || resp.StatusCode < 200 {
+		return nil, fmt.Errorf("request %v failed: %v: %v", currURL.String(), resp.StatusCode, string(body))
+	}
+
+	feed := &jsonFeed{}
+	if err := json.Unmarshal(body, feed); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+	ret := []JobRun{}
+	for _, jobRun := range feed.Items {
+		// the feed may ignore the since parameter.
+		if jobRun.StartTime.Before(since) {
+			continue
+		}
+		ret = append(ret, jobRun)
+	}
+	return ret, nil
+}
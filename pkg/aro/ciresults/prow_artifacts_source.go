@@ -0,0 +1,203 @@
+package ciresults
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
+)
+
+type prowArtifactsCIResultSource struct {
+	artifactsDir string
+}
+
+// NewProwArtifactsCIResultSource reads job runs from a directory of downloaded prow artifacts, for CI that sippy doesn't
+// index like manual e2e runs.  Every directory below <artifactsDir>/<environmentName> containing a prowjob.json is a
+// job run, and every JUnit XML file below it contributes test results.
+func NewProwArtifactsCIResultSource(artifactsDir string) CIResultSource {
+	return &prowArtifactsCIResultSource{
+		artifactsDir: artifactsDir,
+	}
+}
+
+// prowJob is the subset of prow's prowjob.json we use.
+type prowJob struct {
+	Spec struct {
+		Job  string    `json:"job"`
+		Refs *prowRefs `json:"refs"`
+	} `json:"spec"`
+	Status struct {
+		StartTime time.Time `json:"startTime"`
+		State     string    `json:"state"`
+		URL       string    `json:"url"`
+	} `json:"status"`
+}
+
+type prowRefs struct {
+	Org     string `json:"org"`
+	Repo    string `json:"repo"`
+	BaseSHA string `json:"base_sha"`
+	Pulls   []any  `json:"pulls"`
+}
+
+func (p *prowArtifactsCIResultSource) ListJobRuns(ctx context.Context, environmentName string, since time.Time) ([]JobRun, error) {
+	logger := klog.FromContext(ctx)
+
+	environmentDir := filepath.Join(p.artifactsDir, environmentName)
+	if _, err := os.Stat(environmentDir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	ret := []JobRun{}
+	err := filepath.WalkDir(environmentDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "prowjob.json" {
+			return nil
+		}
+		jobRun, err := readProwJobRun(filepath.Dir(path))
+		if err != nil {
+			// one bad download shouldn't hide every other run.
+			logger.Error(err, "failed to read prow job run", "path", path)
+			return nil
+		}
+		if jobRun.StartTime.Before(since) {
+			return nil
+		}
+		ret = append(ret, *jobRun)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prow artifacts in %s: %w", environmentDir, err)
+	}
+	return ret, nil
+}
+
+func readProwJobRun(jobRunDir string) (*JobRun, error) {
+	prowJobBytes, err := os.ReadFile(filepath.Join(jobRunDir, "prowjob.json"))
+	if err != nil {
+		return nil, err
+	}
+	prowJob := &prowJob{}
+	if err := json.Unmarshal(prowJobBytes, prowJob); err != nil {
+		return nil, fmt.Errorf("failed to parse prowjob.json: %w", err)
+	}
+
+	ret := &JobRun{
+		Job:           prowJob.Spec.Job,
+		URL:           prowJob.Status.URL,
+		OverallResult: overallResultFromProwState(prowJob.Status.State),
+		StartTime:     prowJob.Status.StartTime,
+	}
+	// presubmits test unmerged code, which no release contains.
+	if refs := prowJob.Spec.Refs; refs != nil && refs.Org == "Azure" && refs.Repo == "ARO-HCP" && len(refs.Pulls) == 0 {
+		ret.TestedSHA = refs.BaseSHA
+	}
+
+	failed, flaked, err := readJUnitResults(jobRunDir)
+	if err != nil {
+		return nil, err
+	}
+	ret.FailedTestNames = failed
+	ret.TestFailures = len(failed)
+	ret.FlakedTestNames = flaked
+	ret.TestFlakes = len(flaked)
+	return ret, nil
+}
+
+func overallResultFromProwState(state string) status.JobOverallResult {
+	switch state {
+	case "success":
+		return status.JobSucceeded
+	case "failure":
+		return status.JobTestFailure
+	case "aborted":
+		return status.JobAborted
+	case "error":
+		return status.JobInfrastructureFailure
+	case "pending", "triggered", "scheduling":
+		return status.JobRunning
+	default:
+		return status.JobUnknown
+	}
+}
+
+type junitTestSuites struct {
+	Suites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Suites    []junitTestSuite `xml:"testsuite"`
+	TestCases []junitTestCase  `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string    `xml:"name,attr"`
+	Failure *struct{} `xml:"failure"`
+	Error   *struct{} `xml:"error"`
+	Skipped *struct{} `xml:"skipped"`
+}
+
+// readJUnitResults returns the tests that only failed and the tests that failed and then passed in the same run.
+func readJUnitResults(jobRunDir string) ([]string, []string, error) {
+	failedTests := set.New[string]()
+	passedTests := set.New[string]()
+	err := filepath.WalkDir(jobRunDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".xml") {
+			return nil
+		}
+		junitBytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// the root is either <testsuites> or a single <testsuite>.
+		suites := &junitTestSuites{}
+		if err := xml.Unmarshal(junitBytes, suites); err != nil || len(suites.Suites) == 0 {
+			suite := junitTestSuite{}
+			if err := xml.Unmarshal(junitBytes, &suite); err != nil {
+				// not every XML artifact is JUnit.
+				return nil
+			}
+			suites.Suites = []junitTestSuite{suite}
+		}
+		for _, suite := range suites.Suites {
+			collectJUnitTestCases(suite, failedTests, passedTests)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read JUnit results in %s: %w", jobRunDir, err)
+	}
+
+	return failedTests.Difference(passedTests).SortedList(), failedTests.Intersection(passedTests).SortedList(), nil
+}
+
+func collectJUnitTestCases(suite junitTestSuite, failedTests, passedTests set.Set[string]) {
+	for _, nestedSuite := range suite.Suites {
+		collectJUnitTestCases(nestedSuite, failedTests, passedTests)
+	}
+	for _, testCase := range suite.TestCases {
+		switch {
+		case testCase.Skipped != nil:
+		case testCase.Failure != nil || testCase.Error != nil:
+			failedTests.Insert(testCase.Name)
+		default:
+			passedTests.Insert(testCase.Name)
+		}
+	}
+}
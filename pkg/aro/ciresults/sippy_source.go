@@ -0,0 +1,48 @@
+package ciresults
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/sippy"
+)
+
+type sippyCIResultSource struct {
+	client                    sippy.Client
+	environmentToSippyRelease func(environmentName string) string
+}
+
+// NewSippyCIResultSource reads job runs from sippy.  Sippy's releases are our environments, environmentToSippyRelease
+// maps one to the other.
+func NewSippyCIResultSource(client sippy.Client, environmentToSippyRelease func(environmentName string) string) CIResultSource {
+	return &sippyCIResultSource{
+		client:                    client,
+		environmentToSippyRelease: environmentToSippyRelease,
+	}
+}
+
+func (s *sippyCIResultSource) ListJobRuns(ctx context.Context, environmentName string, since time.Time) ([]JobRun, error) {
+	sippyJobRuns, err := s.client.ListJobRuns(ctx, sippy.ListJobRunsOptions{
+		Release: s.environmentToSippyRelease(environmentName),
+		Since:   since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []JobRun{}
+	for _, sippyJobRun := range sippyJobRuns {
+		ret = append(ret, JobRun{
+			Job:             sippyJobRun.Job,
+			URL:             sippyJobRun.URL,
+			OverallResult:   status.JobOverallResult(sippyJobRun.OverallResult),
+			StartTime:       time.UnixMilli(sippyJobRun.Timestamp),
+			TestFailures:    sippyJobRun.TestFailures,
+			FailedTestNames: sippyJobRun.FailedTestNames,
+			TestFlakes:      sippyJobRun.TestFlakes,
+			FlakedTestNames: sippyJobRun.FlakedTestNames,
+		})
+	}
+	return ret, nil
+}
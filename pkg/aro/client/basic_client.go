@@ -1,12 +1,16 @@
 package client
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/openshift-online/service-status/pkg/apis/status"
 )
@@ -14,10 +18,20 @@ import (
 type ReleaseClient interface {
 	ListEnvironments(ctx context.Context) (*status.EnvironmentList, error)
 	GetEnvironment(ctx context.Context, name string) (*status.Environment, error)
-	ListEnvironmentReleases(ctx context.Context) (*status.EnvironmentReleaseList, error)
-	ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error)
+	// ListEnvironmentReleases returns the releases matching options.  Fields not selected by options.Fields are empty.
+	ListEnvironmentReleases(ctx context.Context, options status.ListOptions) (*status.EnvironmentReleaseList, error)
+	// ListEnvironmentReleasesForEnvironment ignores options.Environment.
+	ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string, options status.ListOptions) (*status.EnvironmentReleaseList, error)
 	GetEnvironmentRelease(ctx context.Context, environmentName, releaseName string) (*status.EnvironmentRelease, error)
-	GetEnvironmentReleaseDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string) (*status.EnvironmentReleaseDiff, error)
+	// GetEnvironmentReleaseDiff returns one page of changes.  status.GetAllEnvironmentReleaseDiffPages follows the
+	// continue tokens.
+	GetEnvironmentReleaseDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error)
+	GetCICoverage(ctx context.Context, environmentName string) (*status.CICoverage, error)
+	ListComponents(ctx context.Context) (*status.ComponentStatusList, error)
+	GetComponent(ctx context.Context, name string) (*status.ComponentStatus, error)
+	// Watch streams changes to the environment releases of environmentName, or of every environment when it is empty,
+	// after resourceVersion.  The channel is closed when the watch ends; resume from the last resource version seen.
+	Watch(ctx context.Context, environmentName, resourceVersion string) (<-chan status.EnvironmentReleaseWatchEvent, error)
 }
 
 type basicReleaseClient struct {
@@ -39,17 +53,28 @@ func (c *basicReleaseClient) get(ctx context.Context, url string) ([]byte, error
 	}
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to do request: %w", err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, status.NewTimeout(fmt.Sprintf("failed to do request: %v", err))
+		}
+		return nil, status.NewServiceUnavailable(fmt.Sprintf("failed to do request: %v", err))
 	}
 	defer resp.Body.Close()
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode > 299 || resp.StatusCode < 200 {
-		return nil, fmt.Errorf("request failed: %v: %v", resp.StatusCode, string(body))
+		return nil, status.NewStatusErrorFromResponse(resp.StatusCode, body)
 	}
 
 	return body, nil
 }
 
+// encodeQuery is empty when there is nothing to encode, so plain requests keep their plain URLs.
+func encodeQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
 func (c *basicReleaseClient) ListEnvironments(ctx context.Context) (*status.EnvironmentList, error) {
 	url := fmt.Sprintf("%s/api/aro-hcp/environments", c.baseURL)
 	body, err := c.get(ctx, url)
@@ -78,8 +103,8 @@ func (c *basicReleaseClient) GetEnvironment(ctx context.Context, name string) (*
 	return &result, nil
 }
 
-func (c *basicReleaseClient) ListEnvironmentReleases(ctx context.Context) (*status.EnvironmentReleaseList, error) {
-	url := fmt.Sprintf("%s/api/aro-hcp/environmentreleases", c.baseURL)
+func (c *basicReleaseClient) ListEnvironmentReleases(ctx context.Context, options status.ListOptions) (*status.EnvironmentReleaseList, error) {
+	url := fmt.Sprintf("%s/api/aro-hcp/environmentreleases%s", c.baseURL, encodeQuery(options.ToQuery()))
 	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
@@ -92,8 +117,9 @@ func (c *basicReleaseClient) ListEnvironmentReleases(ctx context.Context) (*stat
 	return &result, nil
 }
 
-func (c *basicReleaseClient) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error) {
-	url := fmt.Sprintf("%s/api/aro-hcp/environments/%s/environmentreleases", c.baseURL, environment)
+func (c *basicReleaseClient) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string, options status.ListOptions) (*status.EnvironmentReleaseList, error) {
+	options.Environment = ""
+	url := fmt.Sprintf("%s/api/aro-hcp/environments/%s/environmentreleases%s", c.baseURL, url.PathEscape(environment), encodeQuery(options.ToQuery()))
 	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
@@ -120,8 +146,11 @@ func (c *basicReleaseClient) GetEnvironmentRelease(ctx context.Context, environm
 	return &result, nil
 }
 
-func (c *basicReleaseClient) GetEnvironmentReleaseDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string) (*status.EnvironmentReleaseDiff, error) {
+func (c *basicReleaseClient) GetEnvironmentReleaseDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
 	url := fmt.Sprintf("%s/api/aro-hcp/environmentreleases/%v/diff/%v", c.baseURL, url.PathEscape(environmentReleaseName), url.PathEscape(otherEnvironmentReleaseName))
+	if query := options.ToQuery(); len(query) > 0 {
+		url += "?" + query.Encode()
+	}
 	body, err := c.get(ctx, url)
 	if err != nil {
 		return nil, err
@@ -133,3 +162,102 @@ func (c *basicReleaseClient) GetEnvironmentReleaseDiff(ctx context.Context, envi
 	}
 	return &result, nil
 }
+
+func (c *basicReleaseClient) GetCICoverage(ctx context.Context, environmentName string) (*status.CICoverage, error) {
+	url := fmt.Sprintf("%s/api/aro-hcp/environments/%v/cicoverage", c.baseURL, url.PathEscape(environmentName))
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var result status.CICoverage
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *basicReleaseClient) ListComponents(ctx context.Context) (*status.ComponentStatusList, error) {
+	url := fmt.Sprintf("%s/api/aro-hcp/components", c.baseURL)
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var result status.ComponentStatusList
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *basicReleaseClient) GetComponent(ctx context.Context, name string) (*status.ComponentStatus, error) {
+	url := fmt.Sprintf("%s/api/aro-hcp/components/%v", c.baseURL, url.PathEscape(name))
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var result status.ComponentStatus
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *basicReleaseClient) Watch(ctx context.Context, environmentName, resourceVersion string) (<-chan status.EnvironmentReleaseWatchEvent, error) {
+	watchURL := fmt.Sprintf("%s/api/aro-hcp/environmentreleases", c.baseURL)
+	if len(environmentName) > 0 {
+		watchURL = fmt.Sprintf("%s/api/aro-hcp/environments/%s/environmentreleases", c.baseURL, url.PathEscape(environmentName))
+	}
+	watchURL += "?watch=true&resourceVersion=" + url.QueryEscape(resourceVersion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, watchURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, status.NewServiceUnavailable(fmt.Sprintf("failed to do request: %v", err))
+	}
+	if resp.StatusCode > 299 || resp.StatusCode < 200 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, status.NewStatusErrorFromResponse(resp.StatusCode, body)
+	}
+
+	ret := make(chan status.EnvironmentReleaseWatchEvent)
+	go func() {
+		defer close(ret)
+		defer resp.Body.Close()
+
+		// only data lines matter, the event name and id repeat what is in the data.
+		data := &bytes.Buffer{}
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				data.WriteString(strings.TrimPrefix(value, " "))
+				continue
+			}
+			if len(line) > 0 || data.Len() == 0 {
+				continue
+			}
+
+			event := status.EnvironmentReleaseWatchEvent{}
+			err := json.Unmarshal(data.Bytes(), &event)
+			data.Reset()
+			if err != nil {
+				return
+			}
+			select {
+			case ret <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ret, nil
+}
@@ -3,13 +3,29 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
-	"path/filepath"
+	"path"
 
 	"github.com/openshift-online/service-status/pkg/apis/status"
 )
 
+// The layout of a file-based API, relative to its root.  It mirrors the URLs of the server with a .json suffix.
+const (
+	FileBasedEnvironmentsPath        = "api/aro-hcp/environments.json"
+	FileBasedEnvironmentReleasesPath = "api/aro-hcp/environmentreleases.json"
+	FileBasedComponentsPath          = "api/aro-hcp/components.json"
+)
+
+func FileBasedCICoveragePath(environmentName string) string {
+	return path.Join("api/aro-hcp/environments", environmentName, "cicoverage.json")
+}
+
+func FileBasedEnvironmentReleaseDiffPath(environmentReleaseName, otherEnvironmentReleaseName string) string {
+	return path.Join("api/aro-hcp/environmentreleases", environmentReleaseName, "diff", otherEnvironmentReleaseName+".json")
+}
+
 type fileBasedReleaseClient struct {
 	fs fs.FS
 }
@@ -21,12 +37,15 @@ func NewFileSystemReleaseClient(fs fs.FS) ReleaseClient {
 }
 
 func (c *fileBasedReleaseClient) get(ctx context.Context, path string) ([]byte, error) {
-	return fs.ReadFile(c.fs, path)
+	ret, err := fs.ReadFile(c.fs, path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, status.NewNotFound("File", path)
+	}
+	return ret, err
 }
 
 func (c *fileBasedReleaseClient) ListEnvironments(ctx context.Context) (*status.EnvironmentList, error) {
-	url := filepath.Join("api/aro-hcp/environments.json")
-	body, err := c.get(ctx, url)
+	body, err := c.get(ctx, FileBasedEnvironmentsPath)
 	if err != nil {
 		return nil, err
 	}
@@ -39,27 +58,21 @@ func (c *fileBasedReleaseClient) ListEnvironments(ctx context.Context) (*status.
 }
 
 func (c *fileBasedReleaseClient) GetEnvironment(ctx context.Context, name string) (*status.Environment, error) {
-	url := filepath.Join("api/aro-hcp/environments.json", name)
-	body, err := c.get(ctx, url)
+	list, err := c.ListEnvironments(ctx)
 	if err != nil {
 		return nil, err
 	}
-	var list status.EnvironmentList
-	if err := json.Unmarshal(body, &list); err != nil {
-		return nil, err
-	}
 	for _, item := range list.Items {
 		if item.Name == name {
 			return &item, nil
 		}
 	}
 
-	return nil, fmt.Errorf("environment %v not found", name)
+	return nil, status.NewNotFound("Environment", name)
 }
 
-func (c *fileBasedReleaseClient) ListEnvironmentReleases(ctx context.Context) (*status.EnvironmentReleaseList, error) {
-	url := filepath.Join("api/aro-hcp/environmentreleases.json")
-	body, err := c.get(ctx, url)
+func (c *fileBasedReleaseClient) ListEnvironmentReleases(ctx context.Context, options status.ListOptions) (*status.EnvironmentReleaseList, error) {
+	body, err := c.get(ctx, FileBasedEnvironmentReleasesPath)
 	if err != nil {
 		return nil, err
 	}
@@ -68,26 +81,42 @@ func (c *fileBasedReleaseClient) ListEnvironmentReleases(ctx context.Context) (*
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, err
 	}
-	return &result, nil
+	return applyListOptions(&result, options)
+}
+
+// ListEnvironmentReleasesForEnvironment filters the one file of every release, the server filters by its path instead.
+func (c *fileBasedReleaseClient) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string, options status.ListOptions) (*status.EnvironmentReleaseList, error) {
+	options.Environment = environment
+	return c.ListEnvironmentReleases(ctx, options)
 }
 
-func (c *fileBasedReleaseClient) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error) {
-	url := filepath.Join("api/aro-hcp/environmentreleases.json")
-	body, err := c.get(ctx, url)
+// applyListOptions filters like the server does.  Fields that weren't selected are left empty.
+func applyListOptions(environmentReleases *status.EnvironmentReleaseList, options status.ListOptions) (*status.EnvironmentReleaseList, error) {
+	ret, err := status.FilterEnvironmentReleaseList(environmentReleases, options)
 	if err != nil {
 		return nil, err
 	}
+	if len(options.Fields) == 0 {
+		return ret, nil
+	}
 
-	var result status.EnvironmentReleaseList
-	if err := json.Unmarshal(body, &result); err != nil {
+	projectedItems, err := status.ProjectEnvironmentReleases(ret.Items, options.Fields)
+	if err != nil {
 		return nil, err
 	}
-	return &result, nil
+	projectedJSON, err := json.Marshal(projectedItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal selected fields: %w", err)
+	}
+	ret.Items = []status.EnvironmentRelease{}
+	if err := json.Unmarshal(projectedJSON, &ret.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal selected fields: %w", err)
+	}
+	return ret, nil
 }
 
 func (c *fileBasedReleaseClient) GetEnvironmentRelease(ctx context.Context, environmentName, releaseName string) (*status.EnvironmentRelease, error) {
-	url := filepath.Join("api/aro-hcp/environmentreleases.json")
-	body, err := c.get(ctx, url)
+	body, err := c.get(ctx, FileBasedEnvironmentReleasesPath)
 	if err != nil {
 		return nil, err
 	}
@@ -101,9 +130,89 @@ func (c *fileBasedReleaseClient) GetEnvironmentRelease(ctx context.Context, envi
 		}
 	}
 
-	return nil, fmt.Errorf("environmentrelease %v not found", fmt.Sprintf("%v---%v", environmentName, releaseName))
+	return nil, status.NewNotFound("EnvironmentRelease", fmt.Sprintf("%v---%v", environmentName, releaseName))
 }
 
-func (c *fileBasedReleaseClient) GetEnvironmentReleaseDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string) (*status.EnvironmentReleaseDiff, error) {
-	return nil, fmt.Errorf("not implemented")
+// GetEnvironmentReleaseDiff can only page through the changes the file lists.
+func (c *fileBasedReleaseClient) GetEnvironmentReleaseDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+	body, err := c.get(ctx, FileBasedEnvironmentReleaseDiffPath(environmentReleaseName, otherEnvironmentReleaseName))
+	if err != nil {
+		return nil, err
+	}
+
+	var result status.EnvironmentReleaseDiff
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return status.PageEnvironmentReleaseDiff(&result, options)
+}
+
+func (c *fileBasedReleaseClient) GetCICoverage(ctx context.Context, environmentName string) (*status.CICoverage, error) {
+	body, err := c.get(ctx, FileBasedCICoveragePath(environmentName))
+	if err != nil {
+		return nil, err
+	}
+
+	var result status.CICoverage
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *fileBasedReleaseClient) ListComponents(ctx context.Context) (*status.ComponentStatusList, error) {
+	body, err := c.get(ctx, FileBasedComponentsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var result status.ComponentStatusList
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *fileBasedReleaseClient) GetComponent(ctx context.Context, name string) (*status.ComponentStatus, error) {
+	list, err := c.ListComponents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list.Items {
+		if item.Name == name {
+			return &item, nil
+		}
+	}
+
+	return nil, status.NewNotFound("Component", name)
+}
+
+// Watch sends every release in the files as ADDED.  The files never change, so nothing follows until ctx is done.
+func (c *fileBasedReleaseClient) Watch(ctx context.Context, environmentName, resourceVersion string) (<-chan status.EnvironmentReleaseWatchEvent, error) {
+	var environmentReleases *status.EnvironmentReleaseList
+	var err error
+	if len(environmentName) > 0 {
+		environmentReleases, err = c.ListEnvironmentReleasesForEnvironment(ctx, environmentName, status.ListOptions{})
+	} else {
+		environmentReleases, err = c.ListEnvironmentReleases(ctx, status.ListOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(chan status.EnvironmentReleaseWatchEvent)
+	go func() {
+		defer close(ret)
+		if len(resourceVersion) == 0 || resourceVersion == "0" {
+			for _, environmentRelease := range environmentReleases.Items {
+				select {
+				case ret <- status.EnvironmentReleaseWatchEvent{Type: status.WatchEventAdded, Object: environmentRelease}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		<-ctx.Done()
+	}()
+	return ret, nil
 }
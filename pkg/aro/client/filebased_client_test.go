@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBasedListEnvironmentReleasesForEnvironment(t *testing.T) {
+	releaseClient := NewFileSystemReleaseClient(fstest.MapFS{
+		FileBasedEnvironmentReleasesPath: {Data: []byte(`{"items": [
+			{"name": "int---2025-01-02T00:00:00Z-bbbbb", "releaseName": "2025-01-02T00:00:00Z-bbbbb", "environment": "int"},
+			{"name": "stg---2025-01-02T00:00:00Z-bbbbb", "releaseName": "2025-01-02T00:00:00Z-bbbbb", "environment": "stg"},
+			{"name": "int---2025-01-01T00:00:00Z-aaaaa", "releaseName": "2025-01-01T00:00:00Z-aaaaa", "environment": "int"}
+		]}`)},
+	})
+	names := func(list *status.EnvironmentReleaseList) []string {
+		ret := []string{}
+		for _, item := range list.Items {
+			ret = append(ret, item.Name)
+		}
+		return ret
+	}
+
+	// like the server, the environment in the options is ignored.
+	list, err := releaseClient.ListEnvironmentReleasesForEnvironment(context.Background(), "int", status.ListOptions{Environment: "stg"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"int---2025-01-02T00:00:00Z-bbbbb", "int---2025-01-01T00:00:00Z-aaaaa"}, names(list))
+
+	list, err = releaseClient.ListEnvironmentReleasesForEnvironment(context.Background(), "int", status.ListOptions{Limit: 1, Fields: []string{"name"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"int---2025-01-02T00:00:00Z-bbbbb"}, names(list))
+	assert.Empty(t, list.Items[0].Environment)
+	assert.NotEmpty(t, list.Continue)
+
+	list, err = releaseClient.ListEnvironmentReleasesForEnvironment(context.Background(), "prod", status.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is read from the --notification-config file.
+type Config struct {
+	// ExternalURL is where the website is served.  Events link to release summary pages under it.
+	ExternalURL string `json:"externalURL,omitempty"`
+	// StateFile records which events were sent, so a restart doesn't send them again.  Without it, events that are
+	// firing at startup aren't sent.
+	StateFile string          `json:"stateFile,omitempty"`
+	Webhooks  []WebhookConfig `json:"webhooks"`
+}
+
+type WebhookConfig struct {
+	Name string `json:"name"`
+	// URL or URLFile is required.  Slack webhook URLs are secrets, so URLFile is usually better.
+	URL     string `json:"url,omitempty"`
+	URLFile string `json:"urlFile,omitempty"`
+
+	Format WebhookFormat `json:"format,omitempty"`
+	// Template is a text/template executed with an Event to produce the payload.  It replaces the default for
+	// Format.  The json function marshals its argument.
+	Template string `json:"template,omitempty"`
+
+	// Events and Environments limit what is sent.  Empty means everything.
+	Events       []EventType `json:"events,omitempty"`
+	Environments []string    `json:"environments,omitempty"`
+}
+
+// ReadConfig reads the config file and builds its webhooks.
+func ReadConfig(configFile string, clock clock.Clock) (*Config, []Webhook, error) {
+	configBytes, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read notification config: %w", err)
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(configBytes, config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse notification config %q: %w", configFile, err)
+	}
+
+	webhooks := []Webhook{}
+	for _, webhookConfig := range config.Webhooks {
+		if len(webhookConfig.Format) == 0 {
+			webhookConfig.Format = WebhookFormatJSON
+		}
+		url := webhookConfig.URL
+		if len(webhookConfig.URLFile) > 0 {
+			urlBytes, err := os.ReadFile(webhookConfig.URLFile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("webhook %q: failed to read url file: %w", webhookConfig.Name, err)
+			}
+			url = strings.TrimSpace(string(urlBytes))
+		}
+		if len(url) == 0 {
+			return nil, nil, fmt.Errorf("webhook %q: one of url and urlFile must be specified", webhookConfig.Name)
+		}
+
+		webhook, err := NewWebhook(webhookConfig, url, clock)
+		if err != nil {
+			return nil, nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return config, webhooks, nil
+}
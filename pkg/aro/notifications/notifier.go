@@ -0,0 +1,278 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/set"
+)
+
+// Notifier checks the environment releases on an interval and sends webhooks for anything new.
+type Notifier struct {
+	accessor    release_inspection.ReleaseAccessor
+	webhooks    []Webhook
+	externalURL string
+	// stateFile keeps what was sent across restarts.  Without it, whatever fires at startup is treated as sent.
+	stateFile string
+	interval  time.Duration
+	clock     clock.Clock
+
+	// state is nil until the first check, or until it is read from stateFile.
+	state *notifierState
+}
+
+// notifierState only holds what is still listed or still firing, so it doesn't grow with uptime.
+type notifierState struct {
+	// BaselineEnvironmentReleases were listed when notifications started.  Every other release is announced.
+	BaselineEnvironmentReleases []string `json:"baselineEnvironmentReleases"`
+	// Sent maps the key of each firing event to the webhooks that were sent it.
+	Sent map[string][]string `json:"sent"`
+}
+
+func NewNotifier(accessor release_inspection.ReleaseAccessor, webhooks []Webhook, externalURL, stateFile string, interval time.Duration, clock clock.Clock) *Notifier {
+	return &Notifier{
+		accessor:    accessor,
+		webhooks:    webhooks,
+		externalURL: strings.TrimSuffix(externalURL, "/"),
+		stateFile:   stateFile,
+		interval:    interval,
+		clock:       clock,
+	}
+}
+
+// Run checks immediately and then on the configured interval until the context is done.
+func (n *Notifier) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting notifier", "interval", n.interval, "webhookCount", len(n.webhooks), "stateFile", n.stateFile)
+
+	if err := n.readState(); err != nil {
+		logger.Error(err, "failed to read notifier state, starting over")
+	}
+
+	for {
+		if err := n.check(ctx); err != nil {
+			logger.Error(err, "failed to check for notifications")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.clock.After(n.interval):
+		}
+	}
+}
+
+func (n *Notifier) check(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+
+	environments, err := n.accessor.ListEnvironments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list environments: %w", err)
+	}
+	environmentToEnvironmentReleases := map[string]*status.EnvironmentReleaseList{}
+	for _, environment := range environments {
+		environmentReleases, err := n.accessor.ListEnvironmentReleasesForEnvironment(ctx, environment)
+		if err != nil {
+			return fmt.Errorf("failed to list environment releases for %q: %w", environment, err)
+		}
+		environmentToEnvironmentReleases[environment] = environmentReleases
+	}
+
+	firstCheck := n.state == nil
+	if firstCheck {
+		n.state = &notifierState{
+			BaselineEnvironmentReleases: environmentReleaseNames(environmentToEnvironmentReleases).SortedList(),
+			Sent:                        map[string][]string{},
+		}
+	}
+
+	events := n.newEnvironmentReleaseEvents(environmentToEnvironmentReleases)
+	events = append(events, DetectEvents(n.clock.Now(), environmentToEnvironmentReleases)...)
+	for i := range events {
+		events[i].URL = n.environmentReleaseURL(events[i].EnvironmentRelease)
+	}
+
+	// events that stopped firing are forgotten, so they are sent again if they fire again.
+	sent := map[string][]string{}
+	for _, event := range events {
+		sent[event.Key] = n.state.Sent[event.Key]
+	}
+	n.state.Sent = sent
+
+	// failed deliveries aren't recorded, so they are tried again on the next check.
+	for _, event := range events {
+		for _, webhook := range n.webhooks {
+			if slices.Contains(n.state.Sent[event.Key], webhook.Name()) || !webhook.Wants(event) {
+				continue
+			}
+			if !firstCheck {
+				if err := webhook.Send(ctx, event); err != nil {
+					logger.Error(err, "failed to send notification", "webhook", webhook.Name(), "key", event.Key)
+					continue
+				}
+			}
+			n.state.Sent[event.Key] = append(n.state.Sent[event.Key], webhook.Name())
+		}
+	}
+
+	// releases that aged out of the lists can't be announced anymore.
+	n.state.BaselineEnvironmentReleases = set.New(n.state.BaselineEnvironmentReleases...).Intersection(environmentReleaseNames(environmentToEnvironmentReleases)).SortedList()
+
+	return n.writeState()
+}
+
+// newEnvironmentReleaseEvents fire for every listed release that isn't in the baseline.
+func (n *Notifier) newEnvironmentReleaseEvents(environmentToEnvironmentReleases map[string]*status.EnvironmentReleaseList) []Event {
+	baseline := set.New(n.state.BaselineEnvironmentReleases...)
+
+	ret := []Event{}
+	for _, environment := range set.KeySet(environmentToEnvironmentReleases).SortedList() {
+		for _, environmentRelease := range environmentToEnvironmentReleases[environment].Items {
+			if baseline.Has(environmentRelease.Name) {
+				continue
+			}
+			ret = append(ret, Event{
+				Type:               EventNewEnvironmentRelease,
+				Key:                fmt.Sprintf("%s/%s", EventNewEnvironmentRelease, environmentRelease.Name),
+				Environment:        environment,
+				EnvironmentRelease: environmentRelease.Name,
+				Message:            fmt.Sprintf("%s has a new release %s.", environment, environmentRelease.ReleaseName),
+				Time:               n.clock.Now(),
+			})
+		}
+	}
+	return ret
+}
+
+func environmentReleaseNames(environmentToEnvironmentReleases map[string]*status.EnvironmentReleaseList) set.Set[string] {
+	ret := set.New[string]()
+	for _, environmentReleases := range environmentToEnvironmentReleases {
+		for _, environmentRelease := range environmentReleases.Items {
+			ret.Insert(environmentRelease.Name)
+		}
+	}
+	return ret
+}
+
+func (n *Notifier) readState() error {
+	if len(n.stateFile) == 0 {
+		return nil
+	}
+	stateBytes, err := os.ReadFile(n.stateFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", n.stateFile, err)
+	}
+	state := &notifierState{}
+	if err := json.Unmarshal(stateBytes, state); err != nil {
+		return fmt.Errorf("failed to parse %q: %w", n.stateFile, err)
+	}
+	if state.Sent == nil {
+		state.Sent = map[string][]string{}
+	}
+	n.state = state
+	return nil
+}
+
+// writeState replaces the file by renaming, so a crash doesn't leave it half written.
+func (n *Notifier) writeState() error {
+	if len(n.stateFile) == 0 {
+		return nil
+	}
+	stateBytes, err := json.Marshal(n.state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notifier state: %w", err)
+	}
+	tmpFile := n.stateFile + ".tmp"
+	if err := os.WriteFile(tmpFile, stateBytes, 0644); err != nil {
+		return fmt.Errorf("failed to write %q: %w", tmpFile, err)
+	}
+	if err := os.Rename(tmpFile, n.stateFile); err != nil {
+		return fmt.Errorf("failed to replace %q: %w", n.stateFile, err)
+	}
+	return nil
+}
+
+func (n *Notifier) environmentReleaseURL(environmentReleaseName string) string {
+	if len(n.externalURL) == 0 || len(environmentReleaseName) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/http/aro-hcp/environmentreleases/%s/summary.html", n.externalURL, url.PathEscape(environmentReleaseName))
+}
+
+// DetectEvents finds the conditions the summary page warns about in the latest release of each environment, plus
+// blocking CI turning red.  environmentToEnvironmentReleases lists releases newest first.
+func DetectEvents(now time.Time, environmentToEnvironmentReleases map[string]*status.EnvironmentReleaseList) []Event {
+	ret := []Event{}
+	for _, environment := range set.KeySet(environmentToEnvironmentReleases).SortedList() {
+		environmentReleases := environmentToEnvironmentReleases[environment]
+		if len(environmentReleases.Items) == 0 {
+			continue
+		}
+		latest := &environmentReleases.Items[0]
+
+		if previousEnvironment, ok := release_inspection.EnvironmentToPreviousEnvironment[environment]; ok && environmentToEnvironmentReleases[previousEnvironment] != nil {
+			closestRelease, changedComponents := release_inspection.FindMatchingEnvironmentRelease(environmentToEnvironmentReleases[previousEnvironment], latest)
+			message := ""
+			switch {
+			case closestRelease == nil:
+				message = fmt.Sprintf("%s was never tested in %s. No release could be compared with it.", latest.Name, previousEnvironment)
+			case len(changedComponents) > 0:
+				message = fmt.Sprintf("%s was never tested in %s. Closest release is %s which differs by %s.",
+					latest.Name, previousEnvironment, closestRelease.Name, strings.Join(changedComponents.SortedList(), ", "))
+			}
+			if len(message) > 0 {
+				ret = append(ret, Event{
+					Type:               EventUntestedEnvironmentRelease,
+					Key:                fmt.Sprintf("%s/%s", EventUntestedEnvironmentRelease, latest.Name),
+					Environment:        environment,
+					EnvironmentRelease: latest.Name,
+					Message:            message,
+					Time:               now,
+				})
+			}
+		}
+
+		// the summary page only checks image age in int, later environments get the same images later.
+		if environment == "int" {
+			for _, staleComponent := range release_inspection.StaleComponents(now, latest) {
+				ret = append(ret, Event{
+					Type:               EventStaleComponent,
+					Key:                fmt.Sprintf("%s/%s/%s", EventStaleComponent, staleComponent.Name, staleComponent.ImageCreationTime.UTC().Format(time.RFC3339)),
+					Environment:        environment,
+					EnvironmentRelease: latest.Name,
+					Component:          staleComponent.Name,
+					Message: fmt.Sprintf("%s needs to be updated.  It is about %d days old and should be updated every %d days.",
+						staleComponent.Name, staleComponent.Age/(24*time.Hour), staleComponent.LatencyThreshold/(24*time.Hour)),
+					Time: now,
+				})
+			}
+		}
+
+		if latest.Health != nil && latest.Health.Verdict == status.ReleaseHealthBlockingRed {
+			ret = append(ret, Event{
+				Type:               EventBlockingCIRed,
+				Key:                fmt.Sprintf("%s/%s", EventBlockingCIRed, latest.Name),
+				Environment:        environment,
+				EnvironmentRelease: latest.Name,
+				Message:            fmt.Sprintf("Blocking CI is red for %s. %s", latest.Name, latest.Health.Reason),
+				Time:               now,
+			})
+		}
+	}
+	return ret
+}
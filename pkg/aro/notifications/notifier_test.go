@@ -0,0 +1,199 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+)
+
+func newTestEnvironmentRelease(environment, releaseName, backendDigest string) status.EnvironmentRelease {
+	return status.EnvironmentRelease{
+		Name:        environment + "---" + releaseName,
+		ReleaseName: releaseName,
+		Environment: environment,
+		Components: map[string]*status.Component{
+			"Backend": {Name: "Backend", ImageInfo: status.ContainerImage{Digest: backendDigest}},
+		},
+	}
+}
+
+func newTestEnvironmentReleaseList(items ...status.EnvironmentRelease) *status.EnvironmentReleaseList {
+	return &status.EnvironmentReleaseList{Items: items}
+}
+
+func TestDetectEvents(t *testing.T) {
+	now := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	staleRelease := newTestEnvironmentRelease("int", "stale", "sha256:a")
+	staleRelease.Components["Backend"].ImageCreationTime = ptr.To(now.Add(-30 * 24 * time.Hour))
+	redRelease := newTestEnvironmentRelease("int", "red", "sha256:a")
+	redRelease.Health = &status.ReleaseHealth{Verdict: status.ReleaseHealthBlockingRed, Reason: "e2e failed"}
+	unreadableIntRelease := status.EnvironmentRelease{
+		Name:        "int---unreadable",
+		Environment: "int",
+		Components:  map[string]*status.Component{},
+		Conditions: []status.Condition{
+			{Type: status.ConditionDegraded, Status: status.ConditionTrue, Reason: status.ConditionReasonConfigUnreadable},
+		},
+	}
+
+	tests := []struct {
+		name                             string
+		environmentToEnvironmentReleases map[string]*status.EnvironmentReleaseList
+		expectedKeys                     []string
+		expectedMessages                 []string
+	}{
+		{
+			name: "stg release tested in int",
+			environmentToEnvironmentReleases: map[string]*status.EnvironmentReleaseList{
+				"int": newTestEnvironmentReleaseList(newTestEnvironmentRelease("int", "2", "sha256:b"), newTestEnvironmentRelease("int", "1", "sha256:a")),
+				"stg": newTestEnvironmentReleaseList(newTestEnvironmentRelease("stg", "1", "sha256:a")),
+			},
+			expectedKeys: []string{},
+		},
+		{
+			name: "stg release never tested in int",
+			environmentToEnvironmentReleases: map[string]*status.EnvironmentReleaseList{
+				"int": newTestEnvironmentReleaseList(newTestEnvironmentRelease("int", "1", "sha256:a")),
+				"stg": newTestEnvironmentReleaseList(newTestEnvironmentRelease("stg", "1", "sha256:c")),
+			},
+			expectedKeys:     []string{"UntestedEnvironmentRelease/stg---1"},
+			expectedMessages: []string{"stg---1 was never tested in int. Closest release is int---1 which differs by Backend."},
+		},
+		{
+			name: "int only has unreadable releases",
+			environmentToEnvironmentReleases: map[string]*status.EnvironmentReleaseList{
+				"int": newTestEnvironmentReleaseList(unreadableIntRelease),
+				"stg": newTestEnvironmentReleaseList(newTestEnvironmentRelease("stg", "1", "sha256:a")),
+			},
+			expectedKeys:     []string{"UntestedEnvironmentRelease/stg---1"},
+			expectedMessages: []string{"stg---1 was never tested in int. No release could be compared with it."},
+		},
+		{
+			name: "stale component in int",
+			environmentToEnvironmentReleases: map[string]*status.EnvironmentReleaseList{
+				"int": newTestEnvironmentReleaseList(staleRelease),
+			},
+			expectedKeys: []string{"StaleComponent/Backend/2025-07-06T00:00:00Z"},
+		},
+		{
+			name: "stale component outside int",
+			environmentToEnvironmentReleases: map[string]*status.EnvironmentReleaseList{
+				"prod": newTestEnvironmentReleaseList(staleRelease),
+			},
+			expectedKeys: []string{},
+		},
+		{
+			name: "blocking CI red",
+			environmentToEnvironmentReleases: map[string]*status.EnvironmentReleaseList{
+				"int": newTestEnvironmentReleaseList(redRelease),
+			},
+			expectedKeys:     []string{"BlockingCIRed/int---red"},
+			expectedMessages: []string{"Blocking CI is red for int---red. e2e failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := DetectEvents(now, tt.environmentToEnvironmentReleases)
+			keys := []string{}
+			messages := []string{}
+			for _, event := range events {
+				keys = append(keys, event.Key)
+				messages = append(messages, event.Message)
+			}
+			assert.Equal(t, tt.expectedKeys, keys)
+			if tt.expectedMessages != nil {
+				assert.Equal(t, tt.expectedMessages, messages)
+			}
+		})
+	}
+}
+
+func TestNotifierDedup(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := clocktesting.NewFakeClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	redRelease := newTestEnvironmentRelease("int", "red", "sha256:a")
+	redRelease.Health = &status.ReleaseHealth{Verdict: status.ReleaseHealthBlockingRed}
+	accessor := &fakeNotifierAccessor{environmentToEnvironmentReleases: map[string]*status.EnvironmentReleaseList{
+		"int": newTestEnvironmentReleaseList(redRelease),
+	}}
+	recorder := &recordingWebhook{}
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	newNotifier := func() *Notifier {
+		notifier := NewNotifier(accessor, []Webhook{recorder}, "", stateFile, time.Minute, fakeClock)
+		require.NoError(t, notifier.readState())
+		return notifier
+	}
+
+	// what fires when notifications first start isn't sent.
+	notifier := newNotifier()
+	require.NoError(t, notifier.check(ctx))
+	assert.Empty(t, recorder.sentKeys)
+
+	// a new release is sent once, a failed delivery is retried.
+	accessor.environmentToEnvironmentReleases["int"] = newTestEnvironmentReleaseList(newTestEnvironmentRelease("int", "green", "sha256:b"), redRelease)
+	recorder.failures = 1
+	require.NoError(t, notifier.check(ctx))
+	assert.Empty(t, recorder.sentKeys)
+	require.NoError(t, notifier.check(ctx))
+	require.NoError(t, notifier.check(ctx))
+	assert.Equal(t, []string{"NewEnvironmentRelease/int---green"}, recorder.sentKeys)
+
+	// a restart reads what was sent instead of sending it again.
+	notifier = newNotifier()
+	require.NoError(t, notifier.check(ctx))
+	assert.Equal(t, []string{"NewEnvironmentRelease/int---green"}, recorder.sentKeys)
+
+	// keys of events that stopped firing are dropped, so the event is sent again when it fires again.
+	redGreenRelease := redRelease
+	redGreenRelease.Health = nil
+	accessor.environmentToEnvironmentReleases["int"] = newTestEnvironmentReleaseList(redGreenRelease)
+	require.NoError(t, notifier.check(ctx))
+	assert.Empty(t, notifier.state.Sent)
+	assert.Equal(t, []string{"int---red"}, notifier.state.BaselineEnvironmentReleases)
+	accessor.environmentToEnvironmentReleases["int"] = newTestEnvironmentReleaseList(redRelease)
+	require.NoError(t, notifier.check(ctx))
+	assert.Equal(t, []string{"NewEnvironmentRelease/int---green", "BlockingCIRed/int---red"}, recorder.sentKeys)
+}
+
+type fakeNotifierAccessor struct {
+	release_inspection.ReleaseAccessor
+	environmentToEnvironmentReleases map[string]*status.EnvironmentReleaseList
+}
+
+func (a *fakeNotifierAccessor) ListEnvironments(ctx context.Context) ([]string, error) {
+	ret := []string{}
+	for environment := range a.environmentToEnvironmentReleases {
+		ret = append(ret, environment)
+	}
+	return ret, nil
+}
+
+func (a *fakeNotifierAccessor) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error) {
+	return a.environmentToEnvironmentReleases[environment], nil
+}
+
+type recordingWebhook struct {
+	failures int
+	sentKeys []string
+}
+
+func (w *recordingWebhook) Name() string           { return "recording" }
+func (w *recordingWebhook) Wants(event Event) bool { return true }
+func (w *recordingWebhook) Send(ctx context.Context, event Event) error {
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("unavailable")
+	}
+	w.sentKeys = append(w.sentKeys, event.Key)
+	return nil
+}
@@ -0,0 +1,28 @@
+package notifications
+
+import (
+	"time"
+)
+
+type EventType string
+
+const (
+	EventNewEnvironmentRelease      EventType = "NewEnvironmentRelease"
+	EventUntestedEnvironmentRelease EventType = "UntestedEnvironmentRelease"
+	EventStaleComponent             EventType = "StaleComponent"
+	EventBlockingCIRed              EventType = "BlockingCIRed"
+)
+
+// Event is the data every payload template is executed with.
+type Event struct {
+	Type EventType `json:"type"`
+	// Key identifies the condition.  Each webhook is sent an event for a key at most once.
+	Key                string `json:"key"`
+	Environment        string `json:"environment"`
+	EnvironmentRelease string `json:"environmentRelease,omitempty"`
+	Component          string `json:"component,omitempty"`
+	Message            string `json:"message"`
+	// URL links to the summary page of the release when the external URL is configured.
+	URL  string    `json:"url,omitempty"`
+	Time time.Time `json:"time"`
+}
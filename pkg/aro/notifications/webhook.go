@@ -0,0 +1,173 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"text/template"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+type WebhookFormat string
+
+const (
+	WebhookFormatSlack WebhookFormat = "slack"
+	WebhookFormatJSON  WebhookFormat = "json"
+)
+
+// defaultTemplates are used when a webhook doesn't configure its own template.
+var defaultTemplates = map[WebhookFormat]string{
+	WebhookFormatSlack: `{"text": {{ if .URL }}{{ json (printf "*%s*: %s <%s|details>" .Type .Message .URL) }}{{ else }}{{ json (printf "*%s*: %s" .Type .Message) }}{{ end }}}`,
+	WebhookFormatJSON:  `{{ json . }}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(obj any) (string, error) {
+		ret, err := json.Marshal(obj)
+		return string(ret), err
+	},
+}
+
+// Webhook posts events matching its filters to a URL.
+type Webhook interface {
+	Name() string
+	Wants(event Event) bool
+	Send(ctx context.Context, event Event) error
+}
+
+type webhook struct {
+	config     WebhookConfig
+	url        string
+	template   *template.Template
+	httpClient *http.Client
+
+	maxRetries     int
+	initialBackoff time.Duration
+	clock          clock.Clock
+}
+
+// NewWebhook validates the config and parses its template.  url is passed separately because it may have been read
+// from a file.
+func NewWebhook(config WebhookConfig, url string, clock clock.Clock) (Webhook, error) {
+	templateText := config.Template
+	if len(templateText) == 0 {
+		var ok bool
+		templateText, ok = defaultTemplates[config.Format]
+		if !ok {
+			return nil, fmt.Errorf("webhook %q: format must be one of %q or %q, not %q", config.Name, WebhookFormatSlack, WebhookFormatJSON, config.Format)
+		}
+	}
+	payloadTemplate, err := template.New(config.Name).Funcs(templateFuncs).Parse(templateText)
+	if err != nil {
+		return nil, fmt.Errorf("webhook %q: failed to parse template: %w", config.Name, err)
+	}
+
+	return &webhook{
+		config:         config,
+		url:            url,
+		template:       payloadTemplate,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		maxRetries:     4,
+		initialBackoff: time.Second,
+		clock:          clock,
+	}, nil
+}
+
+func (w *webhook) Name() string {
+	return w.config.Name
+}
+
+func (w *webhook) Wants(event Event) bool {
+	if len(w.config.Events) > 0 && !slices.Contains(w.config.Events, event.Type) {
+		return false
+	}
+	if len(w.config.Environments) > 0 && !slices.Contains(w.config.Environments, event.Environment) {
+		return false
+	}
+	return true
+}
+
+// Send posts the payload, retrying server errors and rate limiting with a doubling backoff.
+func (w *webhook) Send(ctx context.Context, event Event) error {
+	logger := klog.FromContext(ctx)
+
+	payload := &bytes.Buffer{}
+	if err := w.template.Execute(payload, event); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	backoff := w.initialBackoff
+	for attempt := 0; ; attempt++ {
+		err := w.post(ctx, payload.Bytes())
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= w.maxRetries {
+			return err
+		}
+
+		logger.Info("Retrying webhook", "webhook", w.config.Name, "attempt", attempt+1, "backoff", backoff, "err", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.clock.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// permanentError is returned for responses that won't succeed on retry.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// post leaves the URL out of its errors, slack webhook URLs are secrets.
+func (w *webhook) post(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return &permanentError{err: fmt.Errorf("webhook %q: failed to create request: %w", w.config.Name, withoutURL(err))}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %q: failed to do request: %w", w.config.Name, withoutURL(err))
+	}
+	defer response.Body.Close()
+	body, _ := io.ReadAll(response.Body)
+
+	switch {
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return fmt.Errorf("webhook %q failed (status=%d)", w.config.Name, response.StatusCode)
+	case response.StatusCode < 200 || response.StatusCode > 299:
+		return &permanentError{err: fmt.Errorf("webhook %q failed (status=%d): %v", w.config.Name, response.StatusCode, string(body))}
+	}
+	return nil
+}
+
+// withoutURL drops the URL that *url.Error adds to its message.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
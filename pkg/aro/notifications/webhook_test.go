@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/clock"
+)
+
+func TestWebhookSendSlack(t *testing.T) {
+	requests := 0
+	payloads := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		payloads = append(payloads, string(body))
+		// the first attempt fails, the retry must succeed.
+		if requests == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	slackWebhook, err := NewWebhook(WebhookConfig{Name: "releases", Format: WebhookFormatSlack, Environments: []string{"prod"}}, server.URL, clock.RealClock{})
+	require.NoError(t, err)
+	slackWebhook.(*webhook).initialBackoff = time.Millisecond
+
+	event := Event{
+		Type:               EventNewEnvironmentRelease,
+		Environment:        "prod",
+		EnvironmentRelease: "prod---abc",
+		Message:            `prod has a new release "abc".`,
+		URL:                "https://example.com/summary.html",
+	}
+	assert.True(t, slackWebhook.Wants(event))
+	assert.False(t, slackWebhook.Wants(Event{Type: EventNewEnvironmentRelease, Environment: "int"}))
+
+	require.NoError(t, slackWebhook.Send(context.Background(), event))
+	assert.Equal(t, 2, requests)
+	assert.JSONEq(t, `{"text": "*NewEnvironmentRelease*: prod has a new release \"abc\". <https://example.com/summary.html|details>"}`, payloads[1])
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(WebhookConfig{Name: "releases", Format: WebhookFormatJSON}, server.URL, clock.RealClock{})
+	require.NoError(t, err)
+
+	assert.Error(t, webhook.Send(context.Background(), Event{Type: EventBlockingCIRed}))
+	assert.Equal(t, 1, requests)
+}
+
+func TestWebhookErrorsOmitURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	secretURL := server.URL + "/services/T000/B000/secret-token"
+	// nothing is listening once the server is closed, so the request itself fails.
+	server.Close()
+
+	jsonWebhook, err := NewWebhook(WebhookConfig{Name: "releases", Format: WebhookFormatJSON}, secretURL, clock.RealClock{})
+	require.NoError(t, err)
+	jsonWebhook.(*webhook).maxRetries = 0
+
+	err = jsonWebhook.Send(context.Background(), Event{Type: EventNewEnvironmentRelease})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+	assert.NotContains(t, err.Error(), server.URL)
+	assert.Contains(t, err.Error(), `webhook "releases"`)
+}
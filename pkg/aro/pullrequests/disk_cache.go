@@ -0,0 +1,151 @@
+package pullrequests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+type diskCachingAccessor struct {
+	delegate PullRequestInfoAccessor
+	cacheDir string
+	ttl      time.Duration
+	clock    clock.PassiveClock
+
+	lock sync.Mutex
+	// cacheFileToFetch holds the fetches in flight, so concurrent requests for a pull request share one API call.
+	cacheFileToFetch map[string]*pullRequestFetch
+}
+
+type pullRequestFetch struct {
+	// done is closed once pullRequestInfo and err are set.
+	done            chan struct{}
+	pullRequestInfo *PullRequestInfo
+	err             error
+}
+
+// NewDiskCachingPullRequestInfoAccessor keeps results in cacheDir for ttl so restarts don't spend the API rate limit
+// on pull requests we have already seen.  Merged pull requests rarely change, so the ttl can be long.
+func NewDiskCachingPullRequestInfoAccessor(delegate PullRequestInfoAccessor, cacheDir string, ttl time.Duration, clock clock.PassiveClock) PullRequestInfoAccessor {
+	return &diskCachingAccessor{
+		delegate: delegate,
+		cacheDir: cacheDir,
+		ttl:      ttl,
+		clock:    clock,
+
+		cacheFileToFetch: map[string]*pullRequestFetch{},
+	}
+}
+
+type cacheEntry struct {
+	FetchTime       time.Time        `json:"fetchTime"`
+	PullRequestInfo *PullRequestInfo `json:"pullRequestInfo"`
+}
+
+func (d *diskCachingAccessor) GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error) {
+	logger := klog.FromContext(ctx)
+
+	// cache files are replaced atomically, so they are read without the lock.
+	cacheFile := d.cacheFile(repoURL, number)
+	if cached, err := d.read(cacheFile); err != nil {
+		logger.Error(err, "failed to read pull request cache", "cacheFile", cacheFile)
+	} else if cached != nil && d.clock.Since(cached.FetchTime) < d.ttl {
+		return cached.PullRequestInfo, nil
+	}
+
+	// the lock only guards the map, it isn't held across the API call.
+	d.lock.Lock()
+	fetch, ok := d.cacheFileToFetch[cacheFile]
+	if !ok {
+		fetch = &pullRequestFetch{done: make(chan struct{})}
+		d.cacheFileToFetch[cacheFile] = fetch
+		// detached from ctx, other requests may be waiting for the result.
+		go d.fetch(context.WithoutCancel(ctx), repoURL, number, cacheFile, fetch)
+	}
+	d.lock.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-fetch.done:
+		return fetch.pullRequestInfo, fetch.err
+	}
+}
+
+func (d *diskCachingAccessor) fetch(ctx context.Context, repoURL string, number int32, cacheFile string, fetch *pullRequestFetch) {
+	logger := klog.FromContext(ctx)
+
+	defer func() {
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		delete(d.cacheFileToFetch, cacheFile)
+		close(fetch.done)
+	}()
+
+	fetch.pullRequestInfo, fetch.err = d.delegate.GetPullRequestInfo(ctx, repoURL, number)
+	if fetch.err != nil {
+		return
+	}
+	if err := d.write(cacheFile, &cacheEntry{FetchTime: d.clock.Now(), PullRequestInfo: fetch.pullRequestInfo}); err != nil {
+		logger.Error(err, "failed to write pull request cache", "cacheFile", cacheFile)
+	}
+}
+
+func (d *diskCachingAccessor) cacheFile(repoURL string, number int32) string {
+	repoHash := sha256.Sum256([]byte(repoURL))
+	return filepath.Join(d.cacheDir, hex.EncodeToString(repoHash[:8]), fmt.Sprintf("%d.json", number))
+}
+
+func (d *diskCachingAccessor) read(cacheFile string) (*cacheEntry, error) {
+	cacheBytes, err := os.ReadFile(cacheFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := &cacheEntry{}
+	if err := json.Unmarshal(cacheBytes, ret); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", cacheFile, err)
+	}
+	return ret, nil
+}
+
+func (d *diskCachingAccessor) write(cacheFile string, entry *cacheEntry) error {
+	cacheBytes, err := json.MarshalIndent(entry, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		return err
+	}
+	// written next to the cache file and renamed, so readers never see a partial file.
+	tmpFile, err := os.CreateTemp(filepath.Dir(cacheFile), filepath.Base(cacheFile)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Write(cacheBytes); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	if err := os.Rename(tmpFile.Name(), cacheFile); err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package pullrequests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+type githubClient struct {
+	apiBaseURL string
+	token      string
+	client     *http.Client
+	rateLimit  *rateLimitTracker
+}
+
+// NewGithubClient reads pull requests from the GitHub REST API at apiBaseURL, usually https://api.github.com.
+// token may be empty, but anonymous clients only get sixty requests an hour.
+func NewGithubClient(apiBaseURL, token string, clock clock.PassiveClock) PullRequestInfoAccessor {
+	return &githubClient{
+		apiBaseURL: apiBaseURL,
+		token:      token,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		rateLimit:  newRateLimitTracker("X-RateLimit-Remaining", "X-RateLimit-Reset", clock),
+	}
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubPullRequest struct {
+	Title    string        `json:"title"`
+	Body     string        `json:"body"`
+	HTMLURL  string        `json:"html_url"`
+	User     *githubUser   `json:"user"`
+	MergedBy *githubUser   `json:"merged_by"`
+	Labels   []githubLabel `json:"labels"`
+}
+
+func (c *githubClient) GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error) {
+	path, err := repositoryPath(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.rateLimit.checkAllowed(); err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d", c.apiBaseURL, path, number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if len(c.token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do request: %w", err)
+	}
+	defer resp.Body.Close()
+	c.rateLimit.observe(resp)
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode > 299 || resp.StatusCode < 200 {
+		return nil, fmt.Errorf("request %v failed: %v: %v", url, resp.StatusCode, string(body))
+	}
+
+	pullRequest := &githubPullRequest{}
+	if err := json.Unmarshal(body, pullRequest); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request: %w", err)
+	}
+
+	ret := &PullRequestInfo{
+		Title:        pullRequest.Title,
+		URL:          pullRequest.HTMLURL,
+		Labels:       []string{},
+		LinkedIssues: linkedIssuesFromDescription(pullRequest.Body),
+	}
+	if pullRequest.User != nil {
+		ret.Author = pullRequest.User.Login
+	}
+	if pullRequest.MergedBy != nil {
+		ret.MergedBy = pullRequest.MergedBy.Login
+	}
+	for _, label := range pullRequest.Labels {
+		ret.Labels = append(ret.Labels, label.Name)
+	}
+	return ret, nil
+}
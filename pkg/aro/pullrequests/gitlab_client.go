@@ -0,0 +1,99 @@
+package pullrequests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+type gitlabClient struct {
+	apiBaseURL string
+	token      string
+	client     *http.Client
+	rateLimit  *rateLimitTracker
+}
+
+// NewGitlabClient reads merge requests from the GitLab REST API at apiBaseURL, for instance https://gitlab.cee.redhat.com/api/v4.
+func NewGitlabClient(apiBaseURL, token string, clock clock.PassiveClock) PullRequestInfoAccessor {
+	return &gitlabClient{
+		apiBaseURL: apiBaseURL,
+		token:      token,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		rateLimit:  newRateLimitTracker("RateLimit-Remaining", "RateLimit-Reset", clock),
+	}
+}
+
+type gitlabUser struct {
+	Username string `json:"username"`
+}
+
+type gitlabMergeRequest struct {
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	WebURL      string      `json:"web_url"`
+	Author      *gitlabUser `json:"author"`
+	MergedBy    *gitlabUser `json:"merged_by"`
+	MergeUser   *gitlabUser `json:"merge_user"`
+	Labels      []string    `json:"labels"`
+}
+
+func (c *gitlabClient) GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error) {
+	path, err := repositoryPath(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.rateLimit.checkAllowed(); err != nil {
+		return nil, err
+	}
+
+	requestURL := fmt.Sprintf("%s/projects/%s/merge_requests/%d", c.apiBaseURL, url.PathEscape(path), number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if len(c.token) > 0 {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do request: %w", err)
+	}
+	defer resp.Body.Close()
+	c.rateLimit.observe(resp)
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode > 299 || resp.StatusCode < 200 {
+		return nil, fmt.Errorf("request %v failed: %v: %v", requestURL, resp.StatusCode, string(body))
+	}
+
+	mergeRequest := &gitlabMergeRequest{}
+	if err := json.Unmarshal(body, mergeRequest); err != nil {
+		return nil, fmt.Errorf("failed to parse merge request: %w", err)
+	}
+
+	ret := &PullRequestInfo{
+		Title:        mergeRequest.Title,
+		URL:          mergeRequest.WebURL,
+		Labels:       mergeRequest.Labels,
+		LinkedIssues: linkedIssuesFromDescription(mergeRequest.Description),
+	}
+	if ret.Labels == nil {
+		ret.Labels = []string{}
+	}
+	if mergeRequest.Author != nil {
+		ret.Author = mergeRequest.Author.Username
+	}
+	// merged_by is deprecated in favor of merge_user, older servers only have the former.
+	switch {
+	case mergeRequest.MergeUser != nil:
+		ret.MergedBy = mergeRequest.MergeUser.Username
+	case mergeRequest.MergedBy != nil:
+		ret.MergedBy = mergeRequest.MergedBy.Username
+	}
+	return ret, nil
+}
@@ -0,0 +1,99 @@
+package pullrequests
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+type memoryCachingAccessor struct {
+	delegate PullRequestInfoAccessor
+	ttl      time.Duration
+	clock    clock.PassiveClock
+
+	lock            sync.Mutex
+	keyToCached     map[pullRequestKey]*memoryCacheEntry
+	keyToFetch      map[pullRequestKey]*pullRequestFetch
+	nextExpiryCheck time.Time
+}
+
+type pullRequestKey struct {
+	repoURL string
+	number  int32
+}
+
+type memoryCacheEntry struct {
+	fetchTime       time.Time
+	pullRequestInfo *PullRequestInfo
+}
+
+// NewMemoryCachingPullRequestInfoAccessor keeps results in memory for ttl, so every diff showing a pull request
+// doesn't ask the API for it again.  It is meant to sit in front of the disk cache, which survives restarts.
+// Errors aren't cached, the next diff tries again.
+func NewMemoryCachingPullRequestInfoAccessor(delegate PullRequestInfoAccessor, ttl time.Duration, clock clock.PassiveClock) PullRequestInfoAccessor {
+	return &memoryCachingAccessor{
+		delegate: delegate,
+		ttl:      ttl,
+		clock:    clock,
+
+		keyToCached: map[pullRequestKey]*memoryCacheEntry{},
+		keyToFetch:  map[pullRequestKey]*pullRequestFetch{},
+	}
+}
+
+func (m *memoryCachingAccessor) GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error) {
+	key := pullRequestKey{repoURL: repoURL, number: number}
+
+	// the lock only guards the maps, it isn't held across the API call.
+	m.lock.Lock()
+	m.removeExpiredLocked()
+	if cached, ok := m.keyToCached[key]; ok && m.clock.Since(cached.fetchTime) < m.ttl {
+		m.lock.Unlock()
+		return cached.pullRequestInfo, nil
+	}
+	fetch, ok := m.keyToFetch[key]
+	if !ok {
+		fetch = &pullRequestFetch{done: make(chan struct{})}
+		m.keyToFetch[key] = fetch
+		// detached from ctx, other requests may be waiting for the result.
+		go m.fetch(context.WithoutCancel(ctx), key, fetch)
+	}
+	m.lock.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-fetch.done:
+		return fetch.pullRequestInfo, fetch.err
+	}
+}
+
+func (m *memoryCachingAccessor) fetch(ctx context.Context, key pullRequestKey, fetch *pullRequestFetch) {
+	defer func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		delete(m.keyToFetch, key)
+		if fetch.err == nil {
+			m.keyToCached[key] = &memoryCacheEntry{fetchTime: m.clock.Now(), pullRequestInfo: fetch.pullRequestInfo}
+		}
+		close(fetch.done)
+	}()
+
+	fetch.pullRequestInfo, fetch.err = m.delegate.GetPullRequestInfo(ctx, key.repoURL, key.number)
+}
+
+// removeExpiredLocked drops expired entries at most once per ttl, so the map only holds pull requests of recent diffs.
+func (m *memoryCachingAccessor) removeExpiredLocked() {
+	now := m.clock.Now()
+	if now.Before(m.nextExpiryCheck) {
+		return
+	}
+	m.nextExpiryCheck = now.Add(m.ttl)
+	for key, cached := range m.keyToCached {
+		if now.Sub(cached.fetchTime) >= m.ttl {
+			delete(m.keyToCached, key)
+		}
+	}
+}
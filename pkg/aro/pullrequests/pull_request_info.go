@@ -0,0 +1,88 @@
+package pullrequests
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"k8s.io/utils/set"
+)
+
+// PullRequestInfo is the metadata we show for a merged GitHub pull request or GitLab merge request.
+type PullRequestInfo struct {
+	Title        string   `json:"title"`
+	Author       string   `json:"author"`
+	MergedBy     string   `json:"mergedBy"`
+	Labels       []string `json:"labels"`
+	LinkedIssues []string `json:"linkedIssues"`
+	URL          string   `json:"url"`
+}
+
+type PullRequestInfoAccessor interface {
+	// GetPullRequestInfo returns the metadata for PR or MR number in the repository at repoURL.
+	GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error)
+}
+
+type hostDispatchingAccessor struct {
+	github PullRequestInfoAccessor
+	gitlab PullRequestInfoAccessor
+}
+
+// NewPullRequestInfoAccessor sends github.com repositories to github and gitlab.cee.redhat.com repositories to gitlab.
+func NewPullRequestInfoAccessor(github, gitlab PullRequestInfoAccessor) PullRequestInfoAccessor {
+	return &hostDispatchingAccessor{
+		github: github,
+		gitlab: gitlab,
+	}
+}
+
+func (h *hostDispatchingAccessor) GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error) {
+	switch {
+	case strings.Contains(repoURL, "github.com"):
+		return h.github.GetPullRequestInfo(ctx, repoURL, number)
+	case strings.Contains(repoURL, "gitlab.cee.redhat.com"):
+		return h.gitlab.GetPullRequestInfo(ctx, repoURL, number)
+	default:
+		return nil, fmt.Errorf("no pull request accessor for %q", repoURL)
+	}
+}
+
+type dummyPullRequestInfoAccessor struct {
+}
+
+func NewDummyPullRequestInfoAccessor() PullRequestInfoAccessor {
+	return &dummyPullRequestInfoAccessor{}
+}
+
+func (d *dummyPullRequestInfoAccessor) GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error) {
+	return nil, nil
+}
+
+// repositoryPath returns org/repo (or group/subgroup/repo for gitlab) from a repository URL.
+func repositoryPath(repoURL string) (string, error) {
+	parsedURL, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse repository URL %q: %w", repoURL, err)
+	}
+	path := strings.TrimSuffix(strings.Trim(parsedURL.Path, "/"), ".git")
+	if strings.Count(path, "/") < 1 {
+		return "", fmt.Errorf("repository URL %q has no org and repository", repoURL)
+	}
+	return path, nil
+}
+
+var (
+	// closingKeywordRegex matches the GitHub and GitLab closing keywords, "Fixes #12", "closes org/repo#12" and full issue URLs.
+	closingKeywordRegex = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?|implement[sd]?)\b:?\s+((?:[\w.-]+/[\w.-]+)?#\d+|https?://\S+/issues/\d+)`)
+)
+
+// linkedIssuesFromDescription returns the issues a PR or MR description says it closes.
+func linkedIssuesFromDescription(description string) []string {
+	ret := set.New[string]()
+	for _, match := range closingKeywordRegex.FindAllStringSubmatch(description, -1) {
+		ret.Insert(match[1])
+	}
+	return ret.SortedList()
+}
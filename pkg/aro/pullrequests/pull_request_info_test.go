@@ -0,0 +1,219 @@
+package pullrequests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestGithubClient(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/repos/openshift/hypershift/pulls/6012", r.URL.Path)
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Date(2025, 8, 5, 1, 0, 0, 0, time.UTC).Unix()))
+		fmt.Fprint(w, `{
+			"title": "OCPBUGS-1234: fix nodepool upgrades",
+			"body": "Fixes #6000 and closes openshift/api#17",
+			"html_url": "https://github.com/openshift/hypershift/pull/6012",
+			"user": {"login": "author"},
+			"merged_by": {"login": "approver"},
+			"labels": [{"name": "lgtm"}, {"name": "approved"}]
+		}`)
+	}))
+	defer server.Close()
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	accessor := NewPullRequestInfoAccessor(
+		NewGithubClient(server.URL, "my-token", fakeClock),
+		NewDummyPullRequestInfoAccessor(),
+	)
+
+	actual, err := accessor.GetPullRequestInfo(context.Background(), "https://github.com/openshift/hypershift", 6012)
+	require.NoError(t, err)
+	assert.Equal(t, &PullRequestInfo{
+		Title:        "OCPBUGS-1234: fix nodepool upgrades",
+		Author:       "author",
+		MergedBy:     "approver",
+		Labels:       []string{"lgtm", "approved"},
+		LinkedIssues: []string{"#6000", "openshift/api#17"},
+		URL:          "https://github.com/openshift/hypershift/pull/6012",
+	}, actual)
+
+	// the server said we're out of requests until 01:00, so the next call must not reach it.
+	_, err = accessor.GetPullRequestInfo(context.Background(), "https://github.com/openshift/hypershift", 6012)
+	assert.ErrorContains(t, err, "rate limited")
+	assert.Equal(t, 1, requests)
+
+	fakeClock.SetTime(time.Date(2025, 8, 5, 1, 0, 1, 0, time.UTC))
+	_, err = accessor.GetPullRequestInfo(context.Background(), "https://github.com/openshift/hypershift", 6012)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestGitlabClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects/service%2Faro-hcp-clusters-service/merge_requests/42", r.URL.EscapedPath())
+		assert.Equal(t, "my-token", r.Header.Get("PRIVATE-TOKEN"))
+		fmt.Fprint(w, `{
+			"title": "Add autoscaling defaults",
+			"description": "Resolves https://gitlab.cee.redhat.com/service/aro-hcp-clusters-service/-/issues/7",
+			"web_url": "https://gitlab.cee.redhat.com/service/aro-hcp-clusters-service/-/merge_requests/42",
+			"author": {"username": "author"},
+			"merged_by": {"username": "old-field"},
+			"merge_user": {"username": "approver"},
+			"labels": ["backport"]
+		}`)
+	}))
+	defer server.Close()
+
+	accessor := NewPullRequestInfoAccessor(
+		NewDummyPullRequestInfoAccessor(),
+		NewGitlabClient(server.URL, "my-token", clocktesting.NewFakePassiveClock(time.Now())),
+	)
+
+	actual, err := accessor.GetPullRequestInfo(context.Background(), "https://gitlab.cee.redhat.com/service/aro-hcp-clusters-service", 42)
+	require.NoError(t, err)
+	assert.Equal(t, &PullRequestInfo{
+		Title:        "Add autoscaling defaults",
+		Author:       "author",
+		MergedBy:     "approver",
+		Labels:       []string{"backport"},
+		LinkedIssues: []string{"https://gitlab.cee.redhat.com/service/aro-hcp-clusters-service/-/issues/7"},
+		URL:          "https://gitlab.cee.redhat.com/service/aro-hcp-clusters-service/-/merge_requests/42",
+	}, actual)
+}
+
+func TestDiskCachingPullRequestInfoAccessor(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"title": "request %d"}`, requests)
+	}))
+	defer server.Close()
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	cacheDir := t.TempDir()
+	newAccessor := func() PullRequestInfoAccessor {
+		return NewDiskCachingPullRequestInfoAccessor(NewGithubClient(server.URL, "", fakeClock), cacheDir, time.Hour, fakeClock)
+	}
+
+	actual, err := newAccessor().GetPullRequestInfo(context.Background(), "https://github.com/Azure/ARO-HCP", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "request 1", actual.Title)
+
+	// a new accessor simulates a restart, the result must come from disk.
+	actual, err = newAccessor().GetPullRequestInfo(context.Background(), "https://github.com/Azure/ARO-HCP", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "request 1", actual.Title)
+
+	fakeClock.SetTime(fakeClock.Now().Add(2 * time.Hour))
+	actual, err = newAccessor().GetPullRequestInfo(context.Background(), "https://github.com/Azure/ARO-HCP", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "request 2", actual.Title)
+}
+
+// blockingPullRequestInfoAccessor blocks fetches of pull request 1 until release is closed.
+type blockingPullRequestInfoAccessor struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (a *blockingPullRequestInfoAccessor) GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error) {
+	a.calls.Add(1)
+	if number == 1 {
+		<-a.release
+	}
+	return &PullRequestInfo{Title: fmt.Sprintf("pull request %d", number)}, nil
+}
+
+func TestDiskCachingPullRequestInfoAccessorFetchesConcurrently(t *testing.T) {
+	ctx := context.Background()
+	delegate := &blockingPullRequestInfoAccessor{release: make(chan struct{})}
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	accessor := NewDiskCachingPullRequestInfoAccessor(delegate, t.TempDir(), time.Hour, fakeClock)
+
+	results := make(chan *PullRequestInfo, 2)
+	for range 2 {
+		go func() {
+			actual, err := accessor.GetPullRequestInfo(ctx, "https://github.com/Azure/ARO-HCP", 1)
+			assert.NoError(t, err)
+			results <- actual
+		}()
+	}
+
+	// a slow fetch doesn't hold up other pull requests.
+	actual, err := accessor.GetPullRequestInfo(ctx, "https://github.com/Azure/ARO-HCP", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "pull request 2", actual.Title)
+
+	// a request that gives up doesn't wait for the fetch.
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = accessor.GetPullRequestInfo(cancelledCtx, "https://github.com/Azure/ARO-HCP", 1)
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(delegate.release)
+	for range 2 {
+		assert.Equal(t, "pull request 1", (<-results).Title)
+	}
+	// concurrent requests for pull request 1 shared a fetch.
+	assert.Equal(t, int32(2), delegate.calls.Load())
+}
+
+type countingPullRequestInfoAccessor struct {
+	calls int
+	err   error
+}
+
+func (a *countingPullRequestInfoAccessor) GetPullRequestInfo(ctx context.Context, repoURL string, number int32) (*PullRequestInfo, error) {
+	a.calls++
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &PullRequestInfo{Title: fmt.Sprintf("pull request %d, fetch %d", number, a.calls)}, nil
+}
+
+func TestMemoryCachingPullRequestInfoAccessor(t *testing.T) {
+	ctx := context.Background()
+	delegate := &countingPullRequestInfoAccessor{}
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	accessor := NewMemoryCachingPullRequestInfoAccessor(delegate, time.Hour, fakeClock)
+
+	actual, err := accessor.GetPullRequestInfo(ctx, "https://github.com/Azure/ARO-HCP", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pull request 1, fetch 1", actual.Title)
+	actual, err = accessor.GetPullRequestInfo(ctx, "https://github.com/Azure/ARO-HCP", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pull request 1, fetch 1", actual.Title)
+
+	// the same number in another repository is another pull request.
+	actual, err = accessor.GetPullRequestInfo(ctx, "https://gitlab.cee.redhat.com/service/uhc-clusters-service", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pull request 1, fetch 2", actual.Title)
+
+	// errors aren't cached.
+	delegate.err = fmt.Errorf("rate limited")
+	_, err = accessor.GetPullRequestInfo(ctx, "https://github.com/Azure/ARO-HCP", 2)
+	require.ErrorContains(t, err, "rate limited")
+	delegate.err = nil
+	actual, err = accessor.GetPullRequestInfo(ctx, "https://github.com/Azure/ARO-HCP", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "pull request 2, fetch 4", actual.Title)
+
+	fakeClock.SetTime(fakeClock.Now().Add(2 * time.Hour))
+	actual, err = accessor.GetPullRequestInfo(ctx, "https://github.com/Azure/ARO-HCP", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "pull request 1, fetch 5", actual.Title)
+	assert.Len(t, accessor.(*memoryCachingAccessor).keyToCached, 1)
+}
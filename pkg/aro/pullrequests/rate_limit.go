@@ -0,0 +1,60 @@
+package pullrequests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// rateLimitTracker remembers the last rate limit a server reported so we stop calling it until the limit resets
+// instead of burning requests on errors.
+type rateLimitTracker struct {
+	remainingHeader string
+	resetHeader     string
+	clock           clock.PassiveClock
+
+	lock      sync.Mutex
+	exhausted bool
+	resetTime time.Time
+}
+
+func newRateLimitTracker(remainingHeader, resetHeader string, clock clock.PassiveClock) *rateLimitTracker {
+	return &rateLimitTracker{
+		remainingHeader: remainingHeader,
+		resetHeader:     resetHeader,
+		clock:           clock,
+	}
+}
+
+// checkAllowed returns an error if the last response said we are out of requests and the window hasn't reset yet.
+func (r *rateLimitTracker) checkAllowed() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.exhausted && r.clock.Now().Before(r.resetTime) {
+		return fmt.Errorf("rate limited until %v", r.resetTime.Format(time.RFC3339))
+	}
+	r.exhausted = false
+	return nil
+}
+
+// observe records the rate limit headers from a response.  Both GitHub and GitLab report the reset as unix seconds.
+func (r *rateLimitTracker) observe(response *http.Response) {
+	remaining, err := strconv.Atoi(response.Header.Get(r.remainingHeader))
+	if err != nil {
+		return
+	}
+	resetUnix, err := strconv.ParseInt(response.Header.Get(r.resetHeader), 10, 64)
+	if err != nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.exhausted = remaining <= 0
+	r.resetTime = time.Unix(resetUnix, 0)
+}
@@ -0,0 +1,131 @@
+package release_inspection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"k8s.io/klog/v2"
+)
+
+const aroHCPRepositoryURL = "https://github.com/Azure/ARO-HCP"
+
+// AROHCPRepository is the ARO-HCP checkout that releases are read from.  The release accessor resets the worktree
+// to every interesting commit while scanning, so anything that moves the checkout must hold the same lock.
+type AROHCPRepository struct {
+	lock sync.Mutex
+
+	dir    string
+	url    string
+	branch string
+}
+
+func NewAROHCPRepository(dir string) *AROHCPRepository {
+	return &AROHCPRepository{
+		dir:    dir,
+		url:    aroHCPRepositoryURL,
+		branch: "main",
+	}
+}
+
+func (r *AROHCPRepository) Dir() string {
+	return r.dir
+}
+
+func (r *AROHCPRepository) Name() string {
+	return "ARO-HCP"
+}
+
+func (r *AROHCPRepository) URL() string {
+	return r.url
+}
+
+// Refresh clones the repository if it doesn't exist yet.  Otherwise it fetches the latest branch from origin and moves
+// the checkout to it.  A checkout with local changes is only fetched, it is never reset.
+func (r *AROHCPRepository) Refresh(ctx context.Context) error {
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "repoDir", r.dir, "branchName", r.branch)
+	ctx = klog.NewContext(ctx, logger)
+
+	if _, err := os.Stat(r.dir); os.IsNotExist(err) {
+		return r.clone(ctx)
+	} else if err != nil {
+		return fmt.Errorf("failed to get aro hcp repo directory info: %w", err)
+	}
+
+	// the network fetch only writes objects and remote refs, so it doesn't need to wait for a scan to finish.
+	repo, err := git.PlainOpen(r.dir)
+	if err != nil {
+		return fmt.Errorf("failed to open aro hcp repo: %w", err)
+	}
+	logger.Info("Fetching latest from origin")
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs: []config.RefSpec{
+			config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", r.branch, r.branch)),
+		},
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch origin/%s: %w", r.branch, err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", r.branch), true)
+	if err != nil {
+		return fmt.Errorf("failed to resolve origin/%s: %w", r.branch, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get aro hcp worktree: %w", err)
+	}
+	// --aro-hcp-dir may be someone's working copy, a hard reset would throw their changes away.
+	worktreeStatus, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("failed to get aro hcp worktree status: %w", err)
+	}
+	if !worktreeStatus.IsClean() {
+		return fmt.Errorf("refusing to reset aro hcp worktree %s to origin/%s, it has local changes", r.dir, r.branch)
+	}
+	if err := worktree.Reset(&git.ResetOptions{
+		Commit: remoteRef.Hash(),
+		Mode:   git.HardReset,
+	}); err != nil {
+		return fmt.Errorf("failed to reset aro hcp worktree to origin/%s: %w", r.branch, err)
+	}
+	logger.Info("Reset ARO HCP checkout", "AROHCPHead", remoteRef.Hash())
+
+	return nil
+}
+
+func (r *AROHCPRepository) clone(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+	logger.Info("Cloning repo")
+
+	// clone next to the final location so a scan never sees a partial clone.
+	cloneDir := r.dir + ".partial"
+	if err := os.RemoveAll(cloneDir); err != nil {
+		return fmt.Errorf("failed to remove stale partial clone: %w", err)
+	}
+	_, err := git.PlainCloneContext(ctx, cloneDir, false, &git.CloneOptions{
+		URL:           r.url,
+		RemoteName:    "origin",
+		ReferenceName: plumbing.NewBranchReferenceName(r.branch),
+		SingleBranch:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clone aro hcp repo: %w", err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if err := os.Rename(cloneDir, r.dir); err != nil {
+		return fmt.Errorf("failed to move aro hcp clone into place: %w", err)
+	}
+	return nil
+}
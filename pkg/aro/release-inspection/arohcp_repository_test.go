@@ -0,0 +1,65 @@
+package release_inspection
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAROHCPRepositoryRefresh(t *testing.T) {
+	upstream := newTestGitRepository(t)
+	upstream.commit("release 1")
+
+	dir := filepath.Join(t.TempDir(), "ARO-HCP")
+	checkout, err := git.PlainClone(dir, false, &git.CloneOptions{URL: upstream.dir})
+	require.NoError(t, err)
+	repository := NewAROHCPRepository(dir)
+	repository.branch = "master"
+
+	// a clean checkout follows origin.
+	release2 := upstream.commit("release 2")
+	require.NoError(t, repository.Refresh(context.Background()))
+	head, err := checkout.Head()
+	require.NoError(t, err)
+	assert.Equal(t, release2, head.Hash())
+
+	// local changes are kept.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("local: change\n"), 0644))
+	upstream.commit("release 3")
+	err = repository.Refresh(context.Background())
+	require.ErrorContains(t, err, "local changes")
+	head, err = checkout.Head()
+	require.NoError(t, err)
+	assert.Equal(t, release2, head.Hash())
+	assert.FileExists(t, filepath.Join(dir, "config.yaml"))
+}
+
+func TestAROHCPRepositoryRefreshClones(t *testing.T) {
+	upstream := newTestGitRepository(t)
+	release1 := upstream.commit("release 1")
+
+	dir := filepath.Join(t.TempDir(), "ARO-HCP")
+	repository := NewAROHCPRepository(dir)
+	repository.url = upstream.dir
+	repository.branch = "master"
+
+	require.NoError(t, repository.Refresh(context.Background()))
+	checkout, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := checkout.Head()
+	require.NoError(t, err)
+	assert.Equal(t, release1, head.Hash())
+	assert.NoDirExists(t, dir+".partial")
+
+	// the clone is refreshed like any other checkout.
+	release2 := upstream.commit("release 2")
+	require.NoError(t, repository.Refresh(context.Background()))
+	head, err = checkout.Head()
+	require.NoError(t, err)
+	assert.Equal(t, release2, head.Hash())
+}
@@ -88,12 +88,64 @@ func scrapeInfoForAROHCPConfig(ctx context.Context, imageInfoAccessor ImageInfoA
 		addComponentInfoSha("Service Prometheus Spec", config.Svc.Prometheus.PrometheusSpec.Image)
 	}
 
+	degradedComponents := []string{}
+	for _, componentName := range set.KeySet(currConfigInfo.Components).SortedList() {
+		if status.IsConditionTrue(currConfigInfo.Components[componentName].Conditions, status.ConditionDegraded) {
+			degradedComponents = append(degradedComponents, componentName)
+		}
+	}
+	if len(degradedComponents) > 0 {
+		currConfigInfo.Conditions = append(currConfigInfo.Conditions, status.Condition{
+			Type:    status.ConditionDegraded,
+			Status:  status.ConditionTrue,
+			Reason:  status.ConditionReasonComponentsDegraded,
+			Message: fmt.Sprintf("could not read the images of: %s", strings.Join(degradedComponents, ", ")),
+		})
+	}
+
 	return currConfigInfo, nil
 }
 
+// newConfigUnreadableEnvironmentRelease stands in for a release whose config could not be read, so the release is
+// still listed.
+func newConfigUnreadableEnvironmentRelease(environmentName, releaseName, releaseSHA string, err error) *status.EnvironmentRelease {
+	return &status.EnvironmentRelease{
+		TypeMeta: status.TypeMeta{
+			Kind:       "EnvironmentRelease",
+			APIVersion: "service-status.hcm.openshift.io/v1",
+		},
+		Name:                   getEnvironmentReleaseName(environmentName, releaseName),
+		ReleaseName:            releaseName,
+		SHA:                    releaseSHA,
+		Environment:            environmentName,
+		Components:             map[string]*status.Component{},
+		BlockingJobRunResults:  map[string][]status.JobRunResults{},
+		InformingJobRunResults: map[string][]status.JobRunResults{},
+		Conditions: []status.Condition{
+			{
+				Type:    status.ConditionDegraded,
+				Status:  status.ConditionTrue,
+				Reason:  status.ConditionReasonConfigUnreadable,
+				Message: err.Error(),
+			},
+		},
+	}
+}
+
+// IsConfigUnreadable is true for releases listed without components because their config could not be read.
+func IsConfigUnreadable(environmentRelease *status.EnvironmentRelease) bool {
+	condition := status.FindCondition(environmentRelease.Conditions, status.ConditionDegraded)
+	return condition != nil && condition.Status == status.ConditionTrue && condition.Reason == status.ConditionReasonConfigUnreadable
+}
+
 func completeSourceSHAs(ctx context.Context, imageInfoAccessor ImageInfoAccessor, currInfo *status.Component) {
 	if imageInfo, err := imageInfoAccessor.GetImageInfo(ctx, &currInfo.ImageInfo); err != nil {
-		currInfo.SourceSHA = fmt.Sprintf("ERROR: %v", err)
+		currInfo.Conditions = append(currInfo.Conditions, status.Condition{
+			Type:    status.ConditionDegraded,
+			Status:  status.ConditionTrue,
+			Reason:  status.ConditionReasonImageInfoUnavailable,
+			Message: fmt.Sprintf("failed to get image info: %v", err),
+		})
 	} else {
 		currInfo.ImageCreationTime = imageInfo.ImageCreationTime
 		currInfo.SourceSHA = imageInfo.SourceSHA
@@ -120,7 +172,14 @@ func createComponentInfo(ctx context.Context, imageInfoAccessor ImageInfoAccesso
 		componentInfo.ImageInfo.Repository = repository
 		componentInfo.ImageInfo.Registry = registry
 		if err != nil {
-			componentInfo.ImageInfo.Registry = fmt.Sprintf("missing image pull location for %q: %v", name, err)
+			// without a registry the image can't be pulled, so don't try.
+			componentInfo.Conditions = append(componentInfo.Conditions, status.Condition{
+				Type:    status.ConditionDegraded,
+				Status:  status.ConditionTrue,
+				Reason:  status.ConditionReasonImagePullLocationUnknown,
+				Message: fmt.Sprintf("missing image pull location for %q: %v", name, err),
+			})
+			return componentInfo
 		}
 	}
 	completeSourceSHAs(ctx, imageInfoAccessor, componentInfo)
@@ -128,6 +187,7 @@ func createComponentInfo(ctx context.Context, imageInfoAccessor ImageInfoAccesso
 	return componentInfo
 }
 
+// ChangedComponents lists the components that were added, removed, or have different images between the releases.
 func ChangedComponents(currReleaseEnvironmentInfo, prevReleaseEnvironmentInfo *status.EnvironmentRelease) set.Set[string] {
 	changedComponents := set.Set[string]{}
 
@@ -138,12 +198,45 @@ func ChangedComponents(currReleaseEnvironmentInfo, prevReleaseEnvironmentInfo *s
 		return changedComponents
 	}
 
-	for _, currComponent := range currReleaseEnvironmentInfo.Components {
-		prevComponent := prevReleaseEnvironmentInfo.Components[currComponent.Name]
-		if !reflect.DeepEqual(prevComponent.ImageInfo, currComponent.ImageInfo) {
-			changedComponents.Insert(currComponent.Name)
+	componentNames := set.KeySet(currReleaseEnvironmentInfo.Components).Union(set.KeySet(prevReleaseEnvironmentInfo.Components))
+	for _, componentName := range componentNames.UnsortedList() {
+		currComponent := currReleaseEnvironmentInfo.Components[componentName]
+		prevComponent := prevReleaseEnvironmentInfo.Components[componentName]
+		if currComponent == nil || prevComponent == nil || !reflect.DeepEqual(prevComponent.ImageInfo, currComponent.ImageInfo) {
+			changedComponents.Insert(componentName)
 		}
 	}
 
 	return changedComponents
 }
+
+// FindMatchingEnvironmentRelease returns the release in haystack with the same images as needle and no changed
+// components.  When there isn't one, it returns the closest release and the components that differ.  Releases with
+// unreadable config have no images to compare, so they are skipped in haystack, and an unreadable needle matches
+// nothing.  The returned release is nil when nothing could be compared.
+func FindMatchingEnvironmentRelease(haystack *status.EnvironmentReleaseList, needle *status.EnvironmentRelease) (*status.EnvironmentRelease, set.Set[string]) {
+	if IsConfigUnreadable(needle) {
+		return nil, nil
+	}
+
+	var minChangedComponents set.Set[string]
+	var minChangedRelease *status.EnvironmentRelease
+	for i := range haystack.Items {
+		currEnvironmentRelease := haystack.Items[i]
+		if IsConfigUnreadable(&currEnvironmentRelease) {
+			continue
+		}
+
+		currChangedComponents := ChangedComponents(&currEnvironmentRelease, needle)
+		if len(currChangedComponents) == 0 {
+			return &currEnvironmentRelease, nil
+		}
+
+		if minChangedRelease == nil || len(currChangedComponents) < minChangedComponents.Len() {
+			minChangedComponents = currChangedComponents
+			minChangedRelease = &currEnvironmentRelease
+		}
+	}
+
+	return minChangedRelease, minChangedComponents
+}
@@ -0,0 +1,91 @@
+package release_inspection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+type failingImageInfoAccessor struct{}
+
+func (failingImageInfoAccessor) GetImageInfo(ctx context.Context, containerImage *status.ContainerImage) (ImageInfo, error) {
+	return ImageInfo{}, errors.New("manifest unknown")
+}
+
+func TestCreateComponentInfoDegraded(t *testing.T) {
+	component := createComponentInfo(context.Background(), failingImageInfoAccessor{}, "Frontend", "https://github.com/Azure/ARO-HCP", ptr.To("sha256:abc"))
+
+	assert.Empty(t, component.SourceSHA)
+	condition := status.FindCondition(component.Conditions, status.ConditionDegraded)
+	require.NotNil(t, condition)
+	assert.Equal(t, status.ConditionTrue, condition.Status)
+	assert.Equal(t, status.ConditionReasonImageInfoUnavailable, condition.Reason)
+	assert.Contains(t, condition.Message, "manifest unknown")
+}
+
+func TestChangedComponentsAgainstUnreadableRelease(t *testing.T) {
+	unreadable := newConfigUnreadableEnvironmentRelease("int", "2025-01-01-abc", "abc", errors.New("bad yaml"))
+	assert.True(t, IsConfigUnreadable(unreadable))
+
+	curr := &status.EnvironmentRelease{
+		Components: map[string]*status.Component{
+			"Frontend": {Name: "Frontend"},
+		},
+	}
+	assert.Equal(t, []string{"Frontend"}, ChangedComponents(curr, unreadable).SortedList())
+}
+
+func TestChangedComponentsIncludesRemovedComponents(t *testing.T) {
+	curr := &status.EnvironmentRelease{
+		Components: map[string]*status.Component{
+			"Frontend": {Name: "Frontend"},
+		},
+	}
+	prev := &status.EnvironmentRelease{
+		Components: map[string]*status.Component{
+			"Frontend": {Name: "Frontend"},
+			"Backend":  {Name: "Backend"},
+		},
+	}
+	assert.Equal(t, []string{"Backend"}, ChangedComponents(curr, prev).SortedList())
+}
+
+func TestFindMatchingEnvironmentReleaseSkipsUnreadable(t *testing.T) {
+	newRelease := func(name, frontendDigest string) status.EnvironmentRelease {
+		return status.EnvironmentRelease{
+			Name: name,
+			Components: map[string]*status.Component{
+				"Frontend": {Name: "Frontend", ImageInfo: status.ContainerImage{Digest: frontendDigest}},
+				"Backend":  {Name: "Backend", ImageInfo: status.ContainerImage{Digest: "sha256:backend"}},
+			},
+		}
+	}
+	unreadable := *newConfigUnreadableEnvironmentRelease("int", "2025-01-03-abc", "abc", errors.New("bad yaml"))
+	closest := newRelease("int---2025-01-02-def", "sha256:old")
+	needle := newRelease("stg---2025-01-04-ghi", "sha256:new")
+
+	haystack := &status.EnvironmentReleaseList{Items: []status.EnvironmentRelease{unreadable, closest}}
+	match, changedComponents := FindMatchingEnvironmentRelease(haystack, &needle)
+	require.NotNil(t, match)
+	assert.Equal(t, closest.Name, match.Name)
+	assert.Equal(t, []string{"Frontend"}, changedComponents.SortedList())
+
+	// only unreadable releases leave nothing to compare with.
+	match, changedComponents = FindMatchingEnvironmentRelease(&status.EnvironmentReleaseList{Items: []status.EnvironmentRelease{unreadable}}, &needle)
+	assert.Nil(t, match)
+	assert.Empty(t, changedComponents)
+
+	// an unreadable needle doesn't match anything either.
+	match, _ = FindMatchingEnvironmentRelease(&status.EnvironmentReleaseList{Items: []status.EnvironmentRelease{needle}}, &unreadable)
+	assert.Nil(t, match)
+
+	match, changedComponents = FindMatchingEnvironmentRelease(&status.EnvironmentReleaseList{Items: []status.EnvironmentRelease{unreadable, needle}}, &needle)
+	require.NotNil(t, match)
+	assert.Equal(t, needle.Name, match.Name)
+	assert.Empty(t, changedComponents)
+}
@@ -3,15 +3,45 @@ package release_inspection
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/openshift-online/service-status/pkg/apis/status"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
 )
 
+// CachingReleaseAccessor caches every method of the accessor it wraps.
+type CachingReleaseAccessor interface {
+	ReleaseAccessor
+	// Run recomputes cached values in the background until the context is done.
+	Run(ctx context.Context)
+	ListCaches() *status.CacheList
+}
+
+const (
+	CacheListEnvironments                      = "list_environments"
+	CacheListEnvironmentReleases               = "list_environment_releases"
+	CacheListEnvironmentReleasesForEnvironment = "list_environment_releases_for_environment"
+	CacheGetEnvironmentRelease                 = "get_environment_release"
+	CacheGetReleaseEnvironmentDiff             = "get_release_environment_diff"
+	CacheGetCICoverage                         = "get_ci_coverage"
+	CacheListComponents                        = "list_components"
+)
+
+// CacheNames lists every cache in the order ListCaches reports them.
+var CacheNames = []string{
+	CacheListEnvironments,
+	CacheListEnvironmentReleases,
+	CacheListEnvironmentReleasesForEnvironment,
+	CacheGetEnvironmentRelease,
+	CacheGetReleaseEnvironmentDiff,
+	CacheGetCICoverage,
+	CacheListComponents,
+}
+
 type cachingReleaseAccessor struct {
 	selfLookupInstance ReleaseAccessor
 	delegate           ReleaseAccessor
@@ -22,30 +52,62 @@ type cachingReleaseAccessor struct {
 	listEnvironmentReleasesForEnvironment *stringBasedResultTimeBasedCacher[*status.EnvironmentReleaseList]
 	getEnvironmentRelease                 *stringBasedResultTimeBasedCacher[*status.EnvironmentRelease]
 	getReleaseEnvironmentDiff             *stringBasedResultTimeBasedCacher[*status.EnvironmentReleaseDiff]
+	getCICoverage                         *stringBasedResultTimeBasedCacher[*status.CICoverage]
+	listComponents                        *stringBasedResultTimeBasedCacher[*status.ComponentStatusList]
 }
 
-func NewCachingReleaseAccessor(delegate ReleaseAccessor, clock clock.Clock) ReleaseAccessor {
+// NewCachingReleaseAccessor caches with the policy named after each cache in cachePolicies, or DefaultCachePolicy.
+func NewCachingReleaseAccessor(delegate ReleaseAccessor, cachePolicies map[string]CachePolicy, clock clock.Clock) CachingReleaseAccessor {
+	policyFor := func(name string) CachePolicy {
+		if policy, ok := cachePolicies[name]; ok {
+			return policy
+		}
+		return DefaultCachePolicy
+	}
+
 	ret := &cachingReleaseAccessor{
 		delegate: delegate,
 		clock:    clock,
 		listEnvironments: &stringBasedResultTimeBasedCacher[[]string]{
+			name:     CacheListEnvironments,
 			delegate: noKeyAdapter(delegate.ListEnvironments),
+			policy:   policyFor(CacheListEnvironments),
 			clock:    clock,
 		},
 		listEnvironmentReleases: &stringBasedResultTimeBasedCacher[*status.EnvironmentReleaseList]{
+			name:     CacheListEnvironmentReleases,
 			delegate: noKeyAdapter(delegate.ListEnvironmentReleases),
+			policy:   policyFor(CacheListEnvironmentReleases),
 			clock:    clock,
 		},
 		listEnvironmentReleasesForEnvironment: &stringBasedResultTimeBasedCacher[*status.EnvironmentReleaseList]{
+			name:     CacheListEnvironmentReleasesForEnvironment,
 			delegate: delegate.ListEnvironmentReleasesForEnvironment,
+			policy:   policyFor(CacheListEnvironmentReleasesForEnvironment),
 			clock:    clock,
 		},
 		getEnvironmentRelease: &stringBasedResultTimeBasedCacher[*status.EnvironmentRelease]{
+			name:     CacheGetEnvironmentRelease,
 			delegate: delegate.GetEnvironmentRelease,
+			policy:   policyFor(CacheGetEnvironmentRelease),
 			clock:    clock,
 		},
 		getReleaseEnvironmentDiff: &stringBasedResultTimeBasedCacher[*status.EnvironmentReleaseDiff]{
-			delegate: twoStringAdapter(delegate.GetReleaseEnvironmentDiff),
+			name:     CacheGetReleaseEnvironmentDiff,
+			delegate: diffAdapter(delegate.GetReleaseEnvironmentDiff),
+			policy:   policyFor(CacheGetReleaseEnvironmentDiff),
+			clock:    clock,
+		},
+		getCICoverage: &stringBasedResultTimeBasedCacher[*status.CICoverage]{
+			name:     CacheGetCICoverage,
+			delegate: delegate.GetCICoverage,
+			policy:   policyFor(CacheGetCICoverage),
+			clock:    clock,
+		},
+		listComponents: &stringBasedResultTimeBasedCacher[*status.ComponentStatusList]{
+			name:     CacheListComponents,
+			delegate: noKeyAdapter(delegate.ListComponents),
+			policy:   policyFor(CacheListComponents),
 			clock:    clock,
 		},
 	}
@@ -54,63 +116,85 @@ func NewCachingReleaseAccessor(delegate ReleaseAccessor, clock clock.Clock) Rele
 	return ret
 }
 
-func noKeyAdapter[T any](fn func(ctx context.Context) (T, error)) func(ctx context.Context, key string) (T, error) {
-	return func(ctx context.Context, key string) (T, error) {
-		return fn(ctx)
+func (r *cachingReleaseAccessor) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting cache refreshers")
+
+	wg := sync.WaitGroup{}
+	for _, run := range []func(ctx context.Context){
+		r.listEnvironments.run,
+		r.listEnvironmentReleases.run,
+		r.listEnvironmentReleasesForEnvironment.run,
+		r.getEnvironmentRelease.run,
+		r.getReleaseEnvironmentDiff.run,
+		r.getCICoverage.run,
+		r.listComponents.run,
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run(ctx)
+		}()
 	}
+	wg.Wait()
 }
 
-func twoStringAdapter[T any](fn func(ctx context.Context, part1, part2 string) (T, error)) func(ctx context.Context, key string) (T, error) {
-	return func(ctx context.Context, key string) (T, error) {
-		part1, part2, found := strings.Cut(key, "###")
-		if !found {
-			panic("caller error: key should contain ### separator")
-		}
-		return fn(ctx, part1, part2)
+func (r *cachingReleaseAccessor) ListCaches() *status.CacheList {
+	return &status.CacheList{
+		TypeMeta: status.TypeMeta{
+			Kind:       "CacheList",
+			APIVersion: "service-status.hcm.openshift.io/v1",
+		},
+		Items: []status.Cache{
+			r.listEnvironments.cacheStatus(),
+			r.listEnvironmentReleases.cacheStatus(),
+			r.listEnvironmentReleasesForEnvironment.cacheStatus(),
+			r.getEnvironmentRelease.cacheStatus(),
+			r.getReleaseEnvironmentDiff.cacheStatus(),
+			r.getCICoverage.cacheStatus(),
+			r.listComponents.cacheStatus(),
+		},
 	}
 }
 
-type stringBasedResultTimeBasedCacher[T any] struct {
-	delegate func(ctx context.Context, key string) (T, error)
-	clock    clock.Clock
-
-	lock        sync.RWMutex
-	lastRefresh map[string]time.Time
-	results     map[string]T
+func noKeyAdapter[T any](fn func(ctx context.Context) (T, error)) func(ctx context.Context, key string) (T, error) {
+	return func(ctx context.Context, key string) (T, error) {
+		return fn(ctx)
+	}
 }
 
-func (r *stringBasedResultTimeBasedCacher[T]) Do(ctx context.Context, key string) (T, error) {
-	logger := klog.FromContext(ctx)
-
-	r.lock.RLock()
-	if r.clock.Since(r.lastRefresh[key]) < 1*time.Hour {
-		defer r.lock.RUnlock()
-		return r.results[key], nil
+// diffKey holds everything a diff depends on, so every page is cached on its own.  Release names come from URLs, so
+// each part is escaped to keep the separator out of it.
+func diffKey(environmentReleaseName, otherEnvironmentReleaseName string, options status.DiffOptions) string {
+	parts := []string{environmentReleaseName, otherEnvironmentReleaseName, strconv.Itoa(options.Limit), options.Continue}
+	for i := range parts {
+		parts[i] = url.QueryEscape(parts[i])
 	}
-	r.lock.RUnlock()
-
-	r.lock.Lock()
-	defer r.lock.Unlock()
+	return strings.Join(parts, diffKeySeparator)
+}
 
-	if r.clock.Since(r.lastRefresh[key]) < 1*time.Hour {
-		logger.Info("returning cached result", "key", key)
-		return r.results[key], nil
-	}
+const diffKeySeparator = "###"
 
-	curr, err := r.delegate(ctx, key)
-	if err != nil {
-		return curr, err
-	}
-
-	if r.results == nil {
-		r.results = make(map[string]T)
-	}
-	if r.lastRefresh == nil {
-		r.lastRefresh = make(map[string]time.Time)
+func diffAdapter[T any](fn func(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string, options status.DiffOptions) (T, error)) func(ctx context.Context, key string) (T, error) {
+	return func(ctx context.Context, key string) (T, error) {
+		var zero T
+		parts := strings.Split(key, diffKeySeparator)
+		if len(parts) != 4 {
+			return zero, status.NewBadRequest(fmt.Sprintf("invalid diff cache key %q", key))
+		}
+		for i := range parts {
+			unescaped, err := url.QueryUnescape(parts[i])
+			if err != nil {
+				return zero, status.NewBadRequest(fmt.Sprintf("invalid diff cache key %q: %v", key, err))
+			}
+			parts[i] = unescaped
+		}
+		limit, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return zero, status.NewBadRequest(fmt.Sprintf("invalid diff cache key %q: %v", key, err))
+		}
+		return fn(ctx, parts[0], parts[1], status.DiffOptions{Limit: limit, Continue: parts[3]})
 	}
-	r.lastRefresh[key] = r.clock.Now()
-	r.results[key] = curr
-	return r.results[key], nil
 }
 
 func (r *cachingReleaseAccessor) ListEnvironments(ctx context.Context) ([]string, error) {
@@ -129,11 +213,11 @@ func (r *cachingReleaseAccessor) GetEnvironmentRelease(ctx context.Context, envi
 		}
 	}
 
-	return nil, fmt.Errorf("environment release not found: %s", environmentReleaseName)
+	return nil, status.NewNotFound("EnvironmentRelease", environmentReleaseName)
 }
 
-func (r *cachingReleaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string) (*status.EnvironmentReleaseDiff, error) {
-	return r.getReleaseEnvironmentDiff.Do(ctx, fmt.Sprintf("%v###%v", environmentReleaseName, otherEnvironmentReleaseName))
+func (r *cachingReleaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+	return r.getReleaseEnvironmentDiff.Do(ctx, diffKey(environmentReleaseName, otherEnvironmentReleaseName, options))
 }
 
 func (r *cachingReleaseAccessor) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error) {
@@ -147,3 +231,11 @@ func (r *cachingReleaseAccessor) ListEnvironmentReleases(ctx context.Context) (*
 func (r *cachingReleaseAccessor) SetSelfLookupInstance(accessor ReleaseAccessor) {
 	r.selfLookupInstance = accessor
 }
+
+func (r *cachingReleaseAccessor) GetCICoverage(ctx context.Context, environmentName string) (*status.CICoverage, error) {
+	return r.getCICoverage.Do(ctx, environmentName)
+}
+
+func (r *cachingReleaseAccessor) ListComponents(ctx context.Context) (*status.ComponentStatusList, error) {
+	return r.listComponents.Do(ctx, "")
+}
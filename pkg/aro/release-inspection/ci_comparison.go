@@ -0,0 +1,96 @@
+package release_inspection
+
+import (
+	"sort"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/utils/set"
+)
+
+// DefaultSignificanceLevel matches what sippy component readiness uses.
+const DefaultSignificanceLevel = 0.05
+
+// NewCIComparison compares the CI pass rates of environmentRelease against otherEnvironmentRelease, usually the
+// previous release.  Sippy only reports failing tests, so a test counts as run in every finished job run that didn't
+// fail on infrastructure.
+func NewCIComparison(environmentRelease, otherEnvironmentRelease *status.EnvironmentRelease, significanceLevel float64) *status.CIComparison {
+	ret := &status.CIComparison{
+		SignificanceLevel: significanceLevel,
+		JobVariants:       []status.PassRateComparison{},
+		Tests:             []status.PassRateComparison{},
+	}
+
+	for _, category := range []JobCategory{JobImpactBlocking, JobImpactInforming} {
+		results, otherResults := environmentRelease.BlockingJobRunResults, otherEnvironmentRelease.BlockingJobRunResults
+		if category == JobImpactInforming {
+			results, otherResults = environmentRelease.InformingJobRunResults, otherEnvironmentRelease.InformingJobRunResults
+		}
+		for _, variantName := range set.KeySet(results).Union(set.KeySet(otherResults)).SortedList() {
+			curr := jobVariantHealth(variantName, category, results[variantName])
+			other := jobVariantHealth(variantName, category, otherResults[variantName])
+			comparison := comparePassRates(variantName, curr.Runs, curr.Succeeded, other.Runs, other.Succeeded, significanceLevel)
+			comparison.Category = string(category)
+			ret.JobVariants = append(ret.JobVariants, comparison)
+		}
+	}
+
+	testRuns, testFailures := testPassRates(environmentRelease)
+	otherTestRuns, otherTestFailures := testPassRates(otherEnvironmentRelease)
+	for _, testName := range set.KeySet(testFailures).Union(set.KeySet(otherTestFailures)).SortedList() {
+		comparison := comparePassRates(testName,
+			testRuns, testRuns-testFailures[testName],
+			otherTestRuns, otherTestRuns-otherTestFailures[testName],
+			significanceLevel)
+		if comparison.Verdict != status.ComparisonNoSignificantChange {
+			ret.Tests = append(ret.Tests, comparison)
+		}
+	}
+	sort.SliceStable(ret.Tests, func(i, j int) bool {
+		return ret.Tests[i].PValue < ret.Tests[j].PValue
+	})
+
+	return ret
+}
+
+func comparePassRates(name string, runs, succeeded, otherRuns, otherSucceeded int, significanceLevel float64) status.PassRateComparison {
+	ret := status.PassRateComparison{
+		Name:           name,
+		Runs:           runs,
+		Succeeded:      succeeded,
+		OtherRuns:      otherRuns,
+		OtherSucceeded: otherSucceeded,
+		PValue:         FisherExactTest(succeeded, runs-succeeded, otherSucceeded, otherRuns-otherSucceeded),
+		Verdict:        status.ComparisonNoSignificantChange,
+	}
+	if runs == 0 || otherRuns == 0 || ret.PValue >= significanceLevel {
+		return ret
+	}
+	// compare succeeded/runs to otherSucceeded/otherRuns without dividing.
+	if succeeded*otherRuns < otherSucceeded*runs {
+		ret.Verdict = status.ComparisonRegressed
+	} else {
+		ret.Verdict = status.ComparisonImproved
+	}
+	return ret
+}
+
+// testPassRates returns how many job runs could have run tests and how many of them failed each test.
+func testPassRates(environmentRelease *status.EnvironmentRelease) (int, map[string]int) {
+	runs := 0
+	failures := map[string]int{}
+	for _, results := range []map[string][]status.JobRunResults{environmentRelease.BlockingJobRunResults, environmentRelease.InformingJobRunResults} {
+		for _, jobRuns := range results {
+			for _, jobRun := range jobRuns {
+				switch jobRun.OverallResult {
+				case status.JobRunning, status.JobInfrastructureFailure, status.JobFailureBeforeSetup, status.JobAborted:
+					continue
+				}
+				runs++
+				for _, testName := range set.New(jobRun.FailedTestNames...).UnsortedList() {
+					failures[testName]++
+				}
+			}
+		}
+	}
+	return runs, failures
+}
@@ -0,0 +1,102 @@
+package release_inspection
+
+import (
+	"math"
+	"testing"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparePassRates(t *testing.T) {
+	// 1 of 10 against 11 of 14 has a p-value of 0.002759, see TestFisherExactTest.
+	pValue := FisherExactTest(1, 9, 11, 3)
+
+	tests := []struct {
+		name                      string
+		runs, succeeded           int
+		otherRuns, otherSucceeded int
+		significanceLevel         float64
+		expected                  status.ComparisonVerdict
+	}{
+		{name: "regressed", runs: 10, succeeded: 1, otherRuns: 14, otherSucceeded: 11, significanceLevel: DefaultSignificanceLevel, expected: status.ComparisonRegressed},
+		{name: "improved", runs: 14, succeeded: 11, otherRuns: 10, otherSucceeded: 1, significanceLevel: DefaultSignificanceLevel, expected: status.ComparisonImproved},
+		{name: "same pass rate", runs: 10, succeeded: 5, otherRuns: 10, otherSucceeded: 5, significanceLevel: DefaultSignificanceLevel, expected: status.ComparisonNoSignificantChange},
+		{name: "too few runs to tell", runs: 4, succeeded: 1, otherRuns: 4, otherSucceeded: 3, significanceLevel: DefaultSignificanceLevel, expected: status.ComparisonNoSignificantChange},
+		{name: "no runs", runs: 0, succeeded: 0, otherRuns: 10, otherSucceeded: 10, significanceLevel: 1.1, expected: status.ComparisonNoSignificantChange},
+		{name: "no other runs", runs: 10, succeeded: 0, otherRuns: 0, otherSucceeded: 0, significanceLevel: 1.1, expected: status.ComparisonNoSignificantChange},
+		{name: "p-value at the significance level", runs: 10, succeeded: 1, otherRuns: 14, otherSucceeded: 11, significanceLevel: pValue, expected: status.ComparisonNoSignificantChange},
+		{name: "p-value just below the significance level", runs: 10, succeeded: 1, otherRuns: 14, otherSucceeded: 11, significanceLevel: math.Nextafter(pValue, 1), expected: status.ComparisonRegressed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := comparePassRates("test", tt.runs, tt.succeeded, tt.otherRuns, tt.otherSucceeded, tt.significanceLevel)
+			assert.Equal(t, tt.expected, actual.Verdict)
+			assert.Equal(t, tt.runs, actual.Runs)
+			assert.Equal(t, tt.succeeded, actual.Succeeded)
+			assert.Equal(t, tt.otherRuns, actual.OtherRuns)
+			assert.Equal(t, tt.otherSucceeded, actual.OtherSucceeded)
+		})
+	}
+}
+
+func TestNewCIComparison(t *testing.T) {
+	repeat := func(count int, jobRun status.JobRunResults) []status.JobRunResults {
+		ret := []status.JobRunResults{}
+		for range count {
+			ret = append(ret, jobRun)
+		}
+		return ret
+	}
+	environmentRelease := &status.EnvironmentRelease{
+		BlockingJobRunResults: map[string][]status.JobRunResults{
+			"bare-minimum": append(append(
+				// a test listed twice in a run only failed once.
+				repeat(9, status.JobRunResults{OverallResult: status.JobTestFailure, FailedTestNames: []string{"t1", "t1"}}),
+				status.JobRunResults{OverallResult: status.JobSucceeded},
+				// runs that never got to the tests don't count for or against any test.
+				status.JobRunResults{OverallResult: status.JobInfrastructureFailure, FailedTestNames: []string{"t3"}}),
+				status.JobRunResults{OverallResult: status.JobRunning, FailedTestNames: []string{"t3"}},
+			),
+		},
+	}
+	otherEnvironmentRelease := &status.EnvironmentRelease{
+		BlockingJobRunResults: map[string][]status.JobRunResults{
+			"bare-minimum": append(
+				repeat(8, status.JobRunResults{OverallResult: status.JobTestFailure, FailedTestNames: []string{"t2"}}),
+				repeat(2, status.JobRunResults{OverallResult: status.JobSucceeded})...,
+			),
+		},
+		InformingJobRunResults: map[string][]status.JobRunResults{
+			"e2e-parallel": {{OverallResult: status.JobSucceeded}},
+		},
+	}
+
+	actual := NewCIComparison(environmentRelease, otherEnvironmentRelease, DefaultSignificanceLevel)
+	assert.Equal(t, DefaultSignificanceLevel, actual.SignificanceLevel)
+
+	// variants of either release are compared, blocking first.  Infrastructure failures count against a variant.
+	require.Len(t, actual.JobVariants, 2)
+	assert.Equal(t, "bare-minimum", actual.JobVariants[0].Name)
+	assert.Equal(t, string(JobImpactBlocking), actual.JobVariants[0].Category)
+	assert.Equal(t, 11, actual.JobVariants[0].Runs)
+	assert.Equal(t, 1, actual.JobVariants[0].Succeeded)
+	assert.Equal(t, status.ComparisonNoSignificantChange, actual.JobVariants[0].Verdict)
+	assert.Equal(t, "e2e-parallel", actual.JobVariants[1].Name)
+	assert.Equal(t, string(JobImpactInforming), actual.JobVariants[1].Category)
+	assert.Equal(t, 0, actual.JobVariants[1].Runs)
+	assert.Equal(t, status.ComparisonNoSignificantChange, actual.JobVariants[1].Verdict)
+
+	// only significant changes are listed, most significant first.  Tests are counted across every variant.
+	require.Len(t, actual.Tests, 2)
+	assert.Equal(t, "t1", actual.Tests[0].Name)
+	assert.Equal(t, status.ComparisonRegressed, actual.Tests[0].Verdict)
+	assert.Equal(t, 10, actual.Tests[0].Runs)
+	assert.Equal(t, 1, actual.Tests[0].Succeeded)
+	assert.Equal(t, 11, actual.Tests[0].OtherRuns)
+	assert.Equal(t, 11, actual.Tests[0].OtherSucceeded)
+	assert.Equal(t, "t2", actual.Tests[1].Name)
+	assert.Equal(t, status.ComparisonImproved, actual.Tests[1].Verdict)
+	assert.LessOrEqual(t, actual.Tests[0].PValue, actual.Tests[1].PValue)
+}
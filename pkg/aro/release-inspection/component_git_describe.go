@@ -0,0 +1,313 @@
+package release_inspection
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
+)
+
+// SourceDescription places a SHA in the history of its repository.
+type SourceDescription struct {
+	// Branches lists every branch containing the SHA, the master branch first.
+	Branches []string
+	// Describe is the nearest tag in git describe --tags form, for instance v0.1.52-12-gabc1234.  Empty when no tag
+	// is reachable.
+	Describe string
+}
+
+// String returns something like "v0.1.52-12-gabc1234 on release-4.18".
+func (d *SourceDescription) String() string {
+	if d == nil {
+		return ""
+	}
+	switch {
+	case len(d.Describe) > 0 && len(d.Branches) > 0:
+		return fmt.Sprintf("%s on %s", d.Describe, d.Branches[0])
+	case len(d.Describe) > 0:
+		return d.Describe
+	case len(d.Branches) > 0:
+		return fmt.Sprintf("on %s", d.Branches[0])
+	default:
+		return ""
+	}
+}
+
+func (c *componentGitAccessor) clearDescriptions() {
+	c.descriptionLock.Lock()
+	defer c.descriptionLock.Unlock()
+	c.shaToDescription = map[string]*SourceDescription{}
+}
+
+func (c *componentGitAccessor) DescribeSHA(ctx context.Context, sha string) (*SourceDescription, error) {
+	c.descriptionLock.Lock()
+	cached, ok := c.shaToDescription[sha]
+	c.descriptionLock.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "repoDir", c.repoDir, "sha", sha)
+
+	if _, err := os.Stat(c.repoDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("repository %s has not been cloned yet", c.repoURL)
+	}
+	componentRepo, err := git.PlainOpen(c.repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open component repo: %w", err)
+	}
+	commit, err := componentRepo.CommitObject(plumbing.NewHash(sha))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find commit %s: %w", sha, err)
+	}
+
+	branches, err := c.containingBranches(componentRepo, commit)
+	if err != nil {
+		return nil, err
+	}
+	describe, err := c.describe(componentRepo, commit)
+	if err != nil {
+		return nil, err
+	}
+	ret := &SourceDescription{
+		Branches: branches,
+		Describe: describe,
+	}
+	logger.V(2).Info("Described SHA", "description", ret.String())
+
+	c.descriptionLock.Lock()
+	defer c.descriptionLock.Unlock()
+	c.shaToDescription[sha] = ret
+	return ret, nil
+}
+
+// containingBranches returns the short names of the remote branches whose tip has commit as an ancestor.
+func (c *componentGitAccessor) containingBranches(componentRepo *git.Repository, commit *object.Commit) ([]string, error) {
+	refs, err := componentRepo.References()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list references: %w", err)
+	}
+	branchTips := map[string]plumbing.Hash{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		switch {
+		case ref.Name().IsRemote() && strings.HasPrefix(ref.Name().String(), "refs/remotes/origin/"):
+			branchTips[strings.TrimPrefix(ref.Name().String(), "refs/remotes/origin/")] = ref.Hash()
+		case ref.Name().IsBranch():
+			// bare clones keep the default branch here.
+			if _, exists := branchTips[ref.Name().Short()]; !exists {
+				branchTips[ref.Name().Short()] = ref.Hash()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read references: %w", err)
+	}
+	delete(branchTips, "HEAD")
+
+	containing := set.New[string]()
+	for branch, tipHash := range branchTips {
+		if tipHash == commit.Hash {
+			containing.Insert(branch)
+			continue
+		}
+		tip, err := componentRepo.CommitObject(tipHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tip of %s: %w", branch, err)
+		}
+		isAncestor, err := commit.IsAncestor(tip)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check whether %s contains %s: %w", branch, commit.Hash, err)
+		}
+		if isAncestor {
+			containing.Insert(branch)
+		}
+	}
+
+	ret := containing.SortedList()
+	if containing.Has(c.masterBranch) {
+		ret = append([]string{c.masterBranch}, containing.Delete(c.masterBranch).SortedList()...)
+	}
+	return ret, nil
+}
+
+// maxDescribeCandidates is how many of the nearest tagged commits are compared, like git describe's default.
+const maxDescribeCandidates = 10
+
+// describe mimics git describe --tags --abbrev=7.  The tagged commits met first walking back from commit are the
+// candidates, and the one with the fewest commits in git rev-list tag..commit wins.  Like git, ties go to the
+// candidate met first.
+func (c *componentGitAccessor) describe(componentRepo *git.Repository, commit *object.Commit) (string, error) {
+	tags, err := componentRepo.Tags()
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %w", err)
+	}
+	commitToTags := map[plumbing.Hash][]string{}
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		commitHash := ref.Hash()
+		// annotated tags point to a tag object rather than the commit.
+		if tagObject, err := componentRepo.TagObject(ref.Hash()); err == nil {
+			tagCommit, err := tagObject.Commit()
+			if err != nil {
+				return nil
+			}
+			commitHash = tagCommit.Hash
+		}
+		commitToTags[commitHash] = append(commitToTags[commitHash], ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read tags: %w", err)
+	}
+	if len(commitToTags) == 0 {
+		return "", nil
+	}
+
+	candidates, err := c.describeCandidates(componentRepo, commit, commitToTags)
+	if err != nil {
+		return "", err
+	}
+
+	bestTagName, bestDistance := "", -1
+	for _, candidate := range candidates {
+		distance, err := countCommitsBetween(componentRepo, candidate, commit, c.maxCommitSearchDepth)
+		if err != nil {
+			return "", err
+		}
+		if distance < 0 || (bestDistance >= 0 && distance >= bestDistance) {
+			continue
+		}
+		// when several tags point at the same commit, prefer the highest.
+		tagNames := commitToTags[candidate.Hash]
+		sort.Strings(tagNames)
+		bestTagName, bestDistance = tagNames[len(tagNames)-1], distance
+	}
+	switch {
+	case bestDistance < 0:
+		return "", nil
+	case bestDistance == 0:
+		return bestTagName, nil
+	default:
+		return fmt.Sprintf("%s-%d-g%s", bestTagName, bestDistance, commit.Hash.String()[:7]), nil
+	}
+}
+
+// describeCandidates returns the first tagged commits met walking back from commit, newest commit time first.
+func (c *componentGitAccessor) describeCandidates(componentRepo *git.Repository, commit *object.Commit, commitToTags map[plumbing.Hash][]string) ([]*object.Commit, error) {
+	commitLog, err := componentRepo.Log(&git.LogOptions{From: commit.Hash, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git log: %w", err)
+	}
+	defer commitLog.Close()
+
+	ret := []*object.Commit{}
+	for walked := 0; len(ret) < maxDescribeCandidates && (c.maxCommitSearchDepth <= 0 || walked < c.maxCommitSearchDepth); walked++ {
+		curr, err := commitLog.Next()
+		if errors.Is(err, io.EOF) || errors.Is(err, storer.ErrStop) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read git log: %w", err)
+		}
+		if _, ok := commitToTags[curr.Hash]; ok {
+			ret = append(ret, curr)
+			if curr.Hash == commit.Hash {
+				// a tag on the commit itself can't be beaten.
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+const (
+	reachableFromNewer = 1 << iota
+	reachableFromOlder
+)
+
+// countCommitsBetween counts the commits reachable from newer but not from older, like
+// git rev-list --count older..newer.  Commits are visited newest commit time first, so the walk stops once only commits
+// reachable from older are left.  It returns -1 when more than maxCount commits would be counted and maxCount is
+// positive.
+func countCommitsBetween(componentRepo *git.Repository, older, newer *object.Commit, maxCount int) (int, error) {
+	flags := map[plumbing.Hash]int{
+		newer.Hash: reachableFromNewer,
+		older.Hash: reachableFromOlder,
+	}
+	queue := &commitTimeQueue{}
+	heap.Push(queue, newer)
+	if older.Hash != newer.Hash {
+		heap.Push(queue, older)
+	} else {
+		flags[newer.Hash] |= reachableFromOlder
+	}
+
+	count := 0
+	for queue.Len() > 0 && queue.hasInteresting(flags) {
+		curr := heap.Pop(queue).(*object.Commit)
+		currFlags := flags[curr.Hash]
+		if currFlags == reachableFromNewer {
+			count++
+			if maxCount > 0 && count > maxCount {
+				return -1, nil
+			}
+		}
+
+		err := curr.Parents().ForEach(func(parent *object.Commit) error {
+			parentFlags, seen := flags[parent.Hash]
+			if parentFlags|currFlags == parentFlags && seen {
+				return nil
+			}
+			flags[parent.Hash] = parentFlags | currFlags
+			if !seen {
+				heap.Push(queue, parent)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to read parents of %s: %w", curr.Hash, err)
+		}
+	}
+	return count, nil
+}
+
+// commitTimeQueue pops the commit with the newest commit time first.
+type commitTimeQueue []*object.Commit
+
+func (q commitTimeQueue) Len() int           { return len(q) }
+func (q commitTimeQueue) Less(i, j int) bool { return q[i].Committer.When.After(q[j].Committer.When) }
+func (q commitTimeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *commitTimeQueue) Push(x any)        { *q = append(*q, x.(*object.Commit)) }
+func (q *commitTimeQueue) Pop() any {
+	old := *q
+	ret := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return ret
+}
+
+// hasInteresting is false once every queued commit is reachable from older, none of their ancestors can be counted.
+func (q commitTimeQueue) hasInteresting(flags map[plumbing.Hash]int) bool {
+	for _, commit := range q {
+		if flags[commit.Hash]&reachableFromOlder == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package release_inspection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeSHA(t *testing.T) {
+	repo := newTestGitRepository(t)
+	base := repo.commit("base")
+	repo.tag("v0.1.0", base)
+	mainline := repo.commit("on main", base)
+	side1 := repo.commit("side 1", base)
+	repo.tag("v0.2.0-rc", side1)
+	side2 := repo.commit("side 2", side1)
+	merged := repo.commit("Merge pull request #1", mainline, side2)
+	afterMerge := repo.commit("after merge", merged)
+
+	tests := []struct {
+		name     string
+		sha      string
+		expected string
+	}{
+		{name: "tagged commit", sha: base.String(), expected: "v0.1.0"},
+		{name: "linear", sha: mainline.String(), expected: "v0.1.0-1-g" + mainline.String()[:7]},
+		// v0.2.0-rc..merge is the merge, side 2 and on main.  v0.1.0..merge would add side 1.
+		{name: "nearest tag across a merge", sha: merged.String(), expected: "v0.2.0-rc-3-g" + merged.String()[:7]},
+		{name: "after a merge", sha: afterMerge.String(), expected: "v0.2.0-rc-4-g" + afterMerge.String()[:7]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor := newComponentGitAccessor("Frontend", "https://github.com/Azure/ARO-HCP", repo.dir, "main", 0)
+			description, err := accessor.DescribeSHA(context.Background(), tt.sha)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, description.Describe)
+		})
+	}
+}
+
+func TestCountCommitsBetween(t *testing.T) {
+	repo := newTestGitRepository(t)
+	base := repo.commit("base")
+	head := base
+	for _, message := range []string{"Merge pull request #1", "Merge pull request #2", "Merge pull request #3"} {
+		head = repo.merge(head, message)
+	}
+	componentRepo, err := git.PlainOpen(repo.dir)
+	require.NoError(t, err)
+	baseCommit, err := componentRepo.CommitObject(base)
+	require.NoError(t, err)
+	headCommit, err := componentRepo.CommitObject(head)
+	require.NoError(t, err)
+
+	// each merge brings itself and its change, like git rev-list --count base..head.
+	count, err := countCommitsBetween(componentRepo, baseCommit, headCommit, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 6, count)
+
+	count, err = countCommitsBetween(componentRepo, headCommit, baseCommit, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	count, err = countCommitsBetween(componentRepo, baseCommit, headCommit, 5)
+	require.NoError(t, err)
+	assert.Equal(t, -1, count, "over the limit")
+}
@@ -15,27 +15,55 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
 )
 
 type ComponentsGitInfo interface {
 	GetComponentGitAccessor(ctx context.Context, componentName string) (ComponentGitAccessor, error)
+	// ListRefreshableRepositories returns every component mirror so they can be kept up to date in the background.
+	ListRefreshableRepositories() []RefreshableRepository
 }
 
 type ComponentGitAccessor interface {
-	GetDiffForSHAs(ctx context.Context, newerSHA, olderSHA string, topN int) ([]*object.Commit, error)
+	// GetDiffForSHAs returns at most topN merge commits reachable from newerSHA and not from olderSHA, newest first,
+	// after skipping the newest skip of them.  A topN less than one returns every remaining merge commit.  It only reads
+	// the local mirror, fetching is done by Refresh.
+	GetDiffForSHAs(ctx context.Context, newerSHA, olderSHA string, skip, topN int) (*CommitDiff, error)
+	// DescribeSHA finds the branches containing sha and the nearest tag, like git describe --tags.
+	DescribeSHA(ctx context.Context, sha string) (*SourceDescription, error)
 }
 
+// CommitDiff is the result of walking history from a newer SHA back to an older one.
+type CommitDiff struct {
+	// MergeCommits holds at most topN merge commits after the skipped ones, newest first.
+	MergeCommits []*object.Commit
+	// TotalMergeCommits counts every merge commit between the two SHAs, including the ones beyond topN.
+	TotalMergeCommits int
+	// TotalCommits counts every commit between the two SHAs.
+	TotalCommits int
+	// SearchDepthExceeded is set when the older SHA wasn't found within the search depth.  The totals then only count
+	// the commits walked, there are more.
+	SearchDepthExceeded bool
+}
+
+// DefaultMaxCommitSearchDepth is deep enough for a component that wasn't promoted in months, and shallow enough that a
+// reversed or unrelated pair of SHAs doesn't walk the whole history of a large repository on every diff.
+const DefaultMaxCommitSearchDepth = 10000
+
 type componentsGitInfo struct {
 	repoParentDir string
+	// maxCommitSearchDepth bounds how far back we walk looking for the older SHA.  Zero means no bound.
+	maxCommitSearchDepth int
 
 	lock              sync.Mutex
 	componentGitInfos map[string]*componentGitAccessor
 }
 
-func NewComponentsGitInfo(repoParentDir string) ComponentsGitInfo {
+func NewComponentsGitInfo(repoParentDir string, maxCommitSearchDepth int) ComponentsGitInfo {
 	return &componentsGitInfo{
-		repoParentDir:     repoParentDir,
-		componentGitInfos: map[string]*componentGitAccessor{},
+		repoParentDir:        repoParentDir,
+		maxCommitSearchDepth: maxCommitSearchDepth,
+		componentGitInfos:    map[string]*componentGitAccessor{},
 	}
 }
 
@@ -43,95 +71,159 @@ func (c *componentsGitInfo) GetComponentGitAccessor(ctx context.Context, compone
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	return c.getComponentGitAccessorLocked(componentName), nil
+}
+
+func (c *componentsGitInfo) getComponentGitAccessorLocked(componentName string) *componentGitAccessor {
 	ret, exists := c.componentGitInfos[componentName]
 	if !exists {
 		repoDir := filepath.Join(c.repoParentDir, strings.ReplaceAll(componentName, " ", "-"))
-		ret = newComponentGitAccessor(HardcodedComponents[componentName].RepositoryURL, repoDir, HardcodedComponents[componentName].MasterBranch)
+		ret = newComponentGitAccessor(componentName, HardcodedComponents[componentName].RepositoryURL, repoDir, HardcodedComponents[componentName].MasterBranch, c.maxCommitSearchDepth)
 		c.componentGitInfos[componentName] = ret
 	}
-	return c.componentGitInfos[componentName], nil
+	return ret
+}
+
+func (c *componentsGitInfo) ListRefreshableRepositories() []RefreshableRepository {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ret := []RefreshableRepository{}
+	for _, componentName := range set.KeySet(HardcodedComponents).SortedList() {
+		if len(HardcodedComponents[componentName].RepositoryURL) == 0 {
+			continue
+		}
+		ret = append(ret, c.getComponentGitAccessorLocked(componentName))
+	}
+	return ret
 }
 
 type componentGitAccessor struct {
-	lock sync.Mutex
+	// refreshLock serializes fetches.  Fetching only adds objects and moves remote refs, so readers don't wait for it.
+	refreshLock sync.Mutex
+	// lock protects the existence of the repository directory.  Readers hold it for read, the initial clone moves the
+	// directory into place while holding it for write.
+	lock sync.RWMutex
 
-	repoDir      string
-	repoURL      string
-	masterBranch string
+	componentName        string
+	repoDir              string
+	repoURL              string
+	masterBranch         string
+	maxCommitSearchDepth int
+
+	// descriptionLock protects shaToDescription.  Branches move on every fetch, so Refresh clears it.
+	descriptionLock  sync.Mutex
+	shaToDescription map[string]*SourceDescription
 }
 
-func newComponentGitAccessor(repoURL, repoDir, masterBranch string) *componentGitAccessor {
+func newComponentGitAccessor(componentName, repoURL, repoDir, masterBranch string, maxCommitSearchDepth int) *componentGitAccessor {
 	return &componentGitAccessor{
-		repoDir:      repoDir,
-		repoURL:      repoURL,
-		masterBranch: masterBranch,
+		componentName:        componentName,
+		repoDir:              repoDir,
+		repoURL:              repoURL,
+		masterBranch:         masterBranch,
+		maxCommitSearchDepth: maxCommitSearchDepth,
+		shaToDescription:     map[string]*SourceDescription{},
 	}
 }
 
-func (c *componentGitAccessor) GetDiffForSHAs(ctx context.Context, newerSHA, olderSHA string, topN int) ([]*object.Commit, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+func (c *componentGitAccessor) Name() string {
+	return c.componentName
+}
 
-	logger := klog.LoggerWithValues(klog.FromContext(ctx), "repoDir", c.repoDir, "newSHA", newerSHA, "oldSHA", olderSHA)
+func (c *componentGitAccessor) URL() string {
+	return c.repoURL
+}
+
+// Refresh clones the repository if it doesn't exist yet and fetches the latest from origin otherwise.
+// Every branch and tag is fetched because components are often built from release branches.
+func (c *componentGitAccessor) Refresh(ctx context.Context) error {
+	c.refreshLock.Lock()
+	defer c.refreshLock.Unlock()
+	defer c.clearDescriptions()
+
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "repoDir", c.repoDir)
 	ctx = klog.NewContext(ctx, logger)
 
 	fileInfo, err := os.Stat(c.repoDir)
 	switch {
 	case err == nil:
-		// here we need to fetch the origin main or master
 		if !fileInfo.IsDir() {
-			return nil, fmt.Errorf("repository path %s is not a directory", c.repoDir)
+			return fmt.Errorf("repository path %s is not a directory", c.repoDir)
 		}
 
-		logger.Info("Fetching latest from origin", "branchName", c.masterBranch)
+		logger.Info("Fetching latest from origin")
 		componentRepo, err := git.PlainOpen(c.repoDir)
 		if err != nil {
-			return nil, fmt.Errorf("failed to open existing repository: %w", err)
+			return fmt.Errorf("failed to open existing repository: %w", err)
 		}
-		err = componentRepo.Fetch(&git.FetchOptions{
+		err = componentRepo.FetchContext(ctx, &git.FetchOptions{
 			InsecureSkipTLS: true, // TODO don't do this if we start sending credentials
 			RemoteName:      "origin",
-			Progress:        os.Stdout, // TODO wire up to a logger
 			RefSpecs: []config.RefSpec{
-				config.RefSpec(fmt.Sprintf("refs/heads/%s:refs/remotes/origin/%s", c.masterBranch, c.masterBranch)),
+				"+refs/heads/*:refs/remotes/origin/*",
+				"+refs/tags/*:refs/tags/*",
 			},
+			Tags: git.AllTags,
 		})
 		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
-			return nil, fmt.Errorf("failed to fetch origin/main: %w", err)
+			return fmt.Errorf("failed to fetch origin: %w", err)
+		}
+		return nil
+
+	case os.IsNotExist(err):
+		logger.Info("Cloning repo", "branchName", c.masterBranch)
+		// clone next to the final location so readers never see a partial clone.
+		cloneDir := c.repoDir + ".partial"
+		if err := os.RemoveAll(cloneDir); err != nil {
+			return fmt.Errorf("failed to remove stale partial clone: %w", err)
 		}
-		worktree, err := componentRepo.Worktree()
+		componentRepo, err := git.PlainCloneContext(ctx, cloneDir, true, &git.CloneOptions{
+			InsecureSkipTLS: true, // TODO don't do this if we start sending credentials
+			URL:             c.repoURL,
+			Tags:            git.AllTags,
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to get worktree: %w", err)
+			return fmt.Errorf("failed to clone repository: %w", err)
 		}
-		err = worktree.Pull(&git.PullOptions{
+		// a bare clone only maps the default branch, fetch the rest before anyone can read the repository.
+		err = componentRepo.FetchContext(ctx, &git.FetchOptions{
 			InsecureSkipTLS: true, // TODO don't do this if we start sending credentials
 			RemoteName:      "origin",
-			ReferenceName:   plumbing.ReferenceName(fmt.Sprintf("refs/heads/%s", c.masterBranch)),
+			RefSpecs: []config.RefSpec{
+				"+refs/heads/*:refs/remotes/origin/*",
+			},
 		})
 		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
-			return nil, fmt.Errorf("failed to fast-forward main branch: %w", err)
+			return fmt.Errorf("failed to fetch branches: %w", err)
 		}
 
-	case os.IsNotExist(err):
-		logger.Info("Cloning repo", "branchName", c.masterBranch)
-		// clone the repo and then close it.
-		_, err := git.PlainCloneContext(ctx, c.repoDir, false, &git.CloneOptions{
-			InsecureSkipTLS: true,      // TODO don't do this if we start sending credentials
-			Progress:        os.Stdout, // TODO wire up to a logger
-			URL:             c.repoURL,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to clone repository: %w", err)
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		if err := os.Rename(cloneDir, c.repoDir); err != nil {
+			return fmt.Errorf("failed to move clone into place: %w", err)
 		}
+		return nil
+
+	default:
+		return fmt.Errorf("failed to get repository directory info: %w", err)
+	}
+}
+
+func (c *componentGitAccessor) GetDiffForSHAs(ctx context.Context, newerSHA, olderSHA string, skip, topN int) (*CommitDiff, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "repoDir", c.repoDir, "newSHA", newerSHA, "oldSHA", olderSHA)
 
-	case err != nil:
-		return nil, fmt.Errorf("failed to get repository directory info: %w", err)
+	if _, err := os.Stat(c.repoDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("repository %s has not been cloned yet", c.repoURL)
 	}
 
 	logger.Info("Getting log")
 	componentRepo, err := git.PlainOpen(c.repoDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open aro hcp repo: %w", err)
+		return nil, fmt.Errorf("failed to open component repo: %w", err)
 	}
 
 	newerHash, err := componentRepo.ResolveRevision(plumbing.Revision(newerSHA))
@@ -144,10 +236,11 @@ func (c *componentGitAccessor) GetDiffForSHAs(ctx context.Context, newerSHA, old
 	if err != nil {
 		return nil, fmt.Errorf("failed to get git log: %w", err)
 	}
+	defer commitLog.Close()
 
-	var commits []*object.Commit
+	ret := &CommitDiff{}
 	reachedOlder := false
-	for i := 0; i < 1000; i++ {
+	for c.maxCommitSearchDepth <= 0 || ret.TotalCommits < c.maxCommitSearchDepth {
 		commit, err := commitLog.Next()
 		if errors.Is(err, io.EOF) {
 			break
@@ -159,14 +252,31 @@ func (c *componentGitAccessor) GetDiffForSHAs(ctx context.Context, newerSHA, old
 			reachedOlder = true
 			break
 		}
-		commits = append(commits, commit)
+		ret.TotalCommits++
+		if commit.NumParents() < 2 {
+			continue
+		}
+		ret.TotalMergeCommits++
+		if ret.TotalMergeCommits <= skip {
+			continue
+		}
+		if topN < 1 || len(ret.MergeCommits) < topN {
+			ret.MergeCommits = append(ret.MergeCommits, commit)
+		}
 	}
 
 	if !reachedOlder {
-		return nil, fmt.Errorf("older SHA %s not found in commit history of main branch within 1000 commits", olderSHA)
+		// a reversed or distant pair of SHAs still reports what was found instead of failing.
+		if c.maxCommitSearchDepth > 0 && ret.TotalCommits >= c.maxCommitSearchDepth {
+			logger.Info("Older SHA not found within the search depth", "maxCommitSearchDepth", c.maxCommitSearchDepth)
+			ret.SearchDepthExceeded = true
+			return ret, nil
+		}
+		return nil, fmt.Errorf("older SHA %s not found in commit history of %s", olderSHA, newerSHA)
 	}
+	logger.Info("Found changes", "totalCommits", ret.TotalCommits, "totalMergeCommits", ret.TotalMergeCommits)
 
-	return commits, nil
+	return ret, nil
 }
 
 type dummyComponentsGitInfo struct {
@@ -180,9 +290,17 @@ func (c *dummyComponentsGitInfo) GetComponentGitAccessor(ctx context.Context, co
 	return &dummyComponentGitAccessor{}, nil
 }
 
+func (c *dummyComponentsGitInfo) ListRefreshableRepositories() []RefreshableRepository {
+	return nil
+}
+
 type dummyComponentGitAccessor struct {
 }
 
-func (c *dummyComponentGitAccessor) GetDiffForSHAs(ctx context.Context, newerSHA, olderSHA string, topN int) ([]*object.Commit, error) {
-	return nil, nil
+func (c *dummyComponentGitAccessor) GetDiffForSHAs(ctx context.Context, newerSHA, olderSHA string, skip, topN int) (*CommitDiff, error) {
+	return &CommitDiff{}, nil
+}
+
+func (c *dummyComponentGitAccessor) DescribeSHA(ctx context.Context, sha string) (*SourceDescription, error) {
+	return &SourceDescription{}, nil
 }
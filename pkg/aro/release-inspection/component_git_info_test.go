@@ -0,0 +1,110 @@
+package release_inspection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGitRepository builds histories for tests.  Commits are a minute apart so their order is stable.
+type testGitRepository struct {
+	t        *testing.T
+	dir      string
+	repo     *git.Repository
+	worktree *git.Worktree
+	when     time.Time
+}
+
+func newTestGitRepository(t *testing.T) *testGitRepository {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	return &testGitRepository{t: t, dir: dir, repo: repo, worktree: worktree, when: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// commit creates an empty commit.  Without parents it follows HEAD.
+func (r *testGitRepository) commit(message string, parents ...plumbing.Hash) plumbing.Hash {
+	r.when = r.when.Add(time.Minute)
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: r.when}
+	hash, err := r.worktree.Commit(message, &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            signature,
+		Committer:         signature,
+		Parents:           parents,
+	})
+	require.NoError(r.t, err)
+	return hash
+}
+
+// merge commits a change on a branch off mainline and merges it back, like a merged pull request.
+func (r *testGitRepository) merge(mainline plumbing.Hash, message string) plumbing.Hash {
+	change := r.commit("change for "+message, mainline)
+	return r.commit(message, mainline, change)
+}
+
+// tag creates a lightweight tag.
+func (r *testGitRepository) tag(name string, hash plumbing.Hash) {
+	_, err := r.repo.CreateTag(name, hash, nil)
+	require.NoError(r.t, err)
+}
+
+func TestGetDiffForSHAsPages(t *testing.T) {
+	repo := newTestGitRepository(t)
+	base := repo.commit("base")
+	merges := []plumbing.Hash{}
+	head := base
+	for _, message := range []string{"Merge pull request #1", "Merge pull request #2", "Merge pull request #3", "Merge pull request #4", "Merge pull request #5"} {
+		head = repo.merge(head, message)
+		merges = append(merges, head)
+	}
+	accessor := newComponentGitAccessor("Frontend", "https://github.com/Azure/ARO-HCP", repo.dir, "main", 0)
+
+	commitDiff, err := accessor.GetDiffForSHAs(context.Background(), head.String(), base.String(), 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, commitDiff.TotalMergeCommits)
+	require.Len(t, commitDiff.MergeCommits, 2)
+	// newest first, after skipping #5 and #4.
+	assert.Equal(t, merges[2], commitDiff.MergeCommits[0].Hash)
+	assert.Equal(t, merges[1], commitDiff.MergeCommits[1].Hash)
+
+	commitDiff, err = accessor.GetDiffForSHAs(context.Background(), head.String(), base.String(), 4, 2)
+	require.NoError(t, err)
+	require.Len(t, commitDiff.MergeCommits, 1)
+	assert.Equal(t, merges[0], commitDiff.MergeCommits[0].Hash)
+}
+
+func TestGetDiffForSHAsSearchDepth(t *testing.T) {
+	repo := newTestGitRepository(t)
+	base := repo.commit("base")
+	head := base
+	for _, message := range []string{"Merge pull request #1", "Merge pull request #2", "Merge pull request #3"} {
+		head = repo.merge(head, message)
+	}
+	// the walk follows the merges back to the base, three commits.
+	accessor := newComponentGitAccessor("Frontend", "https://github.com/Azure/ARO-HCP", repo.dir, "main", 2)
+
+	commitDiff, err := accessor.GetDiffForSHAs(context.Background(), head.String(), base.String(), 0, 0)
+	require.NoError(t, err)
+	assert.True(t, commitDiff.SearchDepthExceeded)
+	assert.Equal(t, 2, commitDiff.TotalCommits)
+	assert.Equal(t, 2, commitDiff.TotalMergeCommits)
+	assert.Len(t, commitDiff.MergeCommits, 2)
+
+	// a reversed pair reaches the root, so it fails rather than reporting a count.
+	_, err = accessor.GetDiffForSHAs(context.Background(), base.String(), head.String(), 0, 0)
+	assert.ErrorContains(t, err, "not found in commit history")
+
+	accessor = newComponentGitAccessor("Frontend", "https://github.com/Azure/ARO-HCP", repo.dir, "main", 4)
+	commitDiff, err = accessor.GetDiffForSHAs(context.Background(), head.String(), base.String(), 0, 0)
+	require.NoError(t, err)
+	assert.False(t, commitDiff.SearchDepthExceeded)
+	assert.Equal(t, 3, commitDiff.TotalMergeCommits)
+}
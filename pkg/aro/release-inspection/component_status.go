@@ -0,0 +1,138 @@
+package release_inspection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/metrics"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+)
+
+// ListComponents answers "what version of each component runs where" from the latest release of every environment.
+func (r *releaseAccessor) ListComponents(ctx context.Context) (*status.ComponentStatusList, error) {
+	logger := klog.FromContext(ctx)
+	logger.Info("ListComponents entry")
+
+	environments, err := r.selfLookupInstance.ListEnvironments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	environmentToLatestRelease := map[string]*status.EnvironmentRelease{}
+	componentNames := set.New[string]()
+	for _, environment := range environments {
+		environmentReleases, err := r.selfLookupInstance.ListEnvironmentReleasesForEnvironment(ctx, environment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environment releases for %q: %w", environment, err)
+		}
+		if len(environmentReleases.Items) == 0 {
+			continue
+		}
+		latest := environmentReleases.Items[0]
+		environmentToLatestRelease[environment] = &latest
+		componentNames.Insert(set.KeySet(latest.Components).UnsortedList()...)
+	}
+
+	ret := &status.ComponentStatusList{
+		TypeMeta: status.TypeMeta{
+			Kind:       "ComponentStatusList",
+			APIVersion: "service-status.hcm.openshift.io/v1",
+		},
+		Items: []status.ComponentStatus{},
+	}
+	for _, componentName := range componentNames.SortedList() {
+		ret.Items = append(ret.Items, r.componentStatus(ctx, componentName, environments, environmentToLatestRelease))
+	}
+	return ret, nil
+}
+
+func (r *releaseAccessor) componentStatus(ctx context.Context, componentName string, environments []string, environmentToLatestRelease map[string]*status.EnvironmentRelease) status.ComponentStatus {
+	ret := status.ComponentStatus{
+		TypeMeta: status.TypeMeta{
+			Kind:       "ComponentStatus",
+			APIVersion: "service-status.hcm.openshift.io/v1",
+		},
+		Name:         componentName,
+		Environments: []status.ComponentEnvironmentStatus{},
+	}
+
+	for _, environment := range environments {
+		latest := environmentToLatestRelease[environment]
+		if latest == nil || latest.Components[componentName] == nil {
+			continue
+		}
+		component := latest.Components[componentName]
+		if ret.RepoURL == nil {
+			ret.RepoURL = component.RepoURL
+		}
+
+		environmentStatus := status.ComponentEnvironmentStatus{
+			Environment:              environment,
+			EnvironmentReleaseName:   latest.Name,
+			Image:                    component.ImageInfo,
+			SourceSHA:                component.SourceSHA,
+			PermanentURLForSourceSHA: component.PermanentURLForSourceSHA,
+			SourceDescription:        component.SourceDescription,
+			ImageCreationTime:        component.ImageCreationTime,
+			PreviousEnvironment:      EnvironmentToPreviousEnvironment[environment],
+			Conditions:               component.Conditions,
+		}
+		if previousLatest := environmentToLatestRelease[environmentStatus.PreviousEnvironment]; previousLatest != nil {
+			environmentStatus.CommitsBehindPreviousEnvironment = r.commitsBehind(ctx, component, previousLatest.Components[componentName])
+		}
+		ret.Environments = append(ret.Environments, environmentStatus)
+	}
+	return ret
+}
+
+// commitsBehind counts the commits of previousComponent's source that component doesn't have yet.  It is nil when
+// that can't be known.
+func (r *releaseAccessor) commitsBehind(ctx context.Context, component, previousComponent *status.Component) *int {
+	if previousComponent == nil {
+		return nil
+	}
+	if component.ImageInfo.Digest == previousComponent.ImageInfo.Digest {
+		return ptr.To(0)
+	}
+	if len(component.SourceSHA) == 0 || len(previousComponent.SourceSHA) == 0 {
+		return nil
+	}
+	if component.SourceSHA == previousComponent.SourceSHA {
+		return ptr.To(0)
+	}
+
+	logger := klog.FromContext(ctx)
+	gitAccessor, err := r.componentGitAccessor.GetComponentGitAccessor(ctx, component.Name)
+	if err != nil {
+		logger.V(2).Info("failed to get component git accessor", "component", component.Name, "err", err)
+		return nil
+	}
+	diffStartTime := time.Now()
+	commitDiff, err := gitAccessor.GetDiffForSHAs(ctx, previousComponent.SourceSHA, component.SourceSHA, 0, 1)
+	metrics.ObserveExternalCall("git", "diff", diffStartTime, err)
+	if err != nil {
+		logger.V(2).Info("failed to count commits behind", "component", component.Name, "err", err)
+		return nil
+	}
+	// only a lower bound is known, and it isn't worth showing as the gap.
+	if commitDiff.SearchDepthExceeded {
+		return nil
+	}
+	return ptr.To(commitDiff.TotalCommits)
+}
+
+// WithImageAges sets the image ages as of now.  The component status may be shared with a cache, so it is copied.
+func WithImageAges(now time.Time, componentStatus status.ComponentStatus) status.ComponentStatus {
+	ret := componentStatus
+	ret.Environments = append([]status.ComponentEnvironmentStatus{}, componentStatus.Environments...)
+	for i := range ret.Environments {
+		if ret.Environments[i].ImageCreationTime != nil {
+			ret.Environments[i].ImageAge = now.Sub(*ret.Environments[i].ImageCreationTime).Round(time.Minute).String()
+		}
+	}
+	return ret
+}
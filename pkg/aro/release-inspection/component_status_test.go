@@ -0,0 +1,79 @@
+package release_inspection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+type fakeEnvironmentReleasesAccessor struct {
+	ReleaseAccessor
+	environmentToReleases map[string]*status.EnvironmentReleaseList
+}
+
+func (a *fakeEnvironmentReleasesAccessor) ListEnvironments(ctx context.Context) ([]string, error) {
+	return []string{"int", "stg", "prod"}, nil
+}
+
+func (a *fakeEnvironmentReleasesAccessor) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error) {
+	if ret, ok := a.environmentToReleases[environment]; ok {
+		return ret, nil
+	}
+	return &status.EnvironmentReleaseList{}, nil
+}
+
+type fakeComponentsGitInfo struct {
+	ComponentsGitInfo
+}
+
+func (fakeComponentsGitInfo) GetComponentGitAccessor(ctx context.Context, componentName string) (ComponentGitAccessor, error) {
+	return fakeComponentGitAccessor{}, nil
+}
+
+type fakeComponentGitAccessor struct {
+	ComponentGitAccessor
+}
+
+func (fakeComponentGitAccessor) GetDiffForSHAs(ctx context.Context, newerSHA, olderSHA string, skip, topN int) (*CommitDiff, error) {
+	return &CommitDiff{TotalCommits: 3}, nil
+}
+
+func TestListComponents(t *testing.T) {
+	release := func(environment, digest, sourceSHA string) *status.EnvironmentReleaseList {
+		return &status.EnvironmentReleaseList{Items: []status.EnvironmentRelease{{
+			Name:        environment + "---latest",
+			Environment: environment,
+			Components: map[string]*status.Component{
+				"Cluster Service": {
+					Name:      "Cluster Service",
+					RepoURL:   ptr.To("https://example.com/cs"),
+					ImageInfo: status.ContainerImage{Digest: digest},
+					SourceSHA: sourceSHA,
+				},
+			},
+		}}}
+	}
+	accessor := &releaseAccessor{componentGitAccessor: fakeComponentsGitInfo{}}
+	accessor.SetSelfLookupInstance(&fakeEnvironmentReleasesAccessor{
+		environmentToReleases: map[string]*status.EnvironmentReleaseList{
+			"int":  release("int", "sha256:new", "newsha"),
+			"stg":  release("stg", "sha256:new", "newsha"),
+			"prod": release("prod", "sha256:old", "oldsha"),
+		},
+	})
+
+	components, err := accessor.ListComponents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, components.Items, 1)
+	clusterService := components.Items[0]
+	assert.Equal(t, "https://example.com/cs", *clusterService.RepoURL)
+	require.Len(t, clusterService.Environments, 3)
+	assert.Nil(t, clusterService.Environments[0].CommitsBehindPreviousEnvironment, "int has nothing to be promoted from")
+	assert.Equal(t, ptr.To(0), clusterService.Environments[1].CommitsBehindPreviousEnvironment)
+	assert.Equal(t, "stg", clusterService.Environments[2].PreviousEnvironment)
+	assert.Equal(t, ptr.To(3), clusterService.Environments[2].CommitsBehindPreviousEnvironment)
+}
@@ -0,0 +1,48 @@
+package release_inspection
+
+import (
+	"math"
+)
+
+// FisherExactTest returns the two-sided p-value of Fisher's exact test for the 2x2 contingency table
+//
+//	a b
+//	c d
+//
+// It is the probability, given the row and column totals, of a table at least as extreme as this one.
+func FisherExactTest(a, b, c, d int) float64 {
+	rowOne, rowTwo := a+b, c+d
+	colOne := a + c
+	total := a + b + c + d
+	if total == 0 {
+		return 1
+	}
+
+	// every table with the same margins is fixed by its top left cell.
+	minA := max(0, colOne-rowTwo)
+	maxA := min(rowOne, colOne)
+	observed := hypergeometricProbability(a, rowOne, rowTwo, colOne, total)
+
+	pValue := 0.0
+	for currA := minA; currA <= maxA; currA++ {
+		p := hypergeometricProbability(currA, rowOne, rowTwo, colOne, total)
+		// the tolerance keeps tables as likely as the observed one from being lost to rounding.
+		if p <= observed*(1+1e-7) {
+			pValue += p
+		}
+	}
+	return min(pValue, 1)
+}
+
+func hypergeometricProbability(a, rowOne, rowTwo, colOne, total int) float64 {
+	return math.Exp(logChoose(rowOne, a) + logChoose(rowTwo, colOne-a) - logChoose(total, colOne))
+}
+
+func logChoose(n, k int) float64 {
+	return logFactorial(n) - logFactorial(k) - logFactorial(n-k)
+}
+
+func logFactorial(n int) float64 {
+	ret, _ := math.Lgamma(float64(n) + 1)
+	return ret
+}
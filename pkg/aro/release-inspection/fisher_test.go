@@ -0,0 +1,25 @@
+package release_inspection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFisherExactTest(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b, c, d int
+		expected   float64
+	}{
+		{name: "strong association", a: 1, b: 9, c: 11, d: 3, expected: 0.002759},
+		{name: "identical rows", a: 5, b: 5, c: 5, d: 5, expected: 1},
+		{name: "tea tasting", a: 3, b: 1, c: 1, d: 3, expected: 0.485714},
+		{name: "empty", expected: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.InDelta(t, test.expected, FisherExactTest(test.a, test.b, test.c, test.d), 1e-6)
+		})
+	}
+}
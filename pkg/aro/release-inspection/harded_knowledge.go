@@ -5,6 +5,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"k8s.io/utils/set"
 )
 
 func EnvironmentToSippyReleaseName(environmentName string) string {
@@ -19,6 +21,12 @@ func EnvironmentToSippyReleaseName(environmentName string) string {
 	return "FAIL"
 }
 
+// EnvironmentToPreviousEnvironment is the promotion order: releases reach stg from int, and prod from stg.
+var EnvironmentToPreviousEnvironment = map[string]string{
+	"stg":  "int",
+	"prod": "stg",
+}
+
 type HardcodedCIInfo struct {
 	JobVariant string
 	JobRegexes []*regexp.Regexp
@@ -47,13 +55,18 @@ var HardcodedCIInfos = []HardcodedCIInfo{
 		},
 		Category: JobImpactInforming,
 	},
-	{
-		JobVariant: "unknown",
-		JobRegexes: []*regexp.Regexp{
-			regexp.MustCompile(".*"),
-		},
-		Category: JobImpactInforming,
-	},
+}
+
+// ClassifyJob returns the HardcodedCIInfo whose regexes match jobName, or nil when none do.
+func ClassifyJob(jobName string) *HardcodedCIInfo {
+	for i := range HardcodedCIInfos {
+		for _, currRegex := range HardcodedCIInfos[i].JobRegexes {
+			if currRegex.MatchString(jobName) {
+				return &HardcodedCIInfos[i]
+			}
+		}
+	}
+	return nil
 }
 
 type HardcodedComponentInfo struct {
@@ -187,6 +200,31 @@ var HardcodedComponents = map[string]HardcodedComponentInfo{
 	},
 }
 
+// jiraProjects are the JIRA projects whose keys show up in our component commit messages and PR titles.
+var jiraProjects = []string{
+	"ACM",
+	"ARO",
+	"AROSLSRE",
+	"HOSTEDCP",
+	"MGMT",
+	"OCM",
+	"OCPBUGS",
+	"SDA",
+	"SREP",
+}
+
+var jiraRefRegex = regexp.MustCompile(`\b(?:` + strings.Join(jiraProjects, "|") + `)-\d+\b`)
+
+// JIRARefs returns the sorted, unique JIRA keys mentioned in the text.
+func JIRARefs(text string) []string {
+	return set.New(jiraRefRegex.FindAllString(text, -1)...).SortedList()
+}
+
+// JIRAURL returns the browse URL for a JIRA key.
+func JIRAURL(jiraRef string) string {
+	return "https://issues.redhat.com/browse/" + jiraRef
+}
+
 // imagePullLocationForName returns the registry and repository for a given image name, or an error if the name isn't recognized.
 func imagePullLocationForName(name string) (string, string, error) {
 	info, exists := HardcodedComponents[name]
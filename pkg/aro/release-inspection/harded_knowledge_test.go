@@ -0,0 +1,34 @@
+package release_inspection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyJob(t *testing.T) {
+	tests := []struct {
+		jobName            string
+		expectedJobVariant string
+		expectedCategory   JobCategory
+	}{
+		{jobName: "periodic-ci-Azure-ARO-HCP-main-periodic-create-aro-hcp-in-int", expectedJobVariant: "bare-minimum", expectedCategory: JobImpactBlocking},
+		{jobName: "periodic-ci-Azure-ARO-HCP-main-periodic-stg-e2e-parallel", expectedJobVariant: "e2e-parallel", expectedCategory: JobImpactInforming},
+		{jobName: "periodic-ci-Azure-ARO-HCP-main-periodic-stg-e2e-serial"},
+		{jobName: "pull-ci-Azure-ARO-HCP-main-e2e-parallel"},
+		{jobName: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jobName, func(t *testing.T) {
+			actual := ClassifyJob(tt.jobName)
+			if len(tt.expectedJobVariant) == 0 {
+				assert.Nil(t, actual)
+				return
+			}
+			require.NotNil(t, actual)
+			assert.Equal(t, tt.expectedJobVariant, actual.JobVariant)
+			assert.Equal(t, tt.expectedCategory, actual.Category)
+		})
+	}
+}
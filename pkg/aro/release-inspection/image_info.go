@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/metrics"
 	"k8s.io/klog/v2"
 )
 
@@ -117,7 +118,9 @@ func pullImage(ctx context.Context, imagePullSpec string, credentialFilePath str
 		return fmt.Errorf("failed to start pull process: %w", err)
 	}
 
-	if err := cmd.Wait(); err != nil {
+	err := cmd.Wait()
+	metrics.ObserveExternalCall("podman", "pull", startTime, err)
+	if err != nil {
 		duration := time.Now().Sub(startTime)
 		logger.Info("Failed to pull image", "imagePullSpec", imagePullSpec, "duration", duration, "args", args, "err", err)
 
@@ -152,7 +155,9 @@ func inspectImage(ctx context.Context, imagePullSpec string) (map[string]interfa
 		return nil, fmt.Errorf("failed to start process: %w", err)
 	}
 
-	if err := cmd.Wait(); err != nil {
+	err := cmd.Wait()
+	metrics.ObserveExternalCall("podman", "inspect", startTime, err)
+	if err != nil {
 		duration := time.Now().Sub(startTime)
 		logger.Info("Failed to inspect image", "imagePullSpec", imagePullSpec, "duration", duration)
 		var exitErr *exec.ExitError
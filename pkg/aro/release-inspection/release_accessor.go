@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,8 +19,11 @@ import (
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/openshift-online/service-status/pkg/apis/status"
-	"github.com/openshift-online/service-status/pkg/aro/sippy"
+	"github.com/openshift-online/service-status/pkg/aro/ciresults"
+	"github.com/openshift-online/service-status/pkg/aro/pullrequests"
+	"github.com/openshift-online/service-status/pkg/metrics"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
 )
@@ -29,7 +33,9 @@ type ReleaseAccessor interface {
 	ListEnvironmentReleases(ctx context.Context) (*status.EnvironmentReleaseList, error)
 	ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error)
 	GetEnvironmentRelease(ctx context.Context, environmentReleaseName string) (*status.EnvironmentRelease, error)
-	GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName string, otherEnvironmentReleaseName string) (*status.EnvironmentReleaseDiff, error)
+	GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName string, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error)
+	GetCICoverage(ctx context.Context, environmentName string) (*status.CICoverage, error)
+	ListComponents(ctx context.Context) (*status.ComponentStatusList, error)
 
 	// this is useful to use the caching instance to delegate function calls
 	SetSelfLookupInstance(ReleaseAccessor)
@@ -43,29 +49,48 @@ type Release struct {
 type releaseAccessor struct {
 	selfLookupInstance ReleaseAccessor
 
-	aroHCPDir            string
-	numberOfDays         int
-	imageInfoAccessor    ImageInfoAccessor
-	componentGitAccessor ComponentsGitInfo
+	aroHCPRepository *AROHCPRepository
+	aroHCPDir        string
+	numberOfDays     int
+	// maxChangesPerComponent bounds the changes listed for each component in a diff.  NumberOfChanges still counts all of them.
+	maxChangesPerComponent int
+	imageInfoAccessor      ImageInfoAccessor
+	componentGitAccessor   ComponentsGitInfo
+	pullRequestAccessor    pullrequests.PullRequestInfoAccessor
+	ciResultSource         ciresults.CIResultSource
+	testedCommitResolver   TestedCommitResolver
+	scanStatusRecorder     *ScanStatusRecorder
+	clock                  clock.PassiveClock
 
-	gitLock              sync.Mutex
 	releaseNameToInfo    map[string]*status.ReleaseDetails
 	releaseNameToRelease map[string]*status.Release
 }
 
-func NewReleaseAccessor(aroHCPDir string, numberOfDays int, imageInfoAccessor ImageInfoAccessor, componentGitAccessor ComponentsGitInfo) ReleaseAccessor {
+func NewReleaseAccessor(aroHCPRepository *AROHCPRepository, numberOfDays, maxChangesPerComponent int, imageInfoAccessor ImageInfoAccessor, componentGitAccessor ComponentsGitInfo, pullRequestAccessor pullrequests.PullRequestInfoAccessor, ciResultSource ciresults.CIResultSource, testedCommitResolver TestedCommitResolver, scanStatusRecorder *ScanStatusRecorder, clock clock.PassiveClock) ReleaseAccessor {
 	ret := &releaseAccessor{
-		aroHCPDir:            aroHCPDir,
-		numberOfDays:         numberOfDays,
-		imageInfoAccessor:    imageInfoAccessor,
-		componentGitAccessor: componentGitAccessor,
-		releaseNameToInfo:    map[string]*status.ReleaseDetails{},
-		releaseNameToRelease: map[string]*status.Release{},
+		aroHCPRepository:       aroHCPRepository,
+		aroHCPDir:              aroHCPRepository.Dir(),
+		numberOfDays:           numberOfDays,
+		maxChangesPerComponent: maxChangesPerComponent,
+		imageInfoAccessor:      imageInfoAccessor,
+		componentGitAccessor:   componentGitAccessor,
+		pullRequestAccessor:    pullRequestAccessor,
+		ciResultSource:         ciResultSource,
+		testedCommitResolver:   testedCommitResolver,
+		scanStatusRecorder:     scanStatusRecorder,
+		clock:                  clock,
+		releaseNameToInfo:      map[string]*status.ReleaseDetails{},
+		releaseNameToRelease:   map[string]*status.Release{},
 	}
 	ret.SetSelfLookupInstance(ret)
 	return ret
 }
 
+// lookBackSince is how far back releases are listed.
+func (r *releaseAccessor) lookBackSince() time.Time {
+	return r.clock.Now().Add(-time.Duration(r.numberOfDays) * 24 * time.Hour)
+}
+
 func (r *releaseAccessor) ListEnvironments(ctx context.Context) ([]string, error) {
 	// TODO list the releases to locate all the available names.
 	return []string{"int", "stg", "prod"}, nil
@@ -78,13 +103,17 @@ var interestingFiles = set.New(
 
 // listEnvironmentReleasesLookupInfo returns the environment releases from newest to oldest.
 // only releases with changes are listed.
-func (r *releaseAccessor) listEnvironmentReleasesLookupInfo(ctx context.Context, environmentName string) ([]*EnvironmentReleaseLookupInformation, error) {
-	r.gitLock.Lock()
-	defer r.gitLock.Unlock()
+func (r *releaseAccessor) listEnvironmentReleasesLookupInfo(ctx context.Context, environmentName string, scan *environmentScan) ([]*EnvironmentReleaseLookupInformation, error) {
+	r.aroHCPRepository.lock.Lock()
+	defer r.aroHCPRepository.lock.Unlock()
 
 	logger := klog.FromContext(ctx)
 
 	aroHCPRepo, err := git.PlainOpen(r.aroHCPDir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		// the repository refresher clones it shortly after startup.
+		return nil, status.NewServiceUnavailable(fmt.Sprintf("aro hcp repo has not been cloned to %q yet", r.aroHCPDir))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open aro hcp repo: %w", err)
 	}
@@ -107,6 +136,7 @@ func (r *releaseAccessor) listEnvironmentReleasesLookupInfo(ctx context.Context,
 	}()
 
 	logger.Info("Working ARO HCP Head", "AROHCPHead", aroHCPHead.Hash())
+	scan.aroHCPHead = aroHCPHead.Hash().String()
 
 	configLog, err := aroHCPRepo.Log(ptr.To(git.LogOptions{
 		PathFilter: func(path string) bool {
@@ -115,7 +145,7 @@ func (r *releaseAccessor) listEnvironmentReleasesLookupInfo(ctx context.Context,
 			}
 			return false
 		},
-		Since: ptr.To(time.Now().Add(-time.Duration(r.numberOfDays) * 24 * time.Hour)),
+		Since: ptr.To(r.lookBackSince()),
 	}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get aro hcp config log: %w", err)
@@ -194,13 +224,24 @@ func (r *releaseAccessor) listEnvironmentReleasesLookupInfo(ctx context.Context,
 	return environmentLookupInfoNewestToOldest, nil
 }
 
-func (r *releaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName string, otherEnvironmentReleaseName string) (*status.EnvironmentReleaseDiff, error) {
+// GetReleaseEnvironmentDiff lists a page of the changes of each component.  The page is found while walking the git
+// history, so later pages cost no more than the first.  options.Limit is lowered to maxChangesPerComponent.
+func (r *releaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName string, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
 	logger := klog.FromContext(ctx)
 	logger = klog.LoggerWithValues(logger, "environmentReleaseName", environmentReleaseName)
 	logger = klog.LoggerWithValues(logger, "otherEnvironmentName", otherEnvironmentReleaseName)
 	ctx = klog.NewContext(ctx, logger)
 	logger.Info("GetReleaseEnvironmentDiff entry")
 
+	offset, err := options.Offset()
+	if err != nil {
+		return nil, err
+	}
+	limit := r.maxChangesPerComponent
+	if options.Limit > 0 && (limit < 1 || options.Limit < limit) {
+		limit = options.Limit
+	}
+
 	environmentRelease, err := r.selfLookupInstance.GetEnvironmentRelease(ctx, environmentReleaseName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get release environment info: %w", err)
@@ -218,7 +259,10 @@ func (r *releaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environ
 		Name:                        environmentReleaseName,
 		OtherEnvironmentReleaseName: otherEnvironmentReleaseName,
 		DifferentComponents:         map[string]*status.ComponentDiff{},
+		CIComparison:                NewCIComparison(environmentRelease, otherEnvironmentRelease, DefaultSignificanceLevel),
 	}
+	// every page lists up to maxChangesPerComponent changes of a dozen components, so the lookups are made together.
+	pullRequestLookups := []pullRequestLookup{}
 	for _, component := range environmentRelease.Components {
 		var otherComponent *status.Component
 		for _, currOtherComponent := range otherEnvironmentRelease.Components {
@@ -289,7 +333,9 @@ func (r *releaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environ
 			ret.DifferentComponents[component.Name] = componentDiff
 			continue
 		}
-		diffs, err := gitAccessor.GetDiffForSHAs(ctx, component.SourceSHA, otherComponent.SourceSHA, 100)
+		diffStartTime := time.Now()
+		commitDiff, err := gitAccessor.GetDiffForSHAs(ctx, component.SourceSHA, otherComponent.SourceSHA, offset, limit)
+		metrics.ObserveExternalCall("git", "diff", diffStartTime, err)
 		if err != nil {
 			componentDiff := &status.ComponentDiff{
 				Name:            component.Name,
@@ -304,19 +350,18 @@ func (r *releaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environ
 			ret.DifferentComponents[component.Name] = componentDiff
 			continue
 		}
-		if len(diffs) == 0 {
+		if commitDiff.TotalMergeCommits == 0 {
 			continue
 		}
 
 		componentDiff := &status.ComponentDiff{
-			Name:    component.Name,
-			Changes: []status.ComponentChange{},
+			Name:                component.Name,
+			NumberOfChanges:     commitDiff.TotalMergeCommits,
+			Truncated:           offset+len(commitDiff.MergeCommits) < commitDiff.TotalMergeCommits,
+			SearchDepthExceeded: commitDiff.SearchDepthExceeded,
+			Changes:             []status.ComponentChange{},
 		}
-		for _, diff := range diffs {
-			if len(diff.ParentHashes) < 2 {
-				continue
-			}
-			componentDiff.NumberOfChanges++
+		for _, diff := range commitDiff.MergeCommits {
 
 			switch {
 			case strings.Contains(ptr.Deref(component.RepoURL, ""), "github.com"):
@@ -340,6 +385,25 @@ func (r *releaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environ
 				} else {
 					currChange.GithubPRMerge.ChangeSummary = messageLines[2]
 				}
+				githubPRMerge, commitMessage := currChange.GithubPRMerge, diff.Message
+				pullRequestLookups = append(pullRequestLookups, pullRequestLookup{
+					repoURL: ptr.Deref(component.RepoURL, ""),
+					number:  githubPRMerge.PRNumber,
+					apply: func(pullRequestInfo *pullrequests.PullRequestInfo) {
+						if pullRequestInfo != nil {
+							githubPRMerge.ChangeSummary = pullRequestInfo.Title
+							githubPRMerge.Title = pullRequestInfo.Title
+							githubPRMerge.Author = pullRequestInfo.Author
+							githubPRMerge.MergedBy = pullRequestInfo.MergedBy
+							githubPRMerge.Labels = pullRequestInfo.Labels
+							githubPRMerge.LinkedIssues = pullRequestInfo.LinkedIssues
+							githubPRMerge.URL = pullRequestInfo.URL
+						}
+						if jiraRefs := JIRARefs(commitMessage + "\n" + githubPRMerge.ChangeSummary); len(jiraRefs) > 0 {
+							githubPRMerge.JIRARefs = jiraRefs
+						}
+					},
+				})
 
 				componentDiff.Changes = append(componentDiff.Changes, currChange)
 
@@ -364,6 +428,25 @@ func (r *releaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environ
 				} else {
 					currChange.GitlabMRMerge.ChangeSummary = messageLines[2]
 				}
+				gitlabMRMerge, commitMessage := currChange.GitlabMRMerge, diff.Message
+				pullRequestLookups = append(pullRequestLookups, pullRequestLookup{
+					repoURL: ptr.Deref(component.RepoURL, ""),
+					number:  gitlabMRMerge.MRNumber,
+					apply: func(pullRequestInfo *pullrequests.PullRequestInfo) {
+						if pullRequestInfo != nil {
+							gitlabMRMerge.ChangeSummary = pullRequestInfo.Title
+							gitlabMRMerge.Title = pullRequestInfo.Title
+							gitlabMRMerge.Author = pullRequestInfo.Author
+							gitlabMRMerge.MergedBy = pullRequestInfo.MergedBy
+							gitlabMRMerge.Labels = pullRequestInfo.Labels
+							gitlabMRMerge.LinkedIssues = pullRequestInfo.LinkedIssues
+							gitlabMRMerge.URL = pullRequestInfo.URL
+						}
+						if jiraRefs := JIRARefs(commitMessage + "\n" + gitlabMRMerge.ChangeSummary); len(jiraRefs) > 0 {
+							gitlabMRMerge.JIRARefs = jiraRefs
+						}
+					},
+				})
 
 				componentDiff.Changes = append(componentDiff.Changes, currChange)
 
@@ -375,20 +458,285 @@ func (r *releaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environ
 			}
 		}
 		ret.DifferentComponents[component.Name] = componentDiff
+		if componentDiff.Truncated {
+			ret.Continue = status.DiffContinueToken(offset + limit)
+		}
+	}
+	r.lookUpPullRequests(ctx, pullRequestLookups)
+	for componentName, componentDiff := range ret.DifferentComponents {
+		unavailable := 0.0
+		if slices.ContainsFunc(componentDiff.Changes, func(change status.ComponentChange) bool { return change.ChangeType == "Unavailable" }) {
+			unavailable = 1
+		}
+		componentDiffUnavailable.WithLabelValues(componentName).Set(unavailable)
+	}
+
+	return ret, nil
+}
+
+// GetCICoverage lists every job run the CI result source knows about for environmentName and whether it made it
+// into a release.
+func (r *releaseAccessor) GetCICoverage(ctx context.Context, environmentName string) (*status.CICoverage, error) {
+	logger := klog.FromContext(ctx)
+	logger = klog.LoggerWithValues(logger, "environment", environmentName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("GetCICoverage entry")
+
+	environmentReleases, err := r.selfLookupInstance.ListEnvironmentReleasesForEnvironment(ctx, environmentName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environment releases: %w", err)
+	}
+	attributedURLs := set.New[string]()
+	for _, environmentRelease := range environmentReleases.Items {
+		for _, results := range []map[string][]status.JobRunResults{environmentRelease.BlockingJobRunResults, environmentRelease.InformingJobRunResults} {
+			for _, jobRuns := range results {
+				for _, jobRun := range jobRuns {
+					attributedURLs.Insert(jobRun.URL)
+				}
+			}
+		}
+	}
+
+	ciJobRuns, err := r.ciResultSource.ListJobRuns(ctx, environmentName, r.lookBackSince())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+
+	ret := &status.CICoverage{
+		TypeMeta: status.TypeMeta{
+			Kind:       "CICoverage",
+			APIVersion: "service-status.hcm.openshift.io/v1",
+		},
+		Environment:         environmentName,
+		Jobs:                []status.CIJobCoverage{},
+		UnattributedJobRuns: []status.JobRunResults{},
+	}
+	jobNameToCoverage := map[string]*status.CIJobCoverage{}
+	for _, jobRun := range ciJobRuns {
+		jobCoverage, ok := jobNameToCoverage[jobRun.Job]
+		if !ok {
+			jobCoverage = &status.CIJobCoverage{JobName: jobRun.Job}
+			if ciInfo := ClassifyJob(jobRun.Job); ciInfo != nil {
+				jobCoverage.JobVariant = ciInfo.JobVariant
+				jobCoverage.Category = string(ciInfo.Category)
+			} else {
+				jobCoverage.NeedsClassification = strings.HasPrefix(jobRun.Job, "periodic-")
+			}
+			jobNameToCoverage[jobRun.Job] = jobCoverage
+		}
+
+		jobCoverage.Runs++
+		switch {
+		case attributedURLs.Has(jobRun.URL):
+			jobCoverage.AttributedRuns++
+		case len(jobCoverage.JobVariant) > 0:
+			ret.UnattributedJobRuns = append(ret.UnattributedJobRuns, status.JobRunResults{
+				JobName:       jobRun.Job,
+				OverallResult: jobRun.OverallResult,
+				URL:           jobRun.URL,
+				TestedSHA:     jobRun.TestedSHA,
+			})
+		}
+	}
+	for _, jobName := range set.KeySet(jobNameToCoverage).SortedList() {
+		ret.Jobs = append(ret.Jobs, *jobNameToCoverage[jobName])
 	}
 
 	return ret, nil
 }
 
+// releaseIndexForTestedCommit returns the index of the newest release, from releasesNewestToOldest, whose commit is
+// testedSHA or one of its ancestors, so the release the tested commit was built on.  ARO-HCP main has merge commits,
+// so a commit can be newer than a release without containing it, and the history is walked instead of comparing
+// times.  It returns -1 when the commit is unknown or no release is its ancestor.
+func (r *releaseAccessor) releaseIndexForTestedCommit(ctx context.Context, releasesNewestToOldest []status.EnvironmentRelease, testedSHA string) int {
+	shaToIndex := map[string]int{}
+	for i := len(releasesNewestToOldest) - 1; i >= 0; i-- {
+		if sha := releasesNewestToOldest[i].SHA; len(sha) > 0 {
+			shaToIndex[sha] = i
+		}
+	}
+	if i, ok := shaToIndex[testedSHA]; ok {
+		return i
+	}
+
+	r.aroHCPRepository.lock.Lock()
+	defer r.aroHCPRepository.lock.Unlock()
+
+	ret, err := newestAncestorIndex(r.aroHCPDir, testedSHA, shaToIndex)
+	if err != nil {
+		klog.FromContext(ctx).V(2).Info("failed to find tested commit", "testedSHA", testedSHA, "err", err)
+		return -1
+	}
+	return ret
+}
+
+// newestAncestorIndex walks back from sha, newest commit first, and returns the index of the first commit in
+// shaToIndex.  The walk stops once it is older than every commit in shaToIndex.
+func newestAncestorIndex(repoDir, sha string, shaToIndex map[string]int) (int, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return -1, fmt.Errorf("failed to open repo: %w", err)
+	}
+	var oldestCommitTime *time.Time
+	for candidateSHA := range shaToIndex {
+		candidate, err := repo.CommitObject(plumbing.NewHash(candidateSHA))
+		if err != nil {
+			continue
+		}
+		if oldestCommitTime == nil || candidate.Committer.When.Before(*oldestCommitTime) {
+			oldestCommitTime = &candidate.Committer.When
+		}
+	}
+	if oldestCommitTime == nil {
+		return -1, nil
+	}
+
+	commitLog, err := repo.Log(&git.LogOptions{From: plumbing.NewHash(sha), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return -1, fmt.Errorf("failed to get git log: %w", err)
+	}
+	defer commitLog.Close()
+	for {
+		commit, err := commitLog.Next()
+		if errors.Is(err, io.EOF) {
+			return -1, nil
+		}
+		if err != nil {
+			return -1, fmt.Errorf("failed to read git log: %w", err)
+		}
+		if i, ok := shaToIndex[commit.Hash.String()]; ok {
+			return i, nil
+		}
+		if commit.Committer.When.Before(*oldestCommitTime) {
+			return -1, nil
+		}
+	}
+}
+
+// resolveTestedCommits asks the resolver for the tested commit of every job run that doesn't record one, a few at a
+// time since each may be an HTTP request.  Job runs that can't be resolved are left out.
+func (r *releaseAccessor) resolveTestedCommits(ctx context.Context, jobRuns []ciresults.JobRun) map[string]string {
+	logger := klog.FromContext(ctx)
+
+	lock := sync.Mutex{}
+	ret := map[string]string{}
+	wg := sync.WaitGroup{}
+	workers := make(chan struct{}, maxConcurrentTestedCommitResolutions)
+	for _, jobRun := range jobRuns {
+		if len(jobRun.TestedSHA) > 0 {
+			continue
+		}
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			testedSHA, err := r.testedCommitResolver.ResolveTestedCommit(ctx, jobRun)
+			if err != nil {
+				logger.V(2).Info("failed to resolve tested commit, falling back to wall-clock attribution", "jobRun", jobRun.URL, "err", err)
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			ret[jobRun.URL] = testedSHA
+		}()
+	}
+	wg.Wait()
+	return ret
+}
+
+// releaseIndexForWallClock returns the index of the release, from releasesNewestToOldest, that was the newest one
+// when the job run started.  This is only a fallback: a job that started just before a rollout tested the previous
+// release.  It returns -1 when the job run is older than every release.
+func releaseIndexForWallClock(releasesNewestToOldest []status.EnvironmentRelease, jobRunTime time.Time) int {
+	for i, release := range releasesNewestToOldest {
+		_, releaseTime, _, ok := status.SplitReleaseName(release.ReleaseName)
+		if ok && !jobRunTime.Before(releaseTime) {
+			return i
+		}
+	}
+	return -1
+}
+
+// describeComponentSources fills in the branches and nearest tag of each component's source SHA.  Failures are only
+// logged because the bare SHA is still usable.
+func (r *releaseAccessor) describeComponentSources(ctx context.Context, environmentRelease *status.EnvironmentRelease) {
+	logger := klog.FromContext(ctx)
+	for _, component := range environmentRelease.Components {
+		if len(component.SourceSHA) == 0 || component.RepoURL == nil {
+			continue
+		}
+		gitAccessor, err := r.componentGitAccessor.GetComponentGitAccessor(ctx, component.Name)
+		if err != nil {
+			logger.Error(err, "failed to get component git accessor", "component", component.Name)
+			continue
+		}
+		sourceDescription, err := gitAccessor.DescribeSHA(ctx, component.SourceSHA)
+		if err != nil {
+			logger.V(2).Info("failed to describe source SHA", "component", component.Name, "err", err)
+			continue
+		}
+		component.SourceBranches = sourceDescription.Branches
+		component.SourceDescription = sourceDescription.String()
+	}
+}
+
+// maxConcurrentPullRequestLookups bounds the GitHub and GitLab requests of one diff.
+const maxConcurrentPullRequestLookups = 8
+
+// pullRequestLookup fills in one change once its pull request has been looked up.
+type pullRequestLookup struct {
+	repoURL string
+	number  int32
+	// apply is called with nil when the lookup failed.  It only touches its own change, so lookups run in parallel.
+	apply func(pullRequestInfo *pullrequests.PullRequestInfo)
+}
+
+// lookUpPullRequests runs the lookups in parallel and returns once every change is filled in.
+func (r *releaseAccessor) lookUpPullRequests(ctx context.Context, lookups []pullRequestLookup) {
+	wg := sync.WaitGroup{}
+	workers := make(chan struct{}, maxConcurrentPullRequestLookups)
+	for _, lookup := range lookups {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			lookup.apply(r.getPullRequestInfo(ctx, lookup.repoURL, lookup.number))
+		}()
+	}
+	wg.Wait()
+}
+
+// getPullRequestInfo returns nil when the PR number is unknown or the lookup fails.  The commit message summary is
+// still useful, so enrichment failures are only logged.
+func (r *releaseAccessor) getPullRequestInfo(ctx context.Context, repoURL string, number int32) *pullrequests.PullRequestInfo {
+	if number == 0 {
+		return nil
+	}
+	pullRequestInfo, err := r.pullRequestAccessor.GetPullRequestInfo(ctx, repoURL, number)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "failed to get pull request info", "repoURL", repoURL, "number", number)
+		return nil
+	}
+	if pullRequestInfo == nil || len(pullRequestInfo.Title) == 0 {
+		return nil
+	}
+	return pullRequestInfo
+}
+
 func (r *releaseAccessor) GetEnvironmentRelease(ctx context.Context, environmentReleaseName string) (*status.EnvironmentRelease, error) {
 	logger := klog.FromContext(ctx)
 	logger = klog.LoggerWithValues(logger, "environmentReleaseName", environmentReleaseName)
 	ctx = klog.NewContext(ctx, logger)
 	logger.Info("GetEnvironmentRelease entry")
 
-	environmentName, releaseName, ok := SplitEnvironmentReleaseName(environmentReleaseName)
+	environmentName, releaseName, ok := status.SplitEnvironmentReleaseName(environmentReleaseName)
 	if !ok {
-		return nil, fmt.Errorf("failed to split environment release name %q", environmentReleaseName)
+		return nil, status.NewBadRequest(fmt.Sprintf("environment release name %q must be in format <environmentName>---<releaseName>", environmentReleaseName))
 	}
 	environmentReleases, err := r.selfLookupInstance.ListEnvironmentReleasesForEnvironment(ctx, environmentName)
 	if err != nil {
@@ -401,21 +749,48 @@ func (r *releaseAccessor) GetEnvironmentRelease(ctx context.Context, environment
 		}
 	}
 
-	return nil, fmt.Errorf("error NotFound: did not find environment release %q", releaseName)
+	return nil, status.NewNotFound("EnvironmentRelease", environmentReleaseName)
 }
 
 func (r *releaseAccessor) ListEnvironmentReleasesForEnvironment(ctx context.Context, environmentName string) (*status.EnvironmentReleaseList, error) {
+	scan := r.scanStatusRecorder.startScan(environmentName)
+	ret, err := r.listEnvironmentReleasesForEnvironment(ctx, environmentName, scan)
+	scan.finish(err)
+	return ret, err
+}
+
+// appendOlderEnvironmentRelease appends older to releases listed newest to oldest.  When older has the same components as
+// the release before it, that release isn't actually a new release, so it is replaced with older.  Unreadable releases
+// have no components to compare, so each one is kept with its own Degraded condition.
+func appendOlderEnvironmentRelease(newestToOldest []status.EnvironmentRelease, older status.EnvironmentRelease) []status.EnvironmentRelease {
+	if len(newestToOldest) == 0 {
+		return append(newestToOldest, older)
+	}
+	moreRecent := &newestToOldest[len(newestToOldest)-1]
+	if IsConfigUnreadable(moreRecent) || IsConfigUnreadable(&older) {
+		return append(newestToOldest, older)
+	}
+	if len(ChangedComponents(&older, moreRecent)) == 0 {
+		newestToOldest[len(newestToOldest)-1] = older
+		return newestToOldest
+	}
+	return append(newestToOldest, older)
+}
+
+func (r *releaseAccessor) listEnvironmentReleasesForEnvironment(ctx context.Context, environmentName string, scan *environmentScan) (*status.EnvironmentReleaseList, error) {
 	logger := klog.FromContext(ctx)
 	logger = klog.LoggerWithValues(logger, "environment", environmentName)
 	ctx = klog.NewContext(ctx, logger)
 	logger.Info("ListEnvironmentReleasesForEnvironment entry")
 
-	ciJobRuns, err := sippy.ListJobRunsForEnvironment(ctx, EnvironmentToSippyReleaseName(environmentName))
+	// releases are only listed this far back, so older job runs can't be attributed to any of them.
+	ciJobRuns, err := r.ciResultSource.ListJobRuns(ctx, environmentName, r.lookBackSince())
 	if err != nil {
+		// sources that did answer are still useful.
 		logger.Error(err, "failed to list job runs")
 	}
 
-	environmentReleasesLookupInfoNewestToOldest, err := r.listEnvironmentReleasesLookupInfo(ctx, environmentName)
+	environmentReleasesLookupInfoNewestToOldest, err := r.listEnvironmentReleasesLookupInfo(ctx, environmentName, scan)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list possible releases: %w", err)
 	}
@@ -429,22 +804,15 @@ func (r *releaseAccessor) ListEnvironmentReleasesForEnvironment(ctx context.Cont
 
 		newReleaseInfo, err := ReleaseInfo(localCtx, r.imageInfoAccessor, environmentReleaseLookupInfo)
 		if err != nil {
+			// the release is kept, degraded, so it isn't mistaken for no release at all.
+			loopLogger.Error(err, "failed to read release")
+			scan.releaseDegraded(releaseName, err)
+			unreadableRelease := newConfigUnreadableEnvironmentRelease(environmentName, releaseName, environmentReleaseLookupInfo.ReleaseSHA, err)
+			partialEnvironmentReleases = appendOlderEnvironmentRelease(partialEnvironmentReleases, *unreadableRelease)
 			continue
-			// TODO un-nerf
-			//return nil, fmt.Errorf("failed to get release markdowns: %w", err)
 		}
-
-		if len(partialEnvironmentReleases) > 0 {
-			moreRecentPartialEnvironmentRelease := partialEnvironmentReleases[len(partialEnvironmentReleases)-1]
-			changedComponents := ChangedComponents(newReleaseInfo, &moreRecentPartialEnvironmentRelease)
-			if len(changedComponents) == 0 {
-				// if nothing changed, then the more recent release isn't actually a new release, so replace it with this current one
-				partialEnvironmentReleases[len(partialEnvironmentReleases)-1] = *newReleaseInfo
-				continue
-			}
-		}
-
-		partialEnvironmentReleases = append(partialEnvironmentReleases, *newReleaseInfo)
+		r.describeComponentSources(localCtx, newReleaseInfo)
+		partialEnvironmentReleases = appendOlderEnvironmentRelease(partialEnvironmentReleases, *newReleaseInfo)
 	}
 
 	ret := &status.EnvironmentReleaseList{
@@ -455,56 +823,66 @@ func (r *releaseAccessor) ListEnvironmentReleasesForEnvironment(ctx context.Cont
 		Items: []status.EnvironmentRelease{},
 	}
 	// now add the CI status to these releases
-	for i, newReleaseInfo := range partialEnvironmentReleases {
-		loopLogger := klog.LoggerWithValues(logger, "newEnvironmentReleaseName", newReleaseInfo.Name)
-		loopLogger.Info("starting execution")
-
-		nextReleaseTime := time.Now()
-		if nextReleaseIndex := i - 1; nextReleaseIndex >= 0 {
-			_, nextReleaseTime, _, _ = SplitReleaseName(environmentReleasesLookupInfoNewestToOldest[nextReleaseIndex].ReleaseName)
+	jobRunURLToTestedSHA := r.resolveTestedCommits(ctx, ciJobRuns)
+	testedSHAToReleaseIndex := map[string]int{}
+	for _, currJobRun := range ciJobRuns {
+		matchingAssigner := ClassifyJob(currJobRun.Job)
+		if matchingAssigner == nil {
+			// these are listed by GetCICoverage so someone can classify them.
+			logger.V(4).Info("No matching assigner found for job run", "jobRun", currJobRun.Job)
+			continue
 		}
-		_, newReleaseTime, _, _ := SplitReleaseName(newReleaseInfo.ReleaseName)
 
-		// find all job runs between the newReleaseTime and the nextReleaseTime
-		for _, currJobRun := range ciJobRuns {
-			jobRunTime := time.Unix(0, currJobRun.Timestamp*int64(time.Millisecond))
-			if jobRunTime.After(nextReleaseTime) {
-			}
-			if jobRunTime.After(nextReleaseTime) || jobRunTime.Before(newReleaseTime) {
-				continue
-			}
-
-			var matchingAssigner *HardcodedCIInfo
-			for _, ciAssigner := range HardcodedCIInfos {
-				for _, currRegex := range ciAssigner.JobRegexes {
-					if currRegex.MatchString(currJobRun.Job) {
-						matchingAssigner = &ciAssigner
-						break
-					}
-				}
-				if matchingAssigner != nil {
-					break
-				}
-			}
-
-			jobRunResult := status.JobRunResults{
-				JobName:       currJobRun.Job,
-				OverallResult: status.JobOverallResult(currJobRun.OverallResult),
-				URL:           currJobRun.URL,
+		jobRunResult := status.JobRunResults{
+			JobName:         currJobRun.Job,
+			OverallResult:   currJobRun.OverallResult,
+			URL:             currJobRun.URL,
+			TestFailures:    currJobRun.TestFailures,
+			FailedTestNames: currJobRun.FailedTestNames,
+			TestFlakes:      currJobRun.TestFlakes,
+			FlakedTestNames: currJobRun.FlakedTestNames,
+		}
+		releaseIndex := -1
+		testedSHA := currJobRun.TestedSHA
+		if len(testedSHA) == 0 {
+			testedSHA = jobRunURLToTestedSHA[currJobRun.URL]
+		}
+		if len(testedSHA) > 0 {
+			var ok bool
+			// many job runs test the same commit, and walking the history is the expensive part.
+			if releaseIndex, ok = testedSHAToReleaseIndex[testedSHA]; !ok {
+				releaseIndex = r.releaseIndexForTestedCommit(ctx, partialEnvironmentReleases, testedSHA)
+				testedSHAToReleaseIndex[testedSHA] = releaseIndex
 			}
+			jobRunResult.TestedSHA = testedSHA
+			jobRunResult.Attribution = status.JobRunAttributionTestedCommit
+		}
+		if releaseIndex < 0 {
+			releaseIndex = releaseIndexForWallClock(partialEnvironmentReleases, currJobRun.StartTime)
+			jobRunResult.Attribution = status.JobRunAttributionWallClock
+		}
+		if releaseIndex < 0 {
+			continue
+		}
 
-			switch {
-			case matchingAssigner == nil:
-				loopLogger.Info("No matching assigner found for job run", "jobRun", currJobRun.Job)
-			case matchingAssigner.Category == JobImpactBlocking:
-				newReleaseInfo.BlockingJobRunResults[matchingAssigner.JobVariant] = append(newReleaseInfo.BlockingJobRunResults[matchingAssigner.JobVariant], jobRunResult)
-			case matchingAssigner.Category == JobImpactInforming:
-				newReleaseInfo.InformingJobRunResults[matchingAssigner.JobVariant] = append(newReleaseInfo.InformingJobRunResults[matchingAssigner.JobVariant], jobRunResult)
-			}
+		newReleaseInfo := &partialEnvironmentReleases[releaseIndex]
+		switch matchingAssigner.Category {
+		case JobImpactBlocking:
+			newReleaseInfo.BlockingJobRunResults[matchingAssigner.JobVariant] = append(newReleaseInfo.BlockingJobRunResults[matchingAssigner.JobVariant], jobRunResult)
+		case JobImpactInforming:
+			newReleaseInfo.InformingJobRunResults[matchingAssigner.JobVariant] = append(newReleaseInfo.InformingJobRunResults[matchingAssigner.JobVariant], jobRunResult)
 		}
+	}
+	for i := range partialEnvironmentReleases {
+		partialEnvironmentReleases[i].Health = NewReleaseHealth(&partialEnvironmentReleases[i], DefaultMinimumRuns, DefaultRequiredPassRate)
 
-		ret.Items = append(ret.Items, newReleaseInfo)
+		var previousEnvironmentRelease *status.EnvironmentRelease
+		if i+1 < len(partialEnvironmentReleases) {
+			previousEnvironmentRelease = &partialEnvironmentReleases[i+1]
+		}
+		partialEnvironmentReleases[i].TestResults = NewReleaseTestResults(&partialEnvironmentReleases[i], previousEnvironmentRelease)
 	}
+	ret.Items = append(ret.Items, partialEnvironmentReleases...)
 
 	return ret, nil
 }
@@ -0,0 +1,95 @@
+package release_inspection
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/ciresults"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+type fakeCIResultSource struct {
+	jobRuns []ciresults.JobRun
+	since   time.Time
+}
+
+func (s *fakeCIResultSource) ListJobRuns(ctx context.Context, environmentName string, since time.Time) ([]ciresults.JobRun, error) {
+	s.since = since
+	return s.jobRuns, nil
+}
+
+func TestGetCICoverage(t *testing.T) {
+	now := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	blockingJob := "periodic-ci-Azure-ARO-HCP-main-periodic-create-aro-hcp-in-int"
+	ciResultSource := &fakeCIResultSource{jobRuns: []ciresults.JobRun{
+		{Job: blockingJob, URL: "attributed", OverallResult: status.JobSucceeded},
+		{Job: blockingJob, URL: "too-old", OverallResult: status.JobTestFailure, TestedSHA: "abc"},
+		{Job: "periodic-ci-Azure-ARO-HCP-main-periodic-new-job", URL: "new"},
+		{Job: "pull-ci-Azure-ARO-HCP-main-unit", URL: "presubmit"},
+	}}
+	accessor := &releaseAccessor{
+		numberOfDays:   14,
+		ciResultSource: ciResultSource,
+		clock:          clocktesting.NewFakePassiveClock(now),
+	}
+	accessor.SetSelfLookupInstance(&fakeEnvironmentReleasesAccessor{environmentToReleases: map[string]*status.EnvironmentReleaseList{
+		"int": {Items: []status.EnvironmentRelease{
+			{BlockingJobRunResults: map[string][]status.JobRunResults{"bare-minimum": {{JobName: blockingJob, URL: "attributed"}}}},
+		}},
+	}})
+
+	actual, err := accessor.GetCICoverage(context.Background(), "int")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-14*24*time.Hour), ciResultSource.since, "job runs are listed as far back as releases")
+	assert.Equal(t, []status.CIJobCoverage{
+		{JobName: blockingJob, JobVariant: "bare-minimum", Category: "Blocking", Runs: 2, AttributedRuns: 1},
+		{JobName: "periodic-ci-Azure-ARO-HCP-main-periodic-new-job", Runs: 1, NeedsClassification: true},
+		{JobName: "pull-ci-Azure-ARO-HCP-main-unit", Runs: 1},
+	}, actual.Jobs)
+	// unclassified runs are ignored, so they aren't listed as unattributed.
+	assert.Equal(t, []status.JobRunResults{
+		{JobName: blockingJob, OverallResult: status.JobTestFailure, URL: "too-old", TestedSHA: "abc"},
+	}, actual.UnattributedJobRuns)
+}
+
+func TestAppendOlderEnvironmentRelease(t *testing.T) {
+	readable := func(releaseName, digest string) status.EnvironmentRelease {
+		return status.EnvironmentRelease{
+			ReleaseName: releaseName,
+			Components: map[string]*status.Component{
+				"Backend": {Name: "Backend", ImageInfo: status.ContainerImage{Digest: digest}},
+			},
+		}
+	}
+	unreadable := func(releaseName string) status.EnvironmentRelease {
+		return *newConfigUnreadableEnvironmentRelease("int", releaseName, "abc", fmt.Errorf("bad config"))
+	}
+
+	newestToOldest := []status.EnvironmentRelease{}
+	for _, older := range []status.EnvironmentRelease{
+		readable("7", "sha256:new"),
+		// unchanged, so it replaces 7.
+		readable("6", "sha256:new"),
+		unreadable("5"),
+		unreadable("4"),
+		// the same images as 6, but the unreadable releases in between are kept, so this is listed too.
+		readable("3", "sha256:new"),
+		readable("2", "sha256:old"),
+		readable("1", "sha256:old"),
+	} {
+		newestToOldest = appendOlderEnvironmentRelease(newestToOldest, older)
+	}
+
+	actual := []string{}
+	for _, environmentRelease := range newestToOldest {
+		actual = append(actual, environmentRelease.ReleaseName)
+	}
+	assert.Equal(t, []string{"6", "5", "4", "3", "1"}, actual)
+	assert.True(t, IsConfigUnreadable(&newestToOldest[1]))
+	assert.True(t, IsConfigUnreadable(&newestToOldest[2]))
+}
@@ -0,0 +1,88 @@
+package release_inspection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/utils/set"
+)
+
+const (
+	// DefaultMinimumRuns is how many finished runs a blocking job variant needs.  Fewer than that and a single flake
+	// decides the verdict.
+	DefaultMinimumRuns = 3
+	// DefaultRequiredPassRate leaves room for the occasional infrastructure flake.
+	DefaultRequiredPassRate = 0.8
+)
+
+// NewReleaseHealth computes the verdict from the blocking CI results.  Informing results are reported but never
+// change the verdict.  Every blocking variant in HardcodedCIInfos is expected, so a variant without runs counts as
+// insufficient data rather than being ignored.
+func NewReleaseHealth(environmentRelease *status.EnvironmentRelease, minimumRuns int, requiredPassRate float64) *status.ReleaseHealth {
+	ret := &status.ReleaseHealth{
+		MinimumRuns:      minimumRuns,
+		RequiredPassRate: requiredPassRate,
+		JobVariants:      []status.JobVariantHealth{},
+	}
+
+	blockingVariants := set.KeySet(environmentRelease.BlockingJobRunResults)
+	for _, ciInfo := range HardcodedCIInfos {
+		if ciInfo.Category == JobImpactBlocking {
+			blockingVariants.Insert(ciInfo.JobVariant)
+		}
+	}
+	for _, variantName := range blockingVariants.SortedList() {
+		ret.JobVariants = append(ret.JobVariants, jobVariantHealth(variantName, JobImpactBlocking, environmentRelease.BlockingJobRunResults[variantName]))
+	}
+	for _, variantName := range set.KeySet(environmentRelease.InformingJobRunResults).SortedList() {
+		ret.JobVariants = append(ret.JobVariants, jobVariantHealth(variantName, JobImpactInforming, environmentRelease.InformingJobRunResults[variantName]))
+	}
+
+	failing := []string{}
+	insufficient := []string{}
+	for _, variant := range ret.JobVariants {
+		switch {
+		case variant.Category != string(JobImpactBlocking):
+		case variant.Runs < minimumRuns:
+			insufficient = append(insufficient, fmt.Sprintf("%s has %d of %d runs", variant.Name, variant.Runs, minimumRuns))
+		case variant.PassRate < requiredPassRate:
+			failing = append(failing, fmt.Sprintf("%s passed %d of %d runs", variant.Name, variant.Succeeded, variant.Runs))
+		}
+	}
+
+	switch {
+	case len(failing) > 0:
+		ret.Verdict = status.ReleaseHealthBlockingRed
+		ret.Reason = fmt.Sprintf("Blocking CI below %.0f%%: %s.", requiredPassRate*100, strings.Join(failing, ", "))
+	case len(insufficient) > 0:
+		ret.Verdict = status.ReleaseHealthInsufficientData
+		ret.Reason = fmt.Sprintf("Not enough blocking CI runs: %s.", strings.Join(insufficient, ", "))
+	default:
+		ret.Verdict = status.ReleaseHealthBlockingGreen
+		ret.Reason = fmt.Sprintf("Every blocking job variant passed at least %.0f%% of %d or more runs.", requiredPassRate*100, minimumRuns)
+	}
+	return ret
+}
+
+func jobVariantHealth(name string, category JobCategory, jobRuns []status.JobRunResults) status.JobVariantHealth {
+	ret := status.JobVariantHealth{
+		Name:     name,
+		Category: string(category),
+	}
+	for _, jobRun := range jobRuns {
+		switch jobRun.OverallResult {
+		case status.JobRunning:
+			// not finished, so it says nothing yet.
+		case status.JobSucceeded:
+			ret.Runs++
+			ret.Succeeded++
+		default:
+			ret.Runs++
+		}
+	}
+	if ret.Runs > 0 {
+		ret.PassRate = float64(ret.Succeeded) / float64(ret.Runs)
+	}
+	return ret
+}
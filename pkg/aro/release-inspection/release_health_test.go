@@ -0,0 +1,117 @@
+package release_inspection
+
+import (
+	"testing"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReleaseHealth(t *testing.T) {
+	runs := func(results ...status.JobOverallResult) []status.JobRunResults {
+		ret := []status.JobRunResults{}
+		for _, result := range results {
+			ret = append(ret, status.JobRunResults{OverallResult: result})
+		}
+		return ret
+	}
+	s, f, r := status.JobSucceeded, status.JobTestFailure, status.JobRunning
+
+	tests := []struct {
+		name            string
+		blocking        map[string][]status.JobRunResults
+		informing       map[string][]status.JobRunResults
+		expectedVerdict status.ReleaseHealthVerdict
+		expectedReason  string
+	}{
+		{
+			name:            "no runs",
+			expectedVerdict: status.ReleaseHealthInsufficientData,
+			expectedReason:  "Not enough blocking CI runs: bare-minimum has 0 of 3 runs.",
+		},
+		{
+			name:            "one run short of the minimum",
+			blocking:        map[string][]status.JobRunResults{"bare-minimum": runs(s, s)},
+			expectedVerdict: status.ReleaseHealthInsufficientData,
+			expectedReason:  "Not enough blocking CI runs: bare-minimum has 2 of 3 runs.",
+		},
+		{
+			name:            "running jobs don't count towards the minimum",
+			blocking:        map[string][]status.JobRunResults{"bare-minimum": runs(s, s, r)},
+			expectedVerdict: status.ReleaseHealthInsufficientData,
+			expectedReason:  "Not enough blocking CI runs: bare-minimum has 2 of 3 runs.",
+		},
+		{
+			name:            "exactly the minimum",
+			blocking:        map[string][]status.JobRunResults{"bare-minimum": runs(s, s, s)},
+			expectedVerdict: status.ReleaseHealthBlockingGreen,
+			expectedReason:  "Every blocking job variant passed at least 80% of 3 or more runs.",
+		},
+		{
+			name:            "exactly the required pass rate",
+			blocking:        map[string][]status.JobRunResults{"bare-minimum": runs(s, s, s, s, f)},
+			expectedVerdict: status.ReleaseHealthBlockingGreen,
+			expectedReason:  "Every blocking job variant passed at least 80% of 3 or more runs.",
+		},
+		{
+			name:            "just below the required pass rate",
+			blocking:        map[string][]status.JobRunResults{"bare-minimum": runs(s, s, s, f)},
+			expectedVerdict: status.ReleaseHealthBlockingRed,
+			expectedReason:  "Blocking CI below 80%: bare-minimum passed 3 of 4 runs.",
+		},
+		{
+			name: "failing wins over insufficient",
+			blocking: map[string][]status.JobRunResults{
+				"bare-minimum": runs(f, f, f),
+				"other":        runs(s),
+			},
+			expectedVerdict: status.ReleaseHealthBlockingRed,
+			expectedReason:  "Blocking CI below 80%: bare-minimum passed 0 of 3 runs.",
+		},
+		{
+			name: "blocking variants not in the hardcoded list count too",
+			blocking: map[string][]status.JobRunResults{
+				"bare-minimum": runs(s, s, s),
+				"other":        runs(s),
+			},
+			expectedVerdict: status.ReleaseHealthInsufficientData,
+			expectedReason:  "Not enough blocking CI runs: other has 1 of 3 runs.",
+		},
+		{
+			name:            "informing failures are ignored",
+			blocking:        map[string][]status.JobRunResults{"bare-minimum": runs(s, s, s)},
+			informing:       map[string][]status.JobRunResults{"e2e-parallel": runs(f, f, f)},
+			expectedVerdict: status.ReleaseHealthBlockingGreen,
+			expectedReason:  "Every blocking job variant passed at least 80% of 3 or more runs.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			environmentRelease := &status.EnvironmentRelease{
+				BlockingJobRunResults:  tt.blocking,
+				InformingJobRunResults: tt.informing,
+			}
+			actual := NewReleaseHealth(environmentRelease, DefaultMinimumRuns, DefaultRequiredPassRate)
+			assert.Equal(t, tt.expectedVerdict, actual.Verdict)
+			assert.Equal(t, tt.expectedReason, actual.Reason)
+		})
+	}
+}
+
+func TestJobVariantHealth(t *testing.T) {
+	actual := jobVariantHealth("e2e-parallel", JobImpactInforming, []status.JobRunResults{
+		{OverallResult: status.JobSucceeded},
+		{OverallResult: status.JobRunning},
+		{OverallResult: status.JobInfrastructureFailure},
+		{OverallResult: status.JobAborted},
+	})
+	assert.Equal(t, status.JobVariantHealth{
+		Name:      "e2e-parallel",
+		Category:  string(JobImpactInforming),
+		Runs:      3,
+		Succeeded: 1,
+		PassRate:  1.0 / 3,
+	}, actual)
+
+	assert.Zero(t, jobVariantHealth("e2e-parallel", JobImpactBlocking, nil).PassRate)
+}
@@ -0,0 +1,182 @@
+package release_inspection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+var (
+	cacheRequests = promauto.With(metrics.Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "service_status_cache_requests_total",
+			Help: "Requests to the release caches by whether they were answered from the cache.",
+		},
+		[]string{"cache", "result"},
+	)
+	componentDiffUnavailable = promauto.With(metrics.Registry).NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "service_status_component_diff_unavailable",
+			Help: "1 when the latest computed diff listing a component could not list some of its changes, 0 when it listed all of them.",
+		},
+		[]string{"component"},
+	)
+	releaseMetricsLastCollectionSuccess = promauto.With(metrics.Registry).NewGauge(
+		prometheus.GaugeOpts{
+			Name: "service_status_release_metrics_last_collection_success",
+			Help: "1 when the release gauges were computed at the last collection, 0 when they could not be.",
+		},
+	)
+
+	latestReleaseAgeDesc = prometheus.NewDesc(
+		"service_status_latest_release_age_seconds",
+		"Time since the commit of the latest release of each environment.",
+		[]string{"environment"}, nil,
+	)
+	componentImageAgeDesc = prometheus.NewDesc(
+		"service_status_component_image_age_seconds",
+		"Age of each component image in the latest release of each environment.",
+		[]string{"environment", "component"}, nil,
+	)
+	componentLatencyThresholdDesc = prometheus.NewDesc(
+		"service_status_component_latency_threshold_seconds",
+		"How old a component image may get before it should be updated.",
+		[]string{"component"}, nil,
+	)
+	blockingCIPassRatioDesc = prometheus.NewDesc(
+		"service_status_blocking_ci_pass_ratio",
+		"Pass ratio of the finished blocking CI runs of the latest release with any, by job variant.",
+		[]string{"environment", "job_variant"}, nil,
+	)
+)
+
+// ReleaseMetrics computes the release gauges on an interval and serves the last computed values to scrapes, so a
+// scrape never starts a scan and concurrent scrapes read the same values.  The accessor should be the caching one.
+type ReleaseMetrics struct {
+	accessor ReleaseAccessor
+	interval time.Duration
+	clock    clock.Clock
+
+	lock sync.Mutex
+	// collected is replaced as a whole, so a scrape sees the values of one collection.
+	collected []prometheus.Metric
+}
+
+func NewReleaseMetrics(accessor ReleaseAccessor, interval time.Duration, clock clock.Clock) *ReleaseMetrics {
+	return &ReleaseMetrics{
+		accessor: accessor,
+		interval: interval,
+		clock:    clock,
+	}
+}
+
+// Run collects immediately and then on the configured interval until the context is done.
+func (m *ReleaseMetrics) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting release metrics", "interval", m.interval)
+
+	for {
+		if err := m.collect(ctx); err != nil {
+			logger.Error(err, "failed to collect release metrics")
+			releaseMetricsLastCollectionSuccess.Set(0)
+		} else {
+			releaseMetricsLastCollectionSuccess.Set(1)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.clock.After(m.interval):
+		}
+	}
+}
+
+func (m *ReleaseMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- latestReleaseAgeDesc
+	ch <- componentImageAgeDesc
+	ch <- componentLatencyThresholdDesc
+	ch <- blockingCIPassRatioDesc
+}
+
+func (m *ReleaseMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.lock.Lock()
+	collected := m.collected
+	m.lock.Unlock()
+
+	for _, metric := range collected {
+		ch <- metric
+	}
+}
+
+// collect keeps the previous values when it fails, they are more useful than none.
+func (m *ReleaseMetrics) collect(ctx context.Context) error {
+	collected, err := collectReleaseMetrics(ctx, m.accessor, m.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.collected = collected
+	return nil
+}
+
+func collectReleaseMetrics(ctx context.Context, accessor ReleaseAccessor, now time.Time) ([]prometheus.Metric, error) {
+	environments, err := accessor.ListEnvironments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	ret := []prometheus.Metric{}
+	for componentName, component := range HardcodedComponents {
+		if component.LatencyThreshold > 0 {
+			ret = append(ret, prometheus.MustNewConstMetric(componentLatencyThresholdDesc, prometheus.GaugeValue, component.LatencyThreshold.Seconds(), componentName))
+		}
+	}
+
+	for _, environment := range environments {
+		environmentReleases, err := accessor.ListEnvironmentReleasesForEnvironment(ctx, environment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environment releases for %q: %w", environment, err)
+		}
+		if len(environmentReleases.Items) == 0 {
+			continue
+		}
+
+		latest := environmentReleases.Items[0]
+		if _, releaseTime, _, ok := status.SplitReleaseName(latest.ReleaseName); ok {
+			ret = append(ret, prometheus.MustNewConstMetric(latestReleaseAgeDesc, prometheus.GaugeValue, now.Sub(releaseTime).Seconds(), environment))
+		}
+		for componentName, component := range latest.Components {
+			if component.ImageCreationTime != nil {
+				ret = append(ret, prometheus.MustNewConstMetric(componentImageAgeDesc, prometheus.GaugeValue, now.Sub(*component.ImageCreationTime).Seconds(), environment, componentName))
+			}
+		}
+
+		// the newest release often hasn't been tested yet, so report the newest one that has been.
+		for _, environmentRelease := range environmentReleases.Items {
+			if !hasJobRuns(&environmentRelease) {
+				continue
+			}
+			health := environmentRelease.Health
+			if health == nil {
+				health = NewReleaseHealth(&environmentRelease, DefaultMinimumRuns, DefaultRequiredPassRate)
+			}
+			for _, jobVariant := range health.JobVariants {
+				if jobVariant.Category == string(JobImpactBlocking) && jobVariant.Runs > 0 {
+					ret = append(ret, prometheus.MustNewConstMetric(blockingCIPassRatioDesc, prometheus.GaugeValue, jobVariant.PassRate, environment, jobVariant.Name))
+				}
+			}
+			break
+		}
+	}
+	return ret, nil
+}
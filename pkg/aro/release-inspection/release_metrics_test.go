@@ -0,0 +1,63 @@
+package release_inspection
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+)
+
+func TestReleaseMetricsServeTheLastCollection(t *testing.T) {
+	now := time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)
+	accessor := &fakeEnvironmentReleasesAccessor{environmentToReleases: map[string]*status.EnvironmentReleaseList{
+		"int": {Items: []status.EnvironmentRelease{
+			{
+				ReleaseName: "2025-08-04T00:00:00Z-abc",
+				Components: map[string]*status.Component{
+					"Backend": {Name: "Backend", ImageCreationTime: ptr.To(now.Add(-2 * time.Hour))},
+				},
+			},
+		}},
+	}}
+	releaseMetrics := NewReleaseMetrics(accessor, time.Minute, clocktesting.NewFakeClock(now))
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(releaseMetrics))
+
+	// nothing is computed by a scrape.
+	count, err := testutil.GatherAndCount(registry)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	require.NoError(t, releaseMetrics.collect(context.Background()))
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP service_status_component_image_age_seconds Age of each component image in the latest release of each environment.
+# TYPE service_status_component_image_age_seconds gauge
+service_status_component_image_age_seconds{component="Backend",environment="int"} 7200
+# HELP service_status_latest_release_age_seconds Time since the commit of the latest release of each environment.
+# TYPE service_status_latest_release_age_seconds gauge
+service_status_latest_release_age_seconds{environment="int"} 86400
+`), "service_status_component_image_age_seconds", "service_status_latest_release_age_seconds"))
+
+	// a failed collection keeps serving the previous values.
+	releaseMetrics.accessor = &failingListEnvironmentsAccessor{}
+	require.Error(t, releaseMetrics.collect(context.Background()))
+	count, err = testutil.GatherAndCount(registry, "service_status_latest_release_age_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+type failingListEnvironmentsAccessor struct {
+	ReleaseAccessor
+}
+
+func (failingListEnvironmentsAccessor) ListEnvironments(ctx context.Context) ([]string, error) {
+	return nil, context.DeadlineExceeded
+}
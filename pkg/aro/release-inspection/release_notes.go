@@ -0,0 +1,339 @@
+package release_inspection
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+)
+
+// ReleaseNotes describes everything that changed between two environment releases.  They are pasted into
+// change-management tickets, so everything needed to review a rollout should be here.
+type ReleaseNotes struct {
+	From *status.EnvironmentRelease
+	To   *status.EnvironmentRelease
+
+	AROHCPCompareURL string
+	Components       []ComponentReleaseNotes
+	JIRARefs         []string
+
+	CIVerdict  string
+	CIVariants []CIVariantReleaseNotes
+}
+
+type ComponentReleaseNotes struct {
+	Name    string
+	RepoURL string
+
+	FromSourceSHA string
+	ToSourceSHA   string
+	CompareURL    string
+
+	// ConfigChanged is set when the image reference in the ARO-HCP config changed.
+	ConfigChanged bool
+	FromPullSpec  string
+	ToPullSpec    string
+
+	NumberOfChanges int
+	// SearchDepthExceeded is set when NumberOfChanges only counts the changes found within the search depth.
+	SearchDepthExceeded bool
+	Changes             []ChangeReleaseNotes
+	// NotListedChanges counts the changes the diff didn't list, like those left out of a snapshot.
+	NotListedChanges int
+	Unavailable      []string
+}
+
+type ChangeReleaseNotes struct {
+	Title     string
+	Reference string
+	URL       string
+	Author    string
+	JIRARefs  []string
+}
+
+type CIVariantReleaseNotes struct {
+	Name      string
+	Category  JobCategory
+	Succeeded int
+	Runs      int
+}
+
+// NewReleaseNotes builds release notes for moving from the from release to the to release.  diff must be the
+// EnvironmentReleaseDiff of to against from.
+func NewReleaseNotes(from, to *status.EnvironmentRelease, diff *status.EnvironmentReleaseDiff) *ReleaseNotes {
+	ret := &ReleaseNotes{
+		From:             from,
+		To:               to,
+		AROHCPCompareURL: fmt.Sprintf("%s/compare/%s...%s", aroHCPRepositoryURL, from.SHA, to.SHA),
+	}
+
+	allJIRARefs := set.New[string]()
+	for _, componentName := range ChangedComponents(to, from).SortedList() {
+		toComponent := to.Components[componentName]
+		fromComponent := from.Components[componentName]
+		if toComponent == nil || fromComponent == nil {
+			continue
+		}
+
+		componentNotes := ComponentReleaseNotes{
+			Name:            componentName,
+			RepoURL:         ptr.Deref(toComponent.RepoURL, ""),
+			FromSourceSHA:   fromComponent.SourceSHA,
+			ToSourceSHA:     toComponent.SourceSHA,
+			FromPullSpec:    pullSpecOrEmpty(&fromComponent.ImageInfo),
+			ToPullSpec:      pullSpecOrEmpty(&toComponent.ImageInfo),
+			NumberOfChanges: -1,
+		}
+		componentNotes.ConfigChanged = componentNotes.FromPullSpec != componentNotes.ToPullSpec
+		if len(componentNotes.RepoURL) > 0 && len(fromComponent.SourceSHA) > 0 && len(toComponent.SourceSHA) > 0 {
+			componentNotes.CompareURL = compareURL(componentNotes.RepoURL, fromComponent.SourceSHA, toComponent.SourceSHA)
+		}
+
+		var componentDiff *status.ComponentDiff
+		if diff != nil {
+			componentDiff = diff.DifferentComponents[componentName]
+		}
+		if componentDiff != nil {
+			componentNotes.NumberOfChanges = componentDiff.NumberOfChanges
+			componentNotes.SearchDepthExceeded = componentDiff.SearchDepthExceeded
+			for _, change := range componentDiff.Changes {
+				switch {
+				case change.GithubPRMerge != nil:
+					componentNotes.Changes = append(componentNotes.Changes, ChangeReleaseNotes{
+						Title:     change.GithubPRMerge.ChangeSummary,
+						Reference: fmt.Sprintf("#%d", change.GithubPRMerge.PRNumber),
+						URL:       pullRequestURL(componentNotes.RepoURL, change.GithubPRMerge.PRNumber, change.GithubPRMerge.URL),
+						Author:    change.GithubPRMerge.Author,
+						JIRARefs:  change.GithubPRMerge.JIRARefs,
+					})
+					allJIRARefs.Insert(change.GithubPRMerge.JIRARefs...)
+				case change.GitlabMRMerge != nil:
+					componentNotes.Changes = append(componentNotes.Changes, ChangeReleaseNotes{
+						Title:     change.GitlabMRMerge.ChangeSummary,
+						Reference: fmt.Sprintf("!%d", change.GitlabMRMerge.MRNumber),
+						URL:       pullRequestURL(componentNotes.RepoURL, change.GitlabMRMerge.MRNumber, change.GitlabMRMerge.URL),
+						Author:    change.GitlabMRMerge.Author,
+						JIRARefs:  change.GitlabMRMerge.JIRARefs,
+					})
+					allJIRARefs.Insert(change.GitlabMRMerge.JIRARefs...)
+				case change.Unavailable != nil:
+					componentNotes.Unavailable = append(componentNotes.Unavailable, *change.Unavailable)
+				}
+			}
+		}
+
+		componentNotes.NotListedChanges = max(componentNotes.NumberOfChanges-len(componentNotes.Changes), 0)
+		ret.Components = append(ret.Components, componentNotes)
+	}
+	ret.JIRARefs = allJIRARefs.SortedList()
+	ret.CIVerdict, ret.CIVariants = ciReleaseNotes(to)
+
+	return ret
+}
+
+func ciReleaseNotes(environmentRelease *status.EnvironmentRelease) (string, []CIVariantReleaseNotes) {
+	variants := []CIVariantReleaseNotes{}
+	blockingRuns, blockingSucceeded := 0, 0
+	for _, category := range []JobCategory{JobImpactBlocking, JobImpactInforming} {
+		results := environmentRelease.BlockingJobRunResults
+		if category == JobImpactInforming {
+			results = environmentRelease.InformingJobRunResults
+		}
+		for _, variantName := range set.KeySet(results).SortedList() {
+			variant := CIVariantReleaseNotes{
+				Name:     variantName,
+				Category: category,
+			}
+			for _, jobRun := range results[variantName] {
+				variant.Runs++
+				if jobRun.OverallResult == status.JobSucceeded {
+					variant.Succeeded++
+				}
+			}
+			if category == JobImpactBlocking {
+				blockingRuns += variant.Runs
+				blockingSucceeded += variant.Succeeded
+			}
+			variants = append(variants, variant)
+		}
+	}
+
+	switch {
+	case environmentRelease.Health != nil:
+		return fmt.Sprintf("%s: %s", environmentRelease.Health.Verdict, environmentRelease.Health.Reason), variants
+	case blockingRuns == 0:
+		return "No blocking CI results", variants
+	case blockingRuns == blockingSucceeded:
+		return fmt.Sprintf("Blocking CI passed (%d/%d runs succeeded)", blockingSucceeded, blockingRuns), variants
+	default:
+		return fmt.Sprintf("Blocking CI failed (%d/%d runs succeeded)", blockingSucceeded, blockingRuns), variants
+	}
+}
+
+func pullSpecOrEmpty(containerImage *status.ContainerImage) string {
+	pullSpec, err := PullSpecFromContainerImage(containerImage)
+	if err != nil {
+		return ""
+	}
+	return pullSpec
+}
+
+func compareURL(repoURL, fromSHA, toSHA string) string {
+	repoURL = strings.TrimSuffix(repoURL, "/")
+	if strings.Contains(repoURL, "gitlab.cee.redhat.com") {
+		return fmt.Sprintf("%s/-/compare/%s...%s", repoURL, fromSHA, toSHA)
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", repoURL, fromSHA, toSHA)
+}
+
+// pullRequestURL prefers the URL the API reported and builds one from the repository otherwise.
+func pullRequestURL(repoURL string, number int32, reportedURL string) string {
+	if len(reportedURL) > 0 {
+		return reportedURL
+	}
+	repoURL = strings.TrimSuffix(repoURL, "/")
+	if strings.Contains(repoURL, "gitlab.cee.redhat.com") {
+		return fmt.Sprintf("%s/-/merge_requests/%d", repoURL, number)
+	}
+	return fmt.Sprintf("%s/pull/%d", repoURL, number)
+}
+
+// ShortSHA is the first twelve characters of sha, enough to be unique in practice.
+func ShortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+var releaseNotesFuncs = template.FuncMap{
+	"shortSHA": ShortSHA,
+	"jiraURL":  JIRAURL,
+	"join":     strings.Join,
+}
+
+var markdownReleaseNotesTemplate = template.Must(template.New("markdown").Funcs(releaseNotesFuncs).Parse(
+	`# Release notes: {{ .To.Environment }} {{ .To.ReleaseName }}
+
+Changes from {{ .From.Environment }} {{ .From.ReleaseName }} to {{ .To.Environment }} {{ .To.ReleaseName }}.
+ARO-HCP: [{{ shortSHA .From.SHA }}...{{ shortSHA .To.SHA }}]({{ .AROHCPCompareURL }})
+
+## CI
+
+{{ .CIVerdict }}
+{{ if .CIVariants }}
+| Job variant | Category | Succeeded | Runs |
+| --- | --- | --- | --- |
+{{- range .CIVariants }}
+| {{ .Name }} | {{ .Category }} | {{ .Succeeded }} | {{ .Runs }} |
+{{- end }}
+{{ end }}
+## Components
+{{ if not .Components }}
+No component changes.
+{{ end }}
+{{- range .Components }}
+### {{ .Name }}
+
+{{ if .CompareURL -}}
+- Version: ` + "`{{ shortSHA .FromSourceSHA }}` → `{{ shortSHA .ToSourceSHA }}`" + ` ([compare]({{ .CompareURL }}))
+{{ else -}}
+- Version: ` + "`{{ or (shortSHA .FromSourceSHA) \"unknown\" }}` → `{{ or (shortSHA .ToSourceSHA) \"unknown\" }}`" + `
+{{ end -}}
+{{ if .ConfigChanged -}}
+- Config: ` + "`{{ .FromPullSpec }}` → `{{ .ToPullSpec }}`" + `
+{{ end -}}
+{{ if .SearchDepthExceeded -}}
+- More than {{ .NumberOfChanges }} changes
+{{ else if ge .NumberOfChanges 0 -}}
+- {{ .NumberOfChanges }} changes
+{{ end -}}
+{{ range .Changes }}  - {{ .Title }} ([{{ .Reference }}]({{ .URL }})){{ if .Author }} by {{ .Author }}{{ end }}{{ if .JIRARefs }} ({{ range $i, $ref := .JIRARefs }}{{ if $i }}, {{ end }}[{{ $ref }}]({{ jiraURL $ref }}){{ end }}){{ end }}
+{{ end -}}
+{{ if .NotListedChanges }}  - {{ .NotListedChanges }} more changes not listed
+{{ end -}}
+{{ range .Unavailable }}  - Changes unavailable: {{ . }}
+{{ end }}
+{{- end }}
+{{- if .JIRARefs }}
+## JIRA
+
+{{ range .JIRARefs -}}
+- [{{ . }}]({{ jiraURL . }})
+{{ end -}}
+{{ end -}}
+`))
+
+var textReleaseNotesTemplate = template.Must(template.New("text").Funcs(releaseNotesFuncs).Parse(
+	`Release notes: {{ .To.Environment }} {{ .To.ReleaseName }}
+Changes from {{ .From.Environment }} {{ .From.ReleaseName }} to {{ .To.Environment }} {{ .To.ReleaseName }}.
+ARO-HCP: {{ .AROHCPCompareURL }}
+
+CI: {{ .CIVerdict }}
+{{- range .CIVariants }}
+  {{ .Category }} {{ .Name }}: {{ .Succeeded }}/{{ .Runs }} succeeded
+{{- end }}
+
+Components:
+{{- if not .Components }}
+  No component changes.
+{{- end }}
+{{- range .Components }}
+
+{{ .Name }}
+  Version: {{ or (shortSHA .FromSourceSHA) "unknown" }} -> {{ or (shortSHA .ToSourceSHA) "unknown" }}
+{{- if .CompareURL }}
+  Compare: {{ .CompareURL }}
+{{- end }}
+{{- if .ConfigChanged }}
+  Config: {{ .FromPullSpec }} -> {{ .ToPullSpec }}
+{{- end }}
+{{- if .SearchDepthExceeded }}
+  More than {{ .NumberOfChanges }} changes
+{{- else if ge .NumberOfChanges 0 }}
+  {{ .NumberOfChanges }} changes
+{{- end }}
+{{- range .Changes }}
+  - {{ .Title }} {{ .Reference }} {{ .URL }}{{ if .Author }} by {{ .Author }}{{ end }}{{ if .JIRARefs }} [{{ join .JIRARefs ", " }}]{{ end }}
+{{- end }}
+{{- if .NotListedChanges }}
+  - {{ .NotListedChanges }} more changes not listed
+{{- end }}
+{{- range .Unavailable }}
+  - Changes unavailable: {{ . }}
+{{- end }}
+{{- end }}
+{{- if .JIRARefs }}
+
+JIRA:
+{{- range .JIRARefs }}
+  {{ . }} {{ jiraURL . }}
+{{- end }}
+{{- end }}
+`))
+
+// ValidateReleaseNotesFormat fails for formats WriteReleaseNotes can't render.  It is cheap, so callers check it before
+// fetching anything.
+func ValidateReleaseNotesFormat(format string) error {
+	switch format {
+	case "markdown", "md", "text", "txt":
+		return nil
+	default:
+		return fmt.Errorf("unknown release notes format %q, must be markdown or text", format)
+	}
+}
+
+// WriteReleaseNotes renders the release notes in format, either "markdown" or "text".
+func WriteReleaseNotes(w io.Writer, format string, releaseNotes *ReleaseNotes) error {
+	if err := ValidateReleaseNotesFormat(format); err != nil {
+		return err
+	}
+	if format == "text" || format == "txt" {
+		return textReleaseNotesTemplate.Execute(w, releaseNotes)
+	}
+	return markdownReleaseNotesTemplate.Execute(w, releaseNotes)
+}
@@ -0,0 +1,188 @@
+package release_inspection
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewReleaseNotes(t *testing.T) {
+	image := func(digest string) status.ContainerImage {
+		return status.ContainerImage{Registry: "quay.io", Repository: "app-sre/backend", Digest: digest}
+	}
+	from := &status.EnvironmentRelease{
+		ReleaseName: "2025-08-01T00:00:00Z-aaa",
+		SHA:         "aaa",
+		Environment: "stg",
+		Components: map[string]*status.Component{
+			"Backend":  {Name: "Backend", ImageInfo: image("sha256:old"), RepoURL: ptr.To("https://github.com/Azure/ARO-HCP"), SourceSHA: "1111111111111111"},
+			"Frontend": {Name: "Frontend", ImageInfo: image("sha256:same"), RepoURL: ptr.To("https://github.com/Azure/ARO-HCP"), SourceSHA: "3333"},
+			"Removed":  {Name: "Removed", ImageInfo: image("sha256:gone")},
+			"Maestro":  {Name: "Maestro", ImageInfo: image("sha256:maestro-old"), RepoURL: ptr.To("https://gitlab.cee.redhat.com/service/maestro"), SourceSHA: "4444"},
+		},
+	}
+	to := &status.EnvironmentRelease{
+		ReleaseName: "2025-08-02T00:00:00Z-bbb",
+		SHA:         "bbb",
+		Environment: "stg",
+		Components: map[string]*status.Component{
+			"Backend":  {Name: "Backend", ImageInfo: image("sha256:new"), RepoURL: ptr.To("https://github.com/Azure/ARO-HCP"), SourceSHA: "2222222222222222"},
+			"Frontend": {Name: "Frontend", ImageInfo: image("sha256:same"), RepoURL: ptr.To("https://github.com/Azure/ARO-HCP"), SourceSHA: "3333"},
+			"Added":    {Name: "Added", ImageInfo: image("sha256:added")},
+			"Maestro":  {Name: "Maestro", ImageInfo: image("sha256:maestro-new"), RepoURL: ptr.To("https://gitlab.cee.redhat.com/service/maestro"), SourceSHA: "5555"},
+		},
+		BlockingJobRunResults: map[string][]status.JobRunResults{
+			"e2e-parallel": {{OverallResult: status.JobSucceeded}, {OverallResult: status.JobTestFailure}},
+		},
+		InformingJobRunResults: map[string][]status.JobRunResults{
+			"e2e-serial": {{OverallResult: status.JobSucceeded}},
+		},
+	}
+	diff := &status.EnvironmentReleaseDiff{
+		DifferentComponents: map[string]*status.ComponentDiff{
+			"Backend": {
+				Name:            "Backend",
+				NumberOfChanges: 3,
+				Changes: []status.ComponentChange{
+					{GithubPRMerge: &status.GithubPRMerge{PRNumber: 12, ChangeSummary: "fix the frontend", Author: "someone", JIRARefs: []string{"ARO-2"}}},
+					{GithubPRMerge: &status.GithubPRMerge{PRNumber: 13, ChangeSummary: "bump deps", URL: "https://example.com/pr/13", JIRARefs: []string{"ARO-1", "ARO-2"}}},
+					{Unavailable: ptr.To("too many commits")},
+				},
+			},
+			"Maestro": {
+				Name:            "Maestro",
+				NumberOfChanges: 1,
+				Changes: []status.ComponentChange{
+					{GitlabMRMerge: &status.GitlabMRMerge{MRNumber: 7, ChangeSummary: "tune retries"}},
+				},
+			},
+		},
+	}
+
+	releaseNotes := NewReleaseNotes(from, to, diff)
+	assert.Equal(t, "https://github.com/Azure/ARO-HCP/compare/aaa...bbb", releaseNotes.AROHCPCompareURL)
+	// added and removed components have nothing to compare, and unchanged ones aren't listed.
+	require.Len(t, releaseNotes.Components, 2)
+
+	backend := releaseNotes.Components[0]
+	assert.Equal(t, "Backend", backend.Name)
+	assert.Equal(t, "https://github.com/Azure/ARO-HCP/compare/1111111111111111...2222222222222222", backend.CompareURL)
+	assert.True(t, backend.ConfigChanged)
+	assert.Equal(t, "quay.io/app-sre/backend@sha256:old", backend.FromPullSpec)
+	assert.Equal(t, "quay.io/app-sre/backend@sha256:new", backend.ToPullSpec)
+	assert.Equal(t, 3, backend.NumberOfChanges)
+	assert.Equal(t, []ChangeReleaseNotes{
+		{Title: "fix the frontend", Reference: "#12", URL: "https://github.com/Azure/ARO-HCP/pull/12", Author: "someone", JIRARefs: []string{"ARO-2"}},
+		{Title: "bump deps", Reference: "#13", URL: "https://example.com/pr/13", JIRARefs: []string{"ARO-1", "ARO-2"}},
+	}, backend.Changes)
+	assert.Equal(t, []string{"too many commits"}, backend.Unavailable)
+	// only two of the three changes are listed, the notes must say so rather than look complete.
+	assert.Equal(t, 1, backend.NotListedChanges)
+
+	maestro := releaseNotes.Components[1]
+	assert.Equal(t, "https://gitlab.cee.redhat.com/service/maestro/-/compare/4444...5555", maestro.CompareURL)
+	assert.Equal(t, []ChangeReleaseNotes{
+		{Title: "tune retries", Reference: "!7", URL: "https://gitlab.cee.redhat.com/service/maestro/-/merge_requests/7"},
+	}, maestro.Changes)
+	assert.Zero(t, maestro.NotListedChanges)
+
+	assert.Equal(t, []string{"ARO-1", "ARO-2"}, releaseNotes.JIRARefs)
+	assert.Equal(t, "Blocking CI failed (1/2 runs succeeded)", releaseNotes.CIVerdict)
+	assert.Equal(t, []CIVariantReleaseNotes{
+		{Name: "e2e-parallel", Category: JobImpactBlocking, Succeeded: 1, Runs: 2},
+		{Name: "e2e-serial", Category: JobImpactInforming, Succeeded: 1, Runs: 1},
+	}, releaseNotes.CIVariants)
+
+	// without a diff the number of changes is unknown.
+	releaseNotes = NewReleaseNotes(from, to, nil)
+	assert.Equal(t, -1, releaseNotes.Components[0].NumberOfChanges)
+	assert.Zero(t, releaseNotes.Components[0].NotListedChanges)
+	assert.Empty(t, releaseNotes.JIRARefs)
+}
+
+func TestCIReleaseNotesVerdict(t *testing.T) {
+	tests := []struct {
+		name               string
+		environmentRelease *status.EnvironmentRelease
+		expected           string
+	}{
+		{
+			name:               "no runs",
+			environmentRelease: &status.EnvironmentRelease{},
+			expected:           "No blocking CI results",
+		},
+		{
+			name: "only informing runs",
+			environmentRelease: &status.EnvironmentRelease{InformingJobRunResults: map[string][]status.JobRunResults{
+				"e2e-serial": {{OverallResult: status.JobTestFailure}},
+			}},
+			expected: "No blocking CI results",
+		},
+		{
+			name: "all blocking runs passed",
+			environmentRelease: &status.EnvironmentRelease{BlockingJobRunResults: map[string][]status.JobRunResults{
+				"e2e-parallel": {{OverallResult: status.JobSucceeded}, {OverallResult: status.JobSucceeded}},
+			}},
+			expected: "Blocking CI passed (2/2 runs succeeded)",
+		},
+		{
+			name: "health wins",
+			environmentRelease: &status.EnvironmentRelease{
+				BlockingJobRunResults: map[string][]status.JobRunResults{
+					"e2e-parallel": {{OverallResult: status.JobSucceeded}},
+				},
+				Health: &status.ReleaseHealth{Verdict: status.ReleaseHealthInsufficientData, Reason: "1 of 3 runs"},
+			},
+			expected: string(status.ReleaseHealthInsufficientData) + ": 1 of 3 runs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, _ := ciReleaseNotes(tt.environmentRelease)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestWriteReleaseNotes(t *testing.T) {
+	releaseNotes := &ReleaseNotes{
+		From:             &status.EnvironmentRelease{Environment: "stg", ReleaseName: "from", SHA: "aaa"},
+		To:               &status.EnvironmentRelease{Environment: "prod", ReleaseName: "to", SHA: "bbb"},
+		AROHCPCompareURL: "https://github.com/Azure/ARO-HCP/compare/aaa...bbb",
+		CIVerdict:        "No blocking CI results",
+	}
+
+	for _, format := range []string{"markdown", "md", "text", "txt"} {
+		t.Run(format, func(t *testing.T) {
+			require.NoError(t, ValidateReleaseNotesFormat(format))
+			out := &bytes.Buffer{}
+			require.NoError(t, WriteReleaseNotes(out, format, releaseNotes))
+			assert.Contains(t, out.String(), "Release notes: prod to")
+			assert.Contains(t, out.String(), "No component changes.")
+		})
+	}
+
+	truncatedReleaseNotes := *releaseNotes
+	truncatedReleaseNotes.Components = []ComponentReleaseNotes{
+		{Name: "Backend", NumberOfChanges: 4, Changes: []ChangeReleaseNotes{{Title: "bump deps", Reference: "#13"}}, NotListedChanges: 3},
+		{Name: "Frontend", NumberOfChanges: 7, SearchDepthExceeded: true},
+	}
+	for _, format := range []string{"markdown", "text"} {
+		t.Run(format+" with changes not listed", func(t *testing.T) {
+			out := &bytes.Buffer{}
+			require.NoError(t, WriteReleaseNotes(out, format, &truncatedReleaseNotes))
+			assert.Contains(t, out.String(), "4 changes")
+			assert.Contains(t, out.String(), "  - 3 more changes not listed\n")
+			assert.Contains(t, out.String(), "More than 7 changes")
+		})
+	}
+
+	out := &bytes.Buffer{}
+	assert.EqualError(t, ValidateReleaseNotesFormat("html"), `unknown release notes format "html", must be markdown or text`)
+	assert.Error(t, WriteReleaseNotes(out, "html", releaseNotes))
+	assert.Empty(t, out.String())
+}
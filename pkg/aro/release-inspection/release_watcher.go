@@ -0,0 +1,242 @@
+package release_inspection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// maxWatchHistory bounds how many events are kept for resuming watches.  Older resource versions get an Expired
+	// error and must list again.
+	maxWatchHistory = 1000
+	// watchChannelSize is how far a watcher may fall behind before it is dropped.  The client can resume from the last
+	// resource version it saw.
+	watchChannelSize = 100
+)
+
+// EnvironmentReleaseWatcher polls the accessor and turns the differences between polls into watch events.  Polling the
+// caching accessor is cheap; new releases and CI results show up whenever its cache expires.
+type EnvironmentReleaseWatcher struct {
+	accessor ReleaseAccessor
+	interval time.Duration
+	clock    clock.Clock
+
+	lock sync.Mutex
+	// running is set while Run is polling.  Without it nothing would ever be sent to a watch, and it would never end.
+	running         bool
+	resourceVersion int64
+	nameToObserved  map[string]*observedEnvironmentRelease
+	// history holds the latest events, oldest first.
+	history     []status.EnvironmentReleaseWatchEvent
+	nextWatchID int
+	idToWatch   map[int]*environmentReleaseWatch
+}
+
+type observedEnvironmentRelease struct {
+	content            string
+	environmentRelease status.EnvironmentRelease
+}
+
+type environmentReleaseWatch struct {
+	environmentName string
+	events          chan status.EnvironmentReleaseWatchEvent
+}
+
+func NewEnvironmentReleaseWatcher(accessor ReleaseAccessor, interval time.Duration, clock clock.Clock) *EnvironmentReleaseWatcher {
+	return &EnvironmentReleaseWatcher{
+		accessor:       accessor,
+		interval:       interval,
+		clock:          clock,
+		nameToObserved: map[string]*observedEnvironmentRelease{},
+		idToWatch:      map[int]*environmentReleaseWatch{},
+	}
+}
+
+// Run polls immediately and then on the configured interval until the context is done.
+func (w *EnvironmentReleaseWatcher) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting environment release watcher", "interval", w.interval)
+	w.lock.Lock()
+	w.running = true
+	w.lock.Unlock()
+
+	for {
+		if err := w.poll(ctx); err != nil {
+			logger.Error(err, "failed to poll environment releases")
+		}
+
+		select {
+		case <-ctx.Done():
+			w.closeAll()
+			return
+		case <-w.clock.After(w.interval):
+		}
+	}
+}
+
+func (w *EnvironmentReleaseWatcher) poll(ctx context.Context) error {
+	environmentReleases, err := w.accessor.ListEnvironmentReleases(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list environment releases: %w", err)
+	}
+
+	// oldest first so resource versions follow release order on the first poll.  The list may be shared with a cache,
+	// so it is copied rather than reversed in place.
+	items := slices.Clone(environmentReleases.Items)
+	slices.Reverse(items)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, environmentRelease := range items {
+		environmentRelease.ResourceVersion = ""
+		contentBytes, err := json.Marshal(environmentRelease)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %q: %w", environmentRelease.Name, err)
+		}
+		content := string(contentBytes)
+
+		eventType := status.WatchEventModified
+		observed, ok := w.nameToObserved[environmentRelease.Name]
+		switch {
+		case !ok:
+			eventType = status.WatchEventAdded
+			observed = &observedEnvironmentRelease{}
+			w.nameToObserved[environmentRelease.Name] = observed
+		case observed.content == content:
+			continue
+		}
+
+		w.resourceVersion++
+		environmentRelease.ResourceVersion = strconv.FormatInt(w.resourceVersion, 10)
+		observed.content = content
+		observed.environmentRelease = environmentRelease
+		w.broadcast(status.EnvironmentReleaseWatchEvent{
+			Type:   eventType,
+			Object: environmentRelease,
+		})
+	}
+	return nil
+}
+
+// broadcast must be called with the lock held.
+func (w *EnvironmentReleaseWatcher) broadcast(event status.EnvironmentReleaseWatchEvent) {
+	w.history = append(w.history, event)
+	if len(w.history) > maxWatchHistory {
+		w.history = w.history[len(w.history)-maxWatchHistory:]
+	}
+
+	for id, watch := range w.idToWatch {
+		if len(watch.environmentName) > 0 && watch.environmentName != event.Object.Environment {
+			continue
+		}
+		select {
+		case watch.events <- event:
+		default:
+			// a slow client must not hold up everyone else.  It resumes from the last event it read.
+			close(watch.events)
+			delete(w.idToWatch, id)
+		}
+	}
+}
+
+func (w *EnvironmentReleaseWatcher) closeAll() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.running = false
+	for id, watch := range w.idToWatch {
+		close(watch.events)
+		delete(w.idToWatch, id)
+	}
+}
+
+// ResourceVersion returns the latest resource version handed out.
+func (w *EnvironmentReleaseWatcher) ResourceVersion() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return strconv.FormatInt(w.resourceVersion, 10)
+}
+
+// Watch streams changes to the environment releases of environmentName, or of every environment when it is empty.
+// Without a resourceVersion every known release is sent as ADDED first.  With one, every remembered change after it is
+// replayed.  The channel is closed when the watch falls too far behind or the watcher stops; call stop when done.
+// Watch fails with ServiceUnavailable when Run isn't polling.
+func (w *EnvironmentReleaseWatcher) Watch(environmentName, resourceVersion string) (<-chan status.EnvironmentReleaseWatchEvent, func(), error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if !w.running {
+		return nil, nil, status.NewServiceUnavailable("the environment release watcher is not running")
+	}
+
+	initialEvents := []status.EnvironmentReleaseWatchEvent{}
+	matches := func(event status.EnvironmentReleaseWatchEvent) bool {
+		return len(environmentName) == 0 || event.Object.Environment == environmentName
+	}
+	if len(resourceVersion) == 0 || resourceVersion == "0" {
+		for _, observed := range w.nameToObserved {
+			event := status.EnvironmentReleaseWatchEvent{Type: status.WatchEventAdded, Object: observed.environmentRelease}
+			if matches(event) {
+				initialEvents = append(initialEvents, event)
+			}
+		}
+		sort.Slice(initialEvents, func(i, j int) bool {
+			return resourceVersionOf(initialEvents[i]) < resourceVersionOf(initialEvents[j])
+		})
+	} else {
+		fromResourceVersion, err := strconv.ParseInt(resourceVersion, 10, 64)
+		if err != nil || fromResourceVersion < 0 {
+			return nil, nil, status.NewBadRequest(fmt.Sprintf("resourceVersion must be a non-negative integer, not %q", resourceVersion))
+		}
+		if len(w.history) > 0 && fromResourceVersion < resourceVersionOf(w.history[0])-1 {
+			return nil, nil, status.NewExpired(fmt.Sprintf("resourceVersion %v is too old, the oldest remembered is %v", resourceVersion, resourceVersionOf(w.history[0])))
+		}
+		if fromResourceVersion > w.resourceVersion {
+			// resource versions start over when the server restarts.
+			return nil, nil, status.NewExpired(fmt.Sprintf("resourceVersion %v is newer than the latest, %v", resourceVersion, w.resourceVersion))
+		}
+		for _, event := range w.history {
+			if resourceVersionOf(event) > fromResourceVersion && matches(event) {
+				initialEvents = append(initialEvents, event)
+			}
+		}
+	}
+
+	watch := &environmentReleaseWatch{
+		environmentName: environmentName,
+		events:          make(chan status.EnvironmentReleaseWatchEvent, len(initialEvents)+watchChannelSize),
+	}
+	for _, event := range initialEvents {
+		watch.events <- event
+	}
+	id := w.nextWatchID
+	w.nextWatchID++
+	w.idToWatch[id] = watch
+
+	stop := func() {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		if _, ok := w.idToWatch[id]; ok {
+			close(watch.events)
+			delete(w.idToWatch, id)
+		}
+	}
+	return watch.events, stop, nil
+}
+
+func resourceVersionOf(event status.EnvironmentReleaseWatchEvent) int64 {
+	ret, _ := strconv.ParseInt(event.Object.ResourceVersion, 10, 64)
+	return ret
+}
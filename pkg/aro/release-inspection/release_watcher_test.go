@@ -0,0 +1,104 @@
+package release_inspection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+type fakeListReleaseAccessor struct {
+	ReleaseAccessor
+	environmentReleases *status.EnvironmentReleaseList
+}
+
+func (a *fakeListReleaseAccessor) ListEnvironmentReleases(ctx context.Context) (*status.EnvironmentReleaseList, error) {
+	return a.environmentReleases, nil
+}
+
+func TestEnvironmentReleaseWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	accessor := &fakeListReleaseAccessor{
+		environmentReleases: &status.EnvironmentReleaseList{
+			// newest first, like the accessor.
+			Items: []status.EnvironmentRelease{
+				{Name: "int---b", Environment: "int", SHA: "b"},
+				{Name: "stg---a", Environment: "stg", SHA: "a"},
+			},
+		},
+	}
+	watcher := NewEnvironmentReleaseWatcher(accessor, time.Minute, clocktesting.NewFakeClock(time.Now()))
+	// the fake clock never steps, so Run only polls once and the test polls from then on.
+	go watcher.Run(ctx)
+	require.Eventually(t, func() bool { return watcher.ResourceVersion() == "2" }, 10*time.Second, time.Millisecond)
+
+	events, stop, err := watcher.Watch("", "")
+	require.NoError(t, err)
+	defer stop()
+	assert.Equal(t, status.EnvironmentReleaseWatchEvent{Type: status.WatchEventAdded, Object: status.EnvironmentRelease{Name: "stg---a", Environment: "stg", SHA: "a", ResourceVersion: "1"}}, <-events)
+	assert.Equal(t, status.EnvironmentReleaseWatchEvent{Type: status.WatchEventAdded, Object: status.EnvironmentRelease{Name: "int---b", Environment: "int", SHA: "b", ResourceVersion: "2"}}, <-events)
+
+	// unchanged releases send nothing, changed ones are MODIFIED.
+	require.NoError(t, watcher.poll(ctx))
+	accessor.environmentReleases = &status.EnvironmentReleaseList{
+		Items: []status.EnvironmentRelease{
+			{Name: "int---b", Environment: "int", SHA: "b", Health: &status.ReleaseHealth{Verdict: status.ReleaseHealthBlockingGreen}},
+			{Name: "stg---a", Environment: "stg", SHA: "a"},
+		},
+	}
+	require.NoError(t, watcher.poll(ctx))
+	modified := <-events
+	assert.Equal(t, status.WatchEventModified, modified.Type)
+	assert.Equal(t, "3", modified.Object.ResourceVersion)
+	assert.Empty(t, events)
+
+	resumed, stopResumed, err := watcher.Watch("int", "1")
+	require.NoError(t, err)
+	defer stopResumed()
+	assert.Equal(t, "2", (<-resumed).Object.ResourceVersion)
+	assert.Equal(t, "3", (<-resumed).Object.ResourceVersion)
+	assert.Empty(t, resumed)
+
+	_, _, err = watcher.Watch("", "4")
+	assert.True(t, status.IsExpired(err), "expected expired, got %v", err)
+}
+
+func TestEnvironmentReleaseWatcherNotRunning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	accessor := &fakeListReleaseAccessor{environmentReleases: &status.EnvironmentReleaseList{}}
+	watcher := NewEnvironmentReleaseWatcher(accessor, time.Minute, clocktesting.NewFakeClock(time.Now()))
+
+	_, _, err := watcher.Watch("", "")
+	assert.True(t, status.IsServiceUnavailable(err), "expected service unavailable before Run, got %v", err)
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		watcher.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		_, stop, err := watcher.Watch("", "")
+		if err != nil {
+			return false
+		}
+		stop()
+		return true
+	}, 10*time.Second, time.Millisecond)
+
+	events, stop, err := watcher.Watch("", "")
+	require.NoError(t, err)
+	defer stop()
+	cancel()
+	<-stopped
+
+	// open watches end and new ones fail instead of waiting forever.
+	_, ok := <-events
+	assert.False(t, ok)
+	_, _, err = watcher.Watch("", "")
+	assert.True(t, status.IsServiceUnavailable(err), "expected service unavailable after Run, got %v", err)
+}
@@ -0,0 +1,155 @@
+package release_inspection
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/metrics"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+)
+
+// RefreshableRepository is a local git repository that is kept up to date with its origin in the background.
+type RefreshableRepository interface {
+	Name() string
+	URL() string
+	Refresh(ctx context.Context) error
+}
+
+// RepositoryRefresher refreshes every repository on its own schedule so that request paths only read local objects.
+type RepositoryRefresher struct {
+	repositories []RefreshableRepository
+	interval     time.Duration
+	// jitterFactor spreads refreshes out so a dozen repositories don't hit the network at the same moment.
+	jitterFactor float64
+	// initialBackoff is the first retry delay after a failed refresh.  It doubles until it reaches the interval.
+	initialBackoff time.Duration
+	clock          clock.Clock
+
+	lock     sync.RWMutex
+	statuses map[string]*status.Repository
+}
+
+func NewRepositoryRefresher(repositories []RefreshableRepository, interval time.Duration, clock clock.Clock) *RepositoryRefresher {
+	ret := &RepositoryRefresher{
+		repositories:   repositories,
+		interval:       interval,
+		jitterFactor:   0.1,
+		initialBackoff: 30 * time.Second,
+		clock:          clock,
+		statuses:       map[string]*status.Repository{},
+	}
+	for _, repository := range repositories {
+		ret.statuses[repository.Name()] = &status.Repository{
+			TypeMeta: status.TypeMeta{
+				Kind:       "Repository",
+				APIVersion: "service-status.hcm.openshift.io/v1",
+			},
+			Name: repository.Name(),
+			URL:  repository.URL(),
+		}
+	}
+	return ret
+}
+
+// Run refreshes every repository immediately and then on the configured interval until the context is done.
+func (r *RepositoryRefresher) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting repository refresher", "interval", r.interval, "repositoryCount", len(r.repositories))
+
+	wg := sync.WaitGroup{}
+	for _, repository := range r.repositories {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.runRepository(ctx, repository)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *RepositoryRefresher) runRepository(ctx context.Context, repository RefreshableRepository) {
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "repository", repository.Name())
+	ctx = klog.NewContext(ctx, logger)
+
+	consecutiveFailures := 0
+	for {
+		startTime := r.clock.Now()
+		err := repository.Refresh(ctx)
+		metrics.ObserveExternalCall("git", "refresh", startTime, err)
+		if err != nil {
+			consecutiveFailures++
+			logger.Error(err, "failed to refresh repository", "consecutiveFailures", consecutiveFailures)
+		} else {
+			consecutiveFailures = 0
+			logger.Info("Refreshed repository", "duration", r.clock.Since(startTime))
+		}
+
+		delay := r.nextDelay(consecutiveFailures)
+		r.recordRefresh(repository.Name(), startTime, err, consecutiveFailures, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.clock.After(delay):
+		}
+	}
+}
+
+// nextDelay returns the interval with jitter after a success and an exponential backoff capped at the interval after
+// a failure.
+func (r *RepositoryRefresher) nextDelay(consecutiveFailures int) time.Duration {
+	delay := r.interval
+	if consecutiveFailures > 0 {
+		delay = r.initialBackoff
+		for i := 1; i < consecutiveFailures && delay < r.interval; i++ {
+			delay *= 2
+		}
+		if delay > r.interval {
+			delay = r.interval
+		}
+	}
+	return delay + time.Duration(rand.Float64()*r.jitterFactor*float64(delay))
+}
+
+func (r *RepositoryRefresher) recordRefresh(name string, fetchTime time.Time, err error, consecutiveFailures int, delay time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	repositoryStatus := r.statuses[name]
+	repositoryStatus.LastFetchTime = ptr.To(fetchTime)
+	repositoryStatus.NextFetchTime = ptr.To(fetchTime.Add(delay))
+	repositoryStatus.ConsecutiveFailures = consecutiveFailures
+	if err != nil {
+		repositoryStatus.LastError = err.Error()
+		return
+	}
+	repositoryStatus.LastError = ""
+	repositoryStatus.LastSuccessfulFetchTime = ptr.To(fetchTime)
+}
+
+// ListRepositories returns the refresh status of every repository, sorted by name.
+func (r *RepositoryRefresher) ListRepositories() *status.RepositoryList {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	ret := &status.RepositoryList{
+		TypeMeta: status.TypeMeta{
+			Kind:       "RepositoryList",
+			APIVersion: "service-status.hcm.openshift.io/v1",
+		},
+		Items: []status.Repository{},
+	}
+	for _, repositoryStatus := range r.statuses {
+		ret.Items = append(ret.Items, *repositoryStatus)
+	}
+	sort.Slice(ret.Items, func(i, j int) bool {
+		return ret.Items[i].Name < ret.Items[j].Name
+	})
+	return ret
+}
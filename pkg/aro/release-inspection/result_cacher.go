@@ -0,0 +1,278 @@
+package release_inspection
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+)
+
+// CachePolicy controls when one cached method recomputes its values.
+type CachePolicy struct {
+	// TTL is how long a value is fresh.  A request for a stale value gets it immediately and starts a recompute.
+	TTL time.Duration
+	// RefreshInterval recomputes every known key in the background once it is this old.  Zero disables it, so keys
+	// are only recomputed after a request finds them stale.
+	RefreshInterval time.Duration
+}
+
+// DefaultCachePolicy matches the old fixed one hour cache.
+var DefaultCachePolicy = CachePolicy{TTL: time.Hour}
+
+// stringBasedResultTimeBasedCacher serves the last good value of each key while recomputing it.  Only the first request
+// for a key waits for the delegate, and concurrent requests for that key share the one computation.
+type stringBasedResultTimeBasedCacher[T any] struct {
+	// name labels the cache metrics.
+	name     string
+	delegate func(ctx context.Context, key string) (T, error)
+	policy   CachePolicy
+	clock    clock.Clock
+
+	lock    sync.Mutex
+	entries map[string]*cacheEntry[T]
+}
+
+const (
+	// idleCacheEntryTimeout drops keys nobody requested for this long.  Diff and release keys come from URLs, so
+	// without it every key ever requested would be kept and refreshed.
+	idleCacheEntryTimeout = 24 * time.Hour
+	// failed refreshes are retried after a backoff that doubles with every consecutive failure.
+	initialRefreshBackoff = 30 * time.Second
+	maxRefreshBackoff     = 30 * time.Minute
+)
+
+type cacheEntry[T any] struct {
+	hasValue    bool
+	value       T
+	lastRefresh time.Time
+	lastRequest time.Time
+
+	// inflight is closed when the current computation finishes.  It is nil when nothing is computing.
+	inflight      chan struct{}
+	lastError     error
+	lastErrorTime time.Time
+	// consecutiveFailures sets the backoff before the next refresh.  It is reset by a successful refresh.
+	consecutiveFailures int
+}
+
+func (r *stringBasedResultTimeBasedCacher[T]) entry(key string) *cacheEntry[T] {
+	if r.entries == nil {
+		r.entries = map[string]*cacheEntry[T]{}
+	}
+	if _, ok := r.entries[key]; !ok {
+		r.entries[key] = &cacheEntry[T]{}
+	}
+	return r.entries[key]
+}
+
+// backingOff is true while the entry waits to retry a failed refresh.  It must be called with the lock held.
+func (r *stringBasedResultTimeBasedCacher[T]) backingOff(entry *cacheEntry[T]) bool {
+	if entry.consecutiveFailures == 0 {
+		return false
+	}
+	backoff := initialRefreshBackoff
+	for i := 1; i < entry.consecutiveFailures && backoff < maxRefreshBackoff; i++ {
+		backoff *= 2
+	}
+	return r.clock.Since(entry.lastErrorTime) < min(backoff, maxRefreshBackoff)
+}
+
+func (r *stringBasedResultTimeBasedCacher[T]) Do(ctx context.Context, key string) (T, error) {
+	r.lock.Lock()
+	entry := r.entry(key)
+	entry.lastRequest = r.clock.Now()
+	if entry.hasValue {
+		value := entry.value
+		if r.clock.Since(entry.lastRefresh) < r.policy.TTL {
+			r.lock.Unlock()
+			cacheRequests.WithLabelValues(r.name, "hit").Inc()
+			return value, nil
+		}
+		// the request isn't held up.
+		if entry.inflight == nil && !r.backingOff(entry) {
+			r.startRefresh(ctx, key, entry)
+		}
+		r.lock.Unlock()
+		cacheRequests.WithLabelValues(r.name, "stale").Inc()
+		return value, nil
+	}
+
+	cacheRequests.WithLabelValues(r.name, "miss").Inc()
+	inflight := entry.inflight
+	if inflight == nil {
+		if r.backingOff(entry) {
+			err := entry.lastError
+			r.lock.Unlock()
+			var zero T
+			return zero, err
+		}
+		inflight = r.startRefresh(ctx, key, entry)
+	}
+	r.lock.Unlock()
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case <-inflight:
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !entry.hasValue {
+		var zero T
+		return zero, entry.lastError
+	}
+	return entry.value, nil
+}
+
+// startRefresh must be called with the lock held.  The refresh is detached from ctx's cancellation, so a request
+// that gives up doesn't cancel a computation other requests are waiting for.
+func (r *stringBasedResultTimeBasedCacher[T]) startRefresh(ctx context.Context, key string, entry *cacheEntry[T]) chan struct{} {
+	ctx = context.WithoutCancel(ctx)
+	inflight := make(chan struct{})
+	entry.inflight = inflight
+	go func() {
+		defer close(inflight)
+		r.refresh(ctx, key, entry)
+	}()
+	return inflight
+}
+
+func (r *stringBasedResultTimeBasedCacher[T]) refresh(ctx context.Context, key string, entry *cacheEntry[T]) {
+	logger := klog.FromContext(ctx)
+
+	curr, err := r.callDelegate(ctx, key)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry.inflight = nil
+	if err != nil {
+		// errors aren't cached, the last good value keeps being served.
+		logger.Error(err, "failed to refresh cache", "cache", r.name, "key", key)
+		entry.lastError = err
+		entry.lastErrorTime = r.clock.Now()
+		entry.consecutiveFailures++
+		return
+	}
+	entry.hasValue = true
+	entry.value = curr
+	entry.lastRefresh = r.clock.Now()
+	entry.lastError = nil
+	entry.lastErrorTime = time.Time{}
+	entry.consecutiveFailures = 0
+}
+
+// callDelegate turns a panic into an error.  The delegate runs detached from the request, outside gin's recovery, so
+// a panic would otherwise end the process.
+func (r *stringBasedResultTimeBasedCacher[T]) callDelegate(ctx context.Context, key string) (ret T, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			klog.FromContext(ctx).Error(nil, "panic refreshing cache", "cache", r.name, "key", key, "panic", recovered, "stack", string(debug.Stack()))
+			var zero T
+			ret = zero
+			err = status.NewInternalError(fmt.Errorf("panic refreshing %s cache for key %q: %v", r.name, key, recovered))
+		}
+	}()
+	return r.delegate(ctx, key)
+}
+
+// run drops idle keys and recomputes keys older than the refresh interval until ctx is done.  Keys are refreshed one
+// at a time so a cache with many keys doesn't start every scan at once.
+func (r *stringBasedResultTimeBasedCacher[T]) run(ctx context.Context) {
+	checkInterval := time.Minute
+	if r.policy.RefreshInterval > 0 {
+		checkInterval = min(r.policy.RefreshInterval/10, checkInterval)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.clock.After(checkInterval):
+		}
+
+		for _, key := range r.evictIdleAndListDue() {
+			r.lock.Lock()
+			entry, ok := r.entries[key]
+			if !ok || entry.inflight != nil {
+				r.lock.Unlock()
+				continue
+			}
+			inflight := r.startRefresh(ctx, key, entry)
+			r.lock.Unlock()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-inflight:
+			}
+		}
+	}
+}
+
+// evictIdleAndListDue drops the keys nobody requested within idleCacheEntryTimeout and returns the keys due for a
+// background refresh.
+func (r *stringBasedResultTimeBasedCacher[T]) evictIdleAndListDue() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	dueKeys := []string{}
+	for key, entry := range r.entries {
+		if entry.inflight != nil {
+			continue
+		}
+		if r.clock.Since(entry.lastRequest) >= idleCacheEntryTimeout {
+			delete(r.entries, key)
+			continue
+		}
+		if r.policy.RefreshInterval > 0 && entry.hasValue && !r.backingOff(entry) && r.clock.Since(entry.lastRefresh) >= r.policy.RefreshInterval {
+			dueKeys = append(dueKeys, key)
+		}
+	}
+	return dueKeys
+}
+
+func (r *stringBasedResultTimeBasedCacher[T]) cacheStatus() status.Cache {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := status.Cache{
+		TypeMeta: status.TypeMeta{
+			Kind:       "Cache",
+			APIVersion: "service-status.hcm.openshift.io/v1",
+		},
+		Name:    r.name,
+		TTL:     r.policy.TTL.String(),
+		Entries: []status.CacheEntry{},
+	}
+	if r.policy.RefreshInterval > 0 {
+		ret.RefreshInterval = r.policy.RefreshInterval.String()
+	}
+	for key, entry := range r.entries {
+		cacheEntry := status.CacheEntry{
+			Key:        key,
+			Refreshing: entry.inflight != nil,
+			Stale:      !entry.hasValue || r.clock.Since(entry.lastRefresh) >= r.policy.TTL,
+		}
+		if entry.hasValue {
+			cacheEntry.LastRefreshTime = ptr.To(entry.lastRefresh)
+			cacheEntry.Age = r.clock.Since(entry.lastRefresh).Round(time.Second).String()
+		}
+		if entry.lastError != nil {
+			cacheEntry.LastError = entry.lastError.Error()
+			cacheEntry.LastErrorTime = ptr.To(entry.lastErrorTime)
+		}
+		ret.Entries = append(ret.Entries, cacheEntry)
+	}
+	sort.Slice(ret.Entries, func(i, j int) bool {
+		return ret.Entries[i].Key < ret.Entries[j].Key
+	})
+	return ret
+}
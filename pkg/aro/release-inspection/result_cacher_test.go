@@ -0,0 +1,185 @@
+package release_inspection
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestStringBasedResultTimeBasedCacherServesStaleValues(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	calls := 0
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	cacher := &stringBasedResultTimeBasedCacher[int]{
+		name: "test",
+		delegate: func(ctx context.Context, key string) (int, error) {
+			calls++
+			if calls == 1 {
+				return calls, nil
+			}
+			<-release
+			if calls == 3 {
+				return 0, fmt.Errorf("scan failed")
+			}
+			return calls, nil
+		},
+		policy: CachePolicy{TTL: time.Hour},
+		clock:  fakeClock,
+	}
+
+	actual, err := cacher.Do(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, 1, actual)
+
+	// a stale value is served while the recompute is blocked.
+	fakeClock.Step(2 * time.Hour)
+	actual, err = cacher.Do(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, 1, actual)
+	assert.True(t, cacher.cacheStatus().Entries[0].Refreshing)
+	release <- struct{}{}
+	require.Eventually(t, func() bool { return !cacher.cacheStatus().Entries[0].Refreshing }, time.Second, time.Millisecond)
+	actual, err = cacher.Do(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, 2, actual)
+
+	// a failed recompute keeps the last good value and reports the error.
+	fakeClock.Step(2 * time.Hour)
+	_, err = cacher.Do(ctx, "key")
+	require.NoError(t, err)
+	release <- struct{}{}
+	require.Eventually(t, func() bool { return !cacher.cacheStatus().Entries[0].Refreshing }, time.Second, time.Millisecond)
+	actual, err = cacher.Do(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, 2, actual)
+	cacheStatus := cacher.cacheStatus()
+	assert.Equal(t, "scan failed", cacheStatus.Entries[0].LastError)
+	assert.True(t, cacheStatus.Entries[0].Stale)
+	assert.False(t, cacheStatus.Entries[0].Refreshing, "a failed refresh is not retried before the backoff")
+
+	// after the backoff the next request retries.
+	fakeClock.Step(initialRefreshBackoff)
+	_, err = cacher.Do(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, cacher.cacheStatus().Entries[0].Refreshing)
+	release <- struct{}{}
+	require.Eventually(t, func() bool { return !cacher.cacheStatus().Entries[0].Refreshing }, time.Second, time.Millisecond)
+	actual, err = cacher.Do(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, 4, actual)
+}
+
+func TestStringBasedResultTimeBasedCacherBacksOffFailedMisses(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	calls := 0
+	cacher := &stringBasedResultTimeBasedCacher[int]{
+		name: "test",
+		delegate: func(ctx context.Context, key string) (int, error) {
+			calls++
+			return 0, fmt.Errorf("scan failed")
+		},
+		policy: CachePolicy{TTL: time.Hour},
+		clock:  fakeClock,
+	}
+
+	_, err := cacher.Do(ctx, "key")
+	require.EqualError(t, err, "scan failed")
+	_, err = cacher.Do(ctx, "key")
+	require.EqualError(t, err, "scan failed")
+	assert.Equal(t, 1, calls, "the last error is returned during the backoff")
+
+	fakeClock.Step(initialRefreshBackoff)
+	_, err = cacher.Do(ctx, "key")
+	require.Error(t, err)
+	assert.Equal(t, 2, calls)
+
+	// the backoff doubles with every consecutive failure.
+	fakeClock.Step(initialRefreshBackoff)
+	_, err = cacher.Do(ctx, "key")
+	require.Error(t, err)
+	assert.Equal(t, 2, calls)
+	fakeClock.Step(initialRefreshBackoff)
+	_, err = cacher.Do(ctx, "key")
+	require.Error(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestStringBasedResultTimeBasedCacherRecoversPanics(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	cacher := &stringBasedResultTimeBasedCacher[int]{
+		name: "test",
+		delegate: func(ctx context.Context, key string) (int, error) {
+			panic("bad key")
+		},
+		policy: CachePolicy{TTL: time.Hour},
+		clock:  fakeClock,
+	}
+
+	// the waiting request gets the panic as an error, and the process keeps running.
+	_, err := cacher.Do(ctx, "key")
+	require.Error(t, err)
+	assert.Equal(t, status.StatusReasonInternalError, status.ReasonForError(err))
+	assert.Contains(t, err.Error(), "bad key")
+	cacheStatus := cacher.cacheStatus()
+	assert.Contains(t, cacheStatus.Entries[0].LastError, "bad key")
+	assert.False(t, cacheStatus.Entries[0].Refreshing)
+}
+
+func TestStringBasedResultTimeBasedCacherDetachesRefreshFromRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	cacher := &stringBasedResultTimeBasedCacher[int]{
+		name: "test",
+		delegate: func(ctx context.Context, key string) (int, error) {
+			<-release
+			return 1, ctx.Err()
+		},
+		policy: CachePolicy{TTL: time.Hour},
+		clock:  clocktesting.NewFakeClock(time.Now()),
+	}
+
+	cancel()
+	_, err := cacher.Do(ctx, "key")
+	require.ErrorIs(t, err, context.Canceled)
+	close(release)
+
+	// the computation the cancelled request started still fills the cache.
+	require.Eventually(t, func() bool { return !cacher.cacheStatus().Entries[0].Refreshing }, time.Second, time.Millisecond)
+	actual, err := cacher.Do(context.Background(), "key")
+	require.NoError(t, err)
+	assert.Equal(t, 1, actual)
+}
+
+func TestStringBasedResultTimeBasedCacherEvictsIdleKeys(t *testing.T) {
+	ctx := context.Background()
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	cacher := &stringBasedResultTimeBasedCacher[int]{
+		name: "test",
+		delegate: func(ctx context.Context, key string) (int, error) {
+			return 1, nil
+		},
+		policy: CachePolicy{TTL: time.Hour, RefreshInterval: time.Hour},
+		clock:  fakeClock,
+	}
+
+	_, err := cacher.Do(ctx, "idle")
+	require.NoError(t, err)
+	fakeClock.Step(idleCacheEntryTimeout - time.Hour)
+	_, err = cacher.Do(ctx, "requested")
+	require.NoError(t, err)
+	fakeClock.Step(time.Hour)
+
+	assert.Equal(t, []string{"requested"}, cacher.evictIdleAndListDue())
+	require.Len(t, cacher.cacheStatus().Entries, 1)
+	assert.Equal(t, "requested", cacher.cacheStatus().Entries[0].Key)
+}
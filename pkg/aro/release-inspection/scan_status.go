@@ -0,0 +1,97 @@
+package release_inspection
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+)
+
+// ScanStatusRecorder remembers how the latest scan of each environment went.
+type ScanStatusRecorder struct {
+	clock clock.Clock
+
+	lock                    sync.Mutex
+	environmentToScanStatus map[string]*status.EnvironmentScanStatus
+}
+
+func NewScanStatusRecorder(clock clock.Clock) *ScanStatusRecorder {
+	return &ScanStatusRecorder{
+		clock:                   clock,
+		environmentToScanStatus: map[string]*status.EnvironmentScanStatus{},
+	}
+}
+
+// environmentScan collects what happens during one scan and records it when finished.
+type environmentScan struct {
+	recorder        *ScanStatusRecorder
+	environmentName string
+	startTime       time.Time
+
+	aroHCPHead               string
+	degradedReleases         int
+	lastDegradedReleaseError string
+}
+
+func (r *ScanStatusRecorder) startScan(environmentName string) *environmentScan {
+	return &environmentScan{
+		recorder:        r,
+		environmentName: environmentName,
+		startTime:       r.clock.Now(),
+	}
+}
+
+func (s *environmentScan) releaseDegraded(releaseName string, err error) {
+	s.degradedReleases++
+	s.lastDegradedReleaseError = releaseName + ": " + err.Error()
+}
+
+func (s *environmentScan) finish(err error) {
+	r := s.recorder
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	scanStatus, ok := r.environmentToScanStatus[s.environmentName]
+	if !ok {
+		scanStatus = &status.EnvironmentScanStatus{Environment: s.environmentName}
+		r.environmentToScanStatus[s.environmentName] = scanStatus
+	}
+	scanStatus.LastScanStartTime = ptr.To(s.startTime)
+	scanStatus.LastScanDuration = r.clock.Since(s.startTime).Round(time.Millisecond).String()
+	if err != nil {
+		scanStatus.LastScanError = err.Error()
+		return
+	}
+	scanStatus.LastScanError = ""
+	scanStatus.ScansCompleted++
+	scanStatus.AROHCPHead = s.aroHCPHead
+	scanStatus.DegradedReleases = s.degradedReleases
+	scanStatus.LastDegradedReleaseError = s.lastDegradedReleaseError
+}
+
+// ListScanStatuses returns a copy of every environment's scan status, sorted by environment.
+func (r *ScanStatusRecorder) ListScanStatuses() []status.EnvironmentScanStatus {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := []status.EnvironmentScanStatus{}
+	for _, scanStatus := range r.environmentToScanStatus {
+		ret = append(ret, *scanStatus)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Environment < ret[j].Environment
+	})
+	return ret
+}
+
+// HasCompletedScan is true once a scan of environmentName has succeeded.
+func (r *ScanStatusRecorder) HasCompletedScan(environmentName string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	scanStatus, ok := r.environmentToScanStatus[environmentName]
+	return ok && scanStatus.ScansCompleted > 0
+}
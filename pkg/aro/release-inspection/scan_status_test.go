@@ -0,0 +1,36 @@
+package release_inspection
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestScanStatusRecorder(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	recorder := NewScanStatusRecorder(fakeClock)
+	assert.False(t, recorder.HasCompletedScan("int"))
+
+	scan := recorder.startScan("int")
+	scan.aroHCPHead = "abc"
+	scan.releaseDegraded("int---one", errors.New("no image"))
+	fakeClock.Step(2 * time.Second)
+	scan.finish(nil)
+	assert.True(t, recorder.HasCompletedScan("int"))
+
+	// a failed scan keeps what the last good one found.
+	scan = recorder.startScan("int")
+	scan.finish(errors.New("repo missing"))
+
+	scanStatuses := recorder.ListScanStatuses()
+	assert.Len(t, scanStatuses, 1)
+	assert.Equal(t, "abc", scanStatuses[0].AROHCPHead)
+	assert.Equal(t, 1, scanStatuses[0].ScansCompleted)
+	assert.Equal(t, 1, scanStatuses[0].DegradedReleases)
+	assert.Equal(t, "int---one: no image", scanStatuses[0].LastDegradedReleaseError)
+	assert.Equal(t, "repo missing", scanStatuses[0].LastScanError)
+	assert.True(t, recorder.HasCompletedScan("int"))
+}
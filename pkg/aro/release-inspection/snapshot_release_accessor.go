@@ -0,0 +1,66 @@
+package release_inspection
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+)
+
+// snapshotReleaseAccessor serves a file-based API, like the one written by export, to the JSON routes.  Nothing is
+// computed, so a snapshot is served exactly as it was recorded.
+type snapshotReleaseAccessor struct {
+	releaseClient client.ReleaseClient
+}
+
+func NewSnapshotReleaseAccessor(snapshotFS fs.FS) ReleaseAccessor {
+	return &snapshotReleaseAccessor{
+		releaseClient: client.NewFileSystemReleaseClient(snapshotFS),
+	}
+}
+
+func (a *snapshotReleaseAccessor) ListEnvironments(ctx context.Context) ([]string, error) {
+	environments, err := a.releaseClient.ListEnvironments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	for _, environment := range environments.Items {
+		ret = append(ret, environment.Name)
+	}
+	return ret, nil
+}
+
+func (a *snapshotReleaseAccessor) ListEnvironmentReleases(ctx context.Context) (*status.EnvironmentReleaseList, error) {
+	return a.releaseClient.ListEnvironmentReleases(ctx, status.ListOptions{})
+}
+
+func (a *snapshotReleaseAccessor) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error) {
+	return a.releaseClient.ListEnvironmentReleasesForEnvironment(ctx, environment, status.ListOptions{})
+}
+
+func (a *snapshotReleaseAccessor) GetEnvironmentRelease(ctx context.Context, environmentReleaseName string) (*status.EnvironmentRelease, error) {
+	environmentName, releaseName, ok := status.SplitEnvironmentReleaseName(environmentReleaseName)
+	if !ok {
+		return nil, status.NewBadRequest(fmt.Sprintf("%q must be in format <environmentName>---<releaseName>", environmentReleaseName))
+	}
+	return a.releaseClient.GetEnvironmentRelease(ctx, environmentName, releaseName)
+}
+
+// GetReleaseEnvironmentDiff can only page through the changes the snapshot recorded.
+func (a *snapshotReleaseAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName string, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+	return a.releaseClient.GetEnvironmentReleaseDiff(ctx, environmentReleaseName, otherEnvironmentReleaseName, options)
+}
+
+func (a *snapshotReleaseAccessor) GetCICoverage(ctx context.Context, environmentName string) (*status.CICoverage, error) {
+	return a.releaseClient.GetCICoverage(ctx, environmentName)
+}
+
+func (a *snapshotReleaseAccessor) ListComponents(ctx context.Context) (*status.ComponentStatusList, error) {
+	return a.releaseClient.ListComponents(ctx)
+}
+
+// SetSelfLookupInstance does nothing, a snapshot has nothing to look up twice.
+func (a *snapshotReleaseAccessor) SetSelfLookupInstance(ReleaseAccessor) {}
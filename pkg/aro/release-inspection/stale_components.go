@@ -0,0 +1,44 @@
+package release_inspection
+
+import (
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"k8s.io/utils/set"
+)
+
+// StaleComponent is a component whose image is older than its LatencyThreshold.
+type StaleComponent struct {
+	Name              string
+	ImageCreationTime time.Time
+	Age               time.Duration
+	LatencyThreshold  time.Duration
+}
+
+// StaleComponents lists the components of environmentRelease, sorted by name, that should have been updated by now.
+// Components without an image creation time or a LatencyThreshold are never stale.
+func StaleComponents(now time.Time, environmentRelease *status.EnvironmentRelease) []StaleComponent {
+	ret := []StaleComponent{}
+	for _, componentName := range set.KeySet(environmentRelease.Components).SortedList() {
+		component := environmentRelease.Components[componentName]
+		if component.ImageCreationTime == nil {
+			continue
+		}
+		acceptableLatency := HardcodedComponents[component.Name].LatencyThreshold
+		if acceptableLatency == 0 {
+			continue
+		}
+
+		daysOld := now.Sub(*component.ImageCreationTime) / (24 * time.Hour)
+		roughWorkingDuration := now.Sub(*component.ImageCreationTime) - daysOld
+		if roughWorkingDuration > acceptableLatency {
+			ret = append(ret, StaleComponent{
+				Name:              component.Name,
+				ImageCreationTime: *component.ImageCreationTime,
+				Age:               roughWorkingDuration,
+				LatencyThreshold:  acceptableLatency,
+			})
+		}
+	}
+	return ret
+}
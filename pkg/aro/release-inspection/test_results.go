@@ -0,0 +1,82 @@
+package release_inspection
+
+import (
+	"sort"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+)
+
+// maxMostFailedTests bounds how many tests are listed in MostFailedTests.
+const maxMostFailedTests = 20
+
+// NewReleaseTestResults aggregates the test failures of the job runs attributed to environmentRelease.
+// previousEnvironmentRelease is the release before it in the same environment and may be nil.  When it has no job
+// runs every failure would look new, so NewlyFailingTests is left empty.
+func NewReleaseTestResults(environmentRelease, previousEnvironmentRelease *status.EnvironmentRelease) *status.ReleaseTestResults {
+	ret := &status.ReleaseTestResults{
+		MostFailedTests:   []status.TestResultSummary{},
+		NewlyFailingTests: []status.TestResultSummary{},
+	}
+
+	currTests := summarizeTests(environmentRelease)
+	failingTests := []status.TestResultSummary{}
+	for _, testSummary := range currTests {
+		if testSummary.Failures > 0 {
+			failingTests = append(failingTests, *testSummary)
+		}
+	}
+	sort.Slice(failingTests, func(i, j int) bool {
+		if failingTests[i].Failures != failingTests[j].Failures {
+			return failingTests[i].Failures > failingTests[j].Failures
+		}
+		return failingTests[i].Name < failingTests[j].Name
+	})
+	ret.MostFailedTests = failingTests[:min(len(failingTests), maxMostFailedTests)]
+
+	if previousEnvironmentRelease == nil || !hasJobRuns(previousEnvironmentRelease) {
+		return ret
+	}
+	ret.PreviousEnvironmentReleaseName = previousEnvironmentRelease.Name
+	previousTests := summarizeTests(previousEnvironmentRelease)
+	for _, testSummary := range failingTests {
+		if previous, ok := previousTests[testSummary.Name]; ok && previous.Failures > 0 {
+			continue
+		}
+		ret.NewlyFailingTests = append(ret.NewlyFailingTests, testSummary)
+	}
+	return ret
+}
+
+func summarizeTests(environmentRelease *status.EnvironmentRelease) map[string]*status.TestResultSummary {
+	ret := map[string]*status.TestResultSummary{}
+	getSummary := func(testName string) *status.TestResultSummary {
+		if _, ok := ret[testName]; !ok {
+			ret[testName] = &status.TestResultSummary{Name: testName}
+		}
+		return ret[testName]
+	}
+	for _, results := range []map[string][]status.JobRunResults{environmentRelease.BlockingJobRunResults, environmentRelease.InformingJobRunResults} {
+		for _, jobRuns := range results {
+			for _, jobRun := range jobRuns {
+				for _, testName := range jobRun.FailedTestNames {
+					getSummary(testName).Failures++
+				}
+				for _, testName := range jobRun.FlakedTestNames {
+					getSummary(testName).Flakes++
+				}
+			}
+		}
+	}
+	return ret
+}
+
+func hasJobRuns(environmentRelease *status.EnvironmentRelease) bool {
+	for _, results := range []map[string][]status.JobRunResults{environmentRelease.BlockingJobRunResults, environmentRelease.InformingJobRunResults} {
+		for _, jobRuns := range results {
+			if len(jobRuns) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package release_inspection
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReleaseTestResultsMostFailed(t *testing.T) {
+	environmentRelease := &status.EnvironmentRelease{
+		BlockingJobRunResults: map[string][]status.JobRunResults{
+			"bare-minimum": {
+				{FailedTestNames: []string{"b", "c"}, FlakedTestNames: []string{"a"}},
+				{FailedTestNames: []string{"c"}},
+			},
+		},
+		InformingJobRunResults: map[string][]status.JobRunResults{
+			"e2e-parallel": {
+				{FailedTestNames: []string{"a", "c"}, FlakedTestNames: []string{"flake-only"}},
+			},
+		},
+	}
+
+	actual := NewReleaseTestResults(environmentRelease, nil)
+	// ordered by failures and then by name, and tests that only flaked aren't listed.
+	assert.Equal(t, []status.TestResultSummary{
+		{Name: "c", Failures: 3},
+		{Name: "a", Failures: 1, Flakes: 1},
+		{Name: "b", Failures: 1},
+	}, actual.MostFailedTests)
+	assert.Empty(t, actual.NewlyFailingTests)
+	assert.Empty(t, actual.PreviousEnvironmentReleaseName)
+}
+
+func TestNewReleaseTestResultsMostFailedIsBounded(t *testing.T) {
+	jobRuns := []status.JobRunResults{}
+	for i := range maxMostFailedTests + 5 {
+		failedTestNames := []string{}
+		// test-00 fails once, test-01 twice, and so on, so the last ones are kept.
+		for j := range i + 1 {
+			failedTestNames = append(failedTestNames, fmt.Sprintf("test-%02d", j))
+		}
+		jobRuns = append(jobRuns, status.JobRunResults{FailedTestNames: failedTestNames})
+	}
+	environmentRelease := &status.EnvironmentRelease{BlockingJobRunResults: map[string][]status.JobRunResults{"bare-minimum": jobRuns}}
+
+	actual := NewReleaseTestResults(environmentRelease, nil)
+	assert.Len(t, actual.MostFailedTests, maxMostFailedTests)
+	assert.Equal(t, "test-00", actual.MostFailedTests[0].Name)
+	assert.Equal(t, maxMostFailedTests+5, actual.MostFailedTests[0].Failures)
+	assert.Equal(t, fmt.Sprintf("test-%02d", maxMostFailedTests-1), actual.MostFailedTests[maxMostFailedTests-1].Name)
+}
+
+func TestNewReleaseTestResultsNewlyFailing(t *testing.T) {
+	environmentRelease := &status.EnvironmentRelease{
+		BlockingJobRunResults: map[string][]status.JobRunResults{
+			"bare-minimum": {{FailedTestNames: []string{"still-failing", "used-to-flake", "new"}}},
+		},
+	}
+
+	tests := []struct {
+		name                       string
+		previousEnvironmentRelease *status.EnvironmentRelease
+		expectedPreviousName       string
+		expectedNewlyFailing       []string
+	}{
+		{
+			name:                 "no previous release",
+			expectedNewlyFailing: []string{},
+		},
+		{
+			name:                       "previous release without runs",
+			previousEnvironmentRelease: &status.EnvironmentRelease{Name: "int---previous", BlockingJobRunResults: map[string][]status.JobRunResults{"bare-minimum": {}}},
+			expectedNewlyFailing:       []string{},
+		},
+		{
+			name: "previous release with runs",
+			previousEnvironmentRelease: &status.EnvironmentRelease{
+				Name: "int---previous",
+				InformingJobRunResults: map[string][]status.JobRunResults{
+					"e2e-parallel": {{FailedTestNames: []string{"still-failing"}, FlakedTestNames: []string{"used-to-flake"}}},
+				},
+			},
+			expectedPreviousName: "int---previous",
+			expectedNewlyFailing: []string{"new", "used-to-flake"},
+		},
+		{
+			name: "previous release passed everything",
+			previousEnvironmentRelease: &status.EnvironmentRelease{
+				Name:                  "int---previous",
+				BlockingJobRunResults: map[string][]status.JobRunResults{"bare-minimum": {{OverallResult: status.JobSucceeded}}},
+			},
+			expectedPreviousName: "int---previous",
+			expectedNewlyFailing: []string{"new", "still-failing", "used-to-flake"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := NewReleaseTestResults(environmentRelease, tt.previousEnvironmentRelease)
+			assert.Equal(t, tt.expectedPreviousName, actual.PreviousEnvironmentReleaseName)
+			actualNames := []string{}
+			for _, testSummary := range actual.NewlyFailingTests {
+				actualNames = append(actualNames, testSummary.Name)
+			}
+			assert.Equal(t, tt.expectedNewlyFailing, actualNames)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package release_inspection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/aro/ciresults"
+)
+
+// TestedCommitResolver finds the ARO-HCP commit a CI job run actually exercised.
+type TestedCommitResolver interface {
+	// ResolveTestedCommit returns the full ARO-HCP SHA, or an empty string when the job run doesn't record one.
+	ResolveTestedCommit(ctx context.Context, jobRun ciresults.JobRun) (string, error)
+}
+
+const aroHCPRepoKey = "Azure/ARO-HCP"
+
+// maxConcurrentTestedCommitResolutions bounds the started.json requests of one scan.
+const maxConcurrentTestedCommitResolutions = 8
+
+type prowTestedCommitResolver struct {
+	artifactBaseURL string
+	client          *http.Client
+
+	lock           sync.Mutex
+	jobURLToCommit map[string]string
+}
+
+// NewProwTestedCommitResolver reads started.json next to the prow job artifacts.  Prow writes the commits it checked
+// out in repos, and jobs deploying ARO-HCP record the deployed commit as aro-hcp-commit in metadata.
+// artifactBaseURL is where the prow buckets are served, usually https://storage.googleapis.com.
+func NewProwTestedCommitResolver(artifactBaseURL string) TestedCommitResolver {
+	return &prowTestedCommitResolver{
+		artifactBaseURL: strings.TrimSuffix(artifactBaseURL, "/"),
+		client:          &http.Client{Timeout: 30 * time.Second},
+		jobURLToCommit:  map[string]string{},
+	}
+}
+
+// prowStarted is the subset of prow's started.json we use.
+type prowStarted struct {
+	Repos    map[string]string `json:"repos"`
+	Metadata map[string]any    `json:"metadata"`
+}
+
+func (r *prowTestedCommitResolver) ResolveTestedCommit(ctx context.Context, jobRun ciresults.JobRun) (string, error) {
+	r.lock.Lock()
+	cached, ok := r.jobURLToCommit[jobRun.URL]
+	r.lock.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	artifactPath, err := prowArtifactPath(jobRun.URL)
+	if err != nil {
+		return "", err
+	}
+	startedURL := r.artifactBaseURL + "/" + artifactPath + "/started.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, startedURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to do request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode > 299 || resp.StatusCode < 200 {
+		return "", fmt.Errorf("request %v failed: %v", startedURL, resp.StatusCode)
+	}
+
+	started := &prowStarted{}
+	if err := json.Unmarshal(body, started); err != nil {
+		return "", fmt.Errorf("failed to parse %v: %w", startedURL, err)
+	}
+	ret := testedCommitFromStarted(started)
+
+	// started.json never changes once written, so every answer can be kept.
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.jobURLToCommit[jobRun.URL] = ret
+	return ret, nil
+}
+
+func testedCommitFromStarted(started *prowStarted) string {
+	if commit, ok := started.Metadata["aro-hcp-commit"].(string); ok && len(commit) > 0 {
+		return commit
+	}
+	// repos values look like "main:<base sha>" for periodics and "main:<base sha>,1234:<pr sha>" for presubmits.
+	// Presubmits test unmerged code, which no release contains.
+	refs, ok := started.Repos[aroHCPRepoKey]
+	if !ok || strings.Contains(refs, ",") {
+		return ""
+	}
+	_, baseSHA, _ := strings.Cut(refs, ":")
+	return baseSHA
+}
+
+// prowArtifactPath turns https://prow.ci.openshift.org/view/gs/<bucket>/logs/<job>/<id> into <bucket>/logs/<job>/<id>.
+func prowArtifactPath(jobURL string) (string, error) {
+	parsedURL, err := url.Parse(jobURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse job URL: %w", err)
+	}
+	_, artifactPath, ok := strings.Cut(parsedURL.Path, "/view/gs/")
+	if !ok || len(artifactPath) == 0 {
+		return "", fmt.Errorf("job URL %q is not a prow job URL", jobURL)
+	}
+	return strings.TrimSuffix(artifactPath, "/"), nil
+}
+
+type dummyTestedCommitResolver struct{}
+
+// NewDummyTestedCommitResolver never knows the tested commit, so every job run falls back to wall-clock attribution.
+func NewDummyTestedCommitResolver() TestedCommitResolver {
+	return &dummyTestedCommitResolver{}
+}
+
+func (r *dummyTestedCommitResolver) ResolveTestedCommit(ctx context.Context, jobRun ciresults.JobRun) (string, error) {
+	return "", nil
+}
@@ -0,0 +1,111 @@
+package release_inspection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/ciresults"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseIndexForTestedCommit(t *testing.T) {
+	repo := newTestGitRepository(t)
+	beforeReleases := repo.commit("before every release")
+	release1 := repo.commit("release 1", beforeReleases)
+	release2 := repo.commit("release 2", release1)
+	// branched from release 1 after release 2 was cut, so it is newer than release 2 without containing it.
+	branchedFromRelease1 := repo.commit("pull request", release1)
+	merged := repo.commit("Merge pull request #1", release2, branchedFromRelease1)
+
+	accessor := &releaseAccessor{aroHCPRepository: NewAROHCPRepository(repo.dir), aroHCPDir: repo.dir}
+	releasesNewestToOldest := []status.EnvironmentRelease{
+		{Name: "int---2", SHA: release2.String()},
+		{Name: "int---1", SHA: release1.String()},
+	}
+
+	tests := []struct {
+		name      string
+		testedSHA string
+		expected  int
+	}{
+		{name: "release commit", testedSHA: release1.String(), expected: 1},
+		{name: "branched before the newer release", testedSHA: branchedFromRelease1.String(), expected: 1},
+		{name: "merged after the newer release", testedSHA: merged.String(), expected: 0},
+		{name: "no release is an ancestor", testedSHA: beforeReleases.String(), expected: -1},
+		{name: "unknown commit", testedSHA: "0123456789012345678901234567890123456789", expected: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, accessor.releaseIndexForTestedCommit(context.Background(), releasesNewestToOldest, tt.testedSHA))
+		})
+	}
+}
+
+type fakeTestedCommitResolver struct{}
+
+func (fakeTestedCommitResolver) ResolveTestedCommit(ctx context.Context, jobRun ciresults.JobRun) (string, error) {
+	if jobRun.URL == "failing" {
+		return "", errors.New("started.json not found")
+	}
+	return "sha-of-" + jobRun.URL, nil
+}
+
+func TestResolveTestedCommits(t *testing.T) {
+	accessor := &releaseAccessor{testedCommitResolver: fakeTestedCommitResolver{}}
+	jobRuns := []ciresults.JobRun{{URL: "failing"}, {URL: "recorded", TestedSHA: "abc"}}
+	for _, url := range []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"} {
+		jobRuns = append(jobRuns, ciresults.JobRun{URL: url})
+	}
+
+	jobRunURLToTestedSHA := accessor.resolveTestedCommits(context.Background(), jobRuns)
+	assert.Len(t, jobRunURLToTestedSHA, 10)
+	assert.Equal(t, "sha-of-10", jobRunURLToTestedSHA["10"])
+	assert.NotContains(t, jobRunURLToTestedSHA, "failing")
+	assert.NotContains(t, jobRunURLToTestedSHA, "recorded", "job runs recording their commit aren't resolved")
+}
+
+func TestTestedCommitFromStarted(t *testing.T) {
+	tests := []struct {
+		name     string
+		started  prowStarted
+		expected string
+	}{
+		{
+			name:     "deployed commit in metadata",
+			started:  prowStarted{Metadata: map[string]any{"aro-hcp-commit": "deployed"}, Repos: map[string]string{aroHCPRepoKey: "main:base"}},
+			expected: "deployed",
+		},
+		{
+			name:     "periodic",
+			started:  prowStarted{Repos: map[string]string{aroHCPRepoKey: "main:base"}},
+			expected: "base",
+		},
+		{
+			name:     "presubmit",
+			started:  prowStarted{Repos: map[string]string{aroHCPRepoKey: "main:base,1234:pr"}},
+			expected: "",
+		},
+		{
+			name:     "other repo",
+			started:  prowStarted{Repos: map[string]string{"openshift/release": "main:base"}},
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, testedCommitFromStarted(&tt.started))
+		})
+	}
+}
+
+func TestProwArtifactPath(t *testing.T) {
+	artifactPath, err := prowArtifactPath("https://prow.ci.openshift.org/view/gs/test-platform-results/logs/periodic-e2e/123/")
+	require.NoError(t, err)
+	assert.Equal(t, "test-platform-results/logs/periodic-e2e/123", artifactPath)
+
+	_, err = prowArtifactPath("https://example.com/job/123")
+	assert.Error(t, err)
+}
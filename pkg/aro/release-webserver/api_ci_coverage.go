@@ -0,0 +1,27 @@
+package release_webserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"k8s.io/klog/v2"
+)
+
+func GetCICoverage(accessor release_inspection.ReleaseAccessor) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		logger := klog.LoggerWithValues(klog.FromContext(ctx), "URL", c.Request.URL)
+		ctx = klog.NewContext(ctx, logger)
+
+		environmentName := c.Param("name")
+		ret, err := accessor.GetCICoverage(ctx, environmentName)
+		if err != nil {
+			writeError(c, fmt.Errorf("failed to get CI coverage for %q: %w", environmentName, err))
+			return
+		}
+
+		c.IndentedJSON(http.StatusOK, ret)
+	}
+}
@@ -0,0 +1,57 @@
+package release_webserver
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"k8s.io/klog/v2"
+)
+
+func ListComponents(accessor release_inspection.ReleaseAccessor) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		logger := klog.LoggerWithValues(klog.FromContext(ctx), "URL", c.Request.URL)
+		ctx = klog.NewContext(ctx, logger)
+
+		components, err := accessor.ListComponents(ctx)
+		if err != nil {
+			writeError(c, fmt.Errorf("failed to list components: %w", err))
+			return
+		}
+
+		// the list may be shared with a cache.
+		now := time.Now()
+		ret := *components
+		ret.Items = []status.ComponentStatus{}
+		for _, component := range components.Items {
+			ret.Items = append(ret.Items, release_inspection.WithImageAges(now, component))
+		}
+		c.IndentedJSON(http.StatusOK, ret)
+	}
+}
+
+func GetComponent(accessor release_inspection.ReleaseAccessor) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		logger := klog.LoggerWithValues(klog.FromContext(ctx), "URL", c.Request.URL)
+		ctx = klog.NewContext(ctx, logger)
+
+		componentName := c.Param("name")
+		components, err := accessor.ListComponents(ctx)
+		if err != nil {
+			writeError(c, fmt.Errorf("failed to list components: %w", err))
+			return
+		}
+		for _, component := range components.Items {
+			if component.Name == componentName {
+				c.IndentedJSON(http.StatusOK, release_inspection.WithImageAges(time.Now(), component))
+				return
+			}
+		}
+		writeError(c, status.NewNotFound("Component", componentName))
+	}
+}
@@ -18,7 +18,7 @@ func ListEnvironments(accessor release_inspection.ReleaseAccessor) func(c *gin.C
 
 		environments, err := accessor.ListEnvironments(ctx)
 		if err != nil {
-			c.String(500, "failed to list environments: %v", err)
+			writeError(c, fmt.Errorf("failed to list environments: %w", err))
 			return
 		}
 
@@ -51,7 +51,7 @@ func GetEnvironment(accessor release_inspection.ReleaseAccessor) func(c *gin.Con
 
 		environments, err := accessor.ListEnvironments(ctx)
 		if err != nil {
-			c.String(500, "failed to list environments: %v", err)
+			writeError(c, fmt.Errorf("failed to list environments: %w", err))
 			return
 		}
 
@@ -70,6 +70,6 @@ func GetEnvironment(accessor release_inspection.ReleaseAccessor) func(c *gin.Con
 			}
 		}
 
-		c.IndentedJSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("%q not found", name)})
+		writeError(c, status.NewNotFound("Environment", name))
 	}
 }
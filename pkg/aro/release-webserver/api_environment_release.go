@@ -2,9 +2,9 @@ package release_webserver
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/openshift-online/service-status/pkg/apis/status"
@@ -12,40 +12,99 @@ import (
 	"k8s.io/klog/v2"
 )
 
-func ListEnvironmentReleases(accessor release_inspection.ReleaseAccessor) func(c *gin.Context) {
+func ListEnvironmentReleases(accessor release_inspection.ReleaseAccessor, watcher *release_inspection.EnvironmentReleaseWatcher) func(c *gin.Context) {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 		logger := klog.LoggerWithValues(klog.FromContext(ctx), "URL", c.Request.URL)
 		ctx = klog.NewContext(ctx, logger)
 
-		ret, err := accessor.ListEnvironmentReleases(ctx)
+		if c.Query("watch") == "true" {
+			serveEnvironmentReleaseWatch(c, watcher, "")
+			return
+		}
+
+		options, err := status.ParseListOptions(c.Request.URL.Query())
 		if err != nil {
-			c.String(500, "failed to list releases: %v", err)
+			writeError(c, err)
 			return
 		}
 
-		c.IndentedJSON(http.StatusOK, ret)
+		// read before listing so a watch from it can only repeat changes, never miss them.
+		resourceVersion := watcher.ResourceVersion()
+		environmentReleases, err := accessor.ListEnvironmentReleases(ctx)
+		if err != nil {
+			writeError(c, fmt.Errorf("failed to list releases: %w", err))
+			return
+		}
+
+		writeEnvironmentReleaseList(c, environmentReleases, resourceVersion, options)
 	}
 }
 
-func ListEnvironmentReleasesForEnvironment(accessor release_inspection.ReleaseAccessor) func(c *gin.Context) {
+func ListEnvironmentReleasesForEnvironment(accessor release_inspection.ReleaseAccessor, watcher *release_inspection.EnvironmentReleaseWatcher) func(c *gin.Context) {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
 		logger := klog.LoggerWithValues(klog.FromContext(ctx), "URL", c.Request.URL)
 		ctx = klog.NewContext(ctx, logger)
 
 		environmentName := c.Param("name")
+		if c.Query("watch") == "true" {
+			serveEnvironmentReleaseWatch(c, watcher, environmentName)
+			return
+		}
+
+		options, err := status.ParseListOptions(c.Request.URL.Query())
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		options.Environment = environmentName
 
+		resourceVersion := watcher.ResourceVersion()
 		environmentReleases, err := accessor.ListEnvironmentReleasesForEnvironment(ctx, environmentName)
 		if err != nil {
-			c.String(500, "failed to list releases: %v", err)
+			writeError(c, fmt.Errorf("failed to list releases: %w", err))
 			return
 		}
 
-		c.IndentedJSON(http.StatusOK, environmentReleases)
+		writeEnvironmentReleaseList(c, environmentReleases, resourceVersion, options)
 	}
 }
 
+// projectedEnvironmentReleaseList is an EnvironmentReleaseList with only the requested fields of each release.
+type projectedEnvironmentReleaseList struct {
+	status.TypeMeta `json:",inline"`
+	ResourceVersion string                       `json:"resourceVersion,omitempty"`
+	Continue        string                       `json:"continue,omitempty"`
+	Items           []map[string]json.RawMessage `json:"items"`
+}
+
+func writeEnvironmentReleaseList(c *gin.Context, environmentReleases *status.EnvironmentReleaseList, resourceVersion string, options status.ListOptions) {
+	// the list may be shared with a cache, filtering copies it.
+	ret, err := status.FilterEnvironmentReleaseList(environmentReleases, options)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	ret.ResourceVersion = resourceVersion
+
+	if len(options.Fields) == 0 {
+		c.IndentedJSON(http.StatusOK, ret)
+		return
+	}
+	projectedItems, err := status.ProjectEnvironmentReleases(ret.Items, options.Fields)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, projectedEnvironmentReleaseList{
+		TypeMeta:        ret.TypeMeta,
+		ResourceVersion: ret.ResourceVersion,
+		Continue:        ret.Continue,
+		Items:           projectedItems,
+	})
+}
+
 func GetEnvironmentRelease(accessor release_inspection.ReleaseAccessor) func(c *gin.Context) {
 	return func(c *gin.Context) {
 		ctx := c.Request.Context()
@@ -54,12 +113,8 @@ func GetEnvironmentRelease(accessor release_inspection.ReleaseAccessor) func(c *
 
 		environmentReleaseName := c.Param("name")
 		ret, err := getEnvironmentRelease(ctx, accessor, environmentReleaseName)
-		if err != nil && strings.Contains(err.Error(), "not found") {
-			c.String(http.StatusNotFound, "not found")
-			return
-		}
 		if err != nil {
-			c.String(http.StatusInternalServerError, "failed to get release info: %v", err)
+			writeError(c, fmt.Errorf("failed to get release info: %w", err))
 			return
 		}
 
@@ -73,7 +128,7 @@ func getEnvironmentRelease(ctx context.Context, accessor release_inspection.Rele
 		return nil, fmt.Errorf("failed to get release environment info: %w", err)
 	}
 	if currReleaseEnvironmentInfo == nil {
-		return nil, fmt.Errorf("%q not found", environmentReleaseName)
+		return nil, status.NewNotFound("EnvironmentRelease", environmentReleaseName)
 	}
 
 	return currReleaseEnvironmentInfo, nil
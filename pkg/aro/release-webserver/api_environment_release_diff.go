@@ -1,9 +1,11 @@
 package release_webserver
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
 	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
 	"k8s.io/klog/v2"
 )
@@ -16,10 +18,22 @@ func GetEnvironmentReleaseDiff(accessor release_inspection.ReleaseAccessor) func
 
 		environmentReleaseName := c.Param("name")
 		otherEnvironmentReleaseName := c.Param("otherName")
+		for _, name := range []string{environmentReleaseName, otherEnvironmentReleaseName} {
+			if _, _, ok := status.SplitEnvironmentReleaseName(name); !ok {
+				writeError(c, status.NewBadRequest(fmt.Sprintf("%q is not an environment release name", name)))
+				return
+			}
+		}
+
+		options, err := status.ParseDiffOptions(c.Request.URL.Query())
+		if err != nil {
+			writeError(c, err)
+			return
+		}
 
-		ret, err := accessor.GetReleaseEnvironmentDiff(ctx, environmentReleaseName, otherEnvironmentReleaseName)
+		ret, err := accessor.GetReleaseEnvironmentDiff(ctx, environmentReleaseName, otherEnvironmentReleaseName, options)
 		if err != nil {
-			c.String(http.StatusInternalServerError, "failed to get release environment diff for name=%q to other=%q: %v", environmentReleaseName, otherEnvironmentReleaseName, err)
+			writeError(c, fmt.Errorf("failed to get release environment diff for name=%q to other=%q: %w", environmentReleaseName, otherEnvironmentReleaseName, err))
 			return
 		}
 
@@ -0,0 +1,62 @@
+package release_webserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"k8s.io/klog/v2"
+)
+
+func GetEnvironmentReleaseNotes(accessor release_inspection.ReleaseAccessor) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		logger := klog.LoggerWithValues(klog.FromContext(ctx), "URL", c.Request.URL)
+		ctx = klog.NewContext(ctx, logger)
+
+		environmentReleaseName := c.Param("name")
+		otherEnvironmentReleaseName := c.Param("otherName")
+		format := c.DefaultQuery("format", "markdown")
+		// the diff may take a full scan, so a request that can't be rendered fails first.
+		if err := release_inspection.ValidateReleaseNotesFormat(format); err != nil {
+			writeError(c, status.NewBadRequest(err.Error()))
+			return
+		}
+
+		environmentRelease, err := getEnvironmentRelease(ctx, accessor, environmentReleaseName)
+		if err != nil {
+			writeError(c, fmt.Errorf("failed to get release info: %w", err))
+			return
+		}
+		otherEnvironmentRelease, err := getEnvironmentRelease(ctx, accessor, otherEnvironmentReleaseName)
+		if err != nil {
+			writeError(c, fmt.Errorf("failed to get release info: %w", err))
+			return
+		}
+		// the notes go into change tickets, so every page of changes is listed.
+		diff, err := status.GetAllEnvironmentReleaseDiffPages(ctx, func(ctx context.Context, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+			return accessor.GetReleaseEnvironmentDiff(ctx, environmentReleaseName, otherEnvironmentReleaseName, options)
+		})
+		if err != nil {
+			writeError(c, fmt.Errorf("failed to get release environment diff for name=%q to other=%q: %w", environmentReleaseName, otherEnvironmentReleaseName, err))
+			return
+		}
+
+		releaseNotes := release_inspection.NewReleaseNotes(otherEnvironmentRelease, environmentRelease, diff)
+		releaseNotesBytes := &bytes.Buffer{}
+		if err := release_inspection.WriteReleaseNotes(releaseNotesBytes, format, releaseNotes); err != nil {
+			writeError(c, fmt.Errorf("failed to write release notes: %w", err))
+			return
+		}
+
+		contentType := "text/plain; charset=utf-8"
+		if format == "markdown" || format == "md" {
+			contentType = "text/markdown; charset=utf-8"
+		}
+		c.Data(http.StatusOK, contentType, releaseNotesBytes.Bytes())
+	}
+}
@@ -0,0 +1,90 @@
+package release_webserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestGetEnvironmentReleaseNotes(t *testing.T) {
+	accessor := &releaseNotesTestAccessor{releases: map[string]*status.EnvironmentRelease{
+		"int---new": {Name: "int---new", Environment: "int", ReleaseName: "new", SHA: "bbb", Components: map[string]*status.Component{
+			"Backend": {Name: "Backend", RepoURL: ptr.To("https://github.com/Azure/ARO-HCP"), SourceSHA: "2222", ImageInfo: status.ContainerImage{Digest: "sha256:new"}},
+		}},
+		"int---old": {Name: "int---old", Environment: "int", ReleaseName: "old", SHA: "aaa", Components: map[string]*status.Component{
+			"Backend": {Name: "Backend", RepoURL: ptr.To("https://github.com/Azure/ARO-HCP"), SourceSHA: "1111", ImageInfo: status.ContainerImage{Digest: "sha256:old"}},
+		}},
+	}}
+	httpRouter := gin.New()
+	httpRouter.GET("/releasenotes/:name/:otherName", GetEnvironmentReleaseNotes(accessor))
+
+	tests := []struct {
+		name                string
+		query               string
+		expectedCode        int
+		expectedContentType string
+		expectedDiffs       int
+	}{
+		{name: "default", expectedCode: http.StatusOK, expectedContentType: "text/markdown; charset=utf-8", expectedDiffs: 3},
+		{name: "text", query: "?format=txt", expectedCode: http.StatusOK, expectedContentType: "text/plain; charset=utf-8", expectedDiffs: 3},
+		{name: "unknown format", query: "?format=html", expectedCode: http.StatusBadRequest, expectedContentType: "application/json; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor.diffs = 0
+			w := httptest.NewRecorder()
+			httpRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/releasenotes/int---new/int---old"+tt.query, nil))
+			require.Equal(t, tt.expectedCode, w.Code, w.Body.String())
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedDiffs, accessor.diffs)
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			// every page is listed.
+			for _, title := range []string{"first", "second", "third"} {
+				assert.Contains(t, w.Body.String(), title)
+			}
+			assert.NotContains(t, w.Body.String(), "not listed")
+		})
+	}
+}
+
+// releaseNotesTestAccessor counts the diffs, they are the expensive part of release notes.  It pages one change at a
+// time, like a server with --max-changes-per-component=1.
+type releaseNotesTestAccessor struct {
+	release_inspection.ReleaseAccessor
+
+	releases map[string]*status.EnvironmentRelease
+	diffs    int
+}
+
+func (a *releaseNotesTestAccessor) GetEnvironmentRelease(ctx context.Context, environmentReleaseName string) (*status.EnvironmentRelease, error) {
+	environmentRelease, ok := a.releases[environmentReleaseName]
+	if !ok {
+		return nil, status.NewNotFound("EnvironmentRelease", environmentReleaseName)
+	}
+	return environmentRelease, nil
+}
+
+func (a *releaseNotesTestAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName string, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+	a.diffs++
+	changes := []status.ComponentChange{}
+	for i, title := range []string{"first", "second", "third"} {
+		changes = append(changes, status.ComponentChange{ChangeType: "GithubPRMerge", GithubPRMerge: &status.GithubPRMerge{PRNumber: int32(i + 1), ChangeSummary: title}})
+	}
+	return status.PageEnvironmentReleaseDiff(&status.EnvironmentReleaseDiff{
+		Name:                        environmentReleaseName,
+		OtherEnvironmentReleaseName: otherEnvironmentReleaseName,
+		DifferentComponents: map[string]*status.ComponentDiff{
+			"Backend": {Name: "Backend", NumberOfChanges: len(changes), Changes: changes},
+		},
+	}, status.DiffOptions{Limit: 1, Continue: options.Continue})
+}
@@ -0,0 +1,58 @@
+package release_webserver
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"k8s.io/klog/v2"
+)
+
+// watchKeepaliveInterval keeps proxies from closing idle watches.
+const watchKeepaliveInterval = 30 * time.Second
+
+// serveEnvironmentReleaseWatch streams watch events as server-sent events.  Each event is named after its type and
+// carries the resource version as its id, so browsers resume with Last-Event-ID on their own.
+func serveEnvironmentReleaseWatch(c *gin.Context, watcher *release_inspection.EnvironmentReleaseWatcher, environmentName string) {
+	ctx := c.Request.Context()
+	logger := klog.FromContext(ctx)
+
+	resourceVersion := c.Query("resourceVersion")
+	if len(resourceVersion) == 0 {
+		resourceVersion = c.GetHeader("Last-Event-ID")
+	}
+	events, stop, err := watcher.Watch(environmentName, resourceVersion)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	defer stop()
+	logger.Info("Starting watch", "environment", environmentName, "resourceVersion", resourceVersion)
+
+	keepalive := time.NewTicker(watchKeepaliveInterval)
+	defer keepalive.Stop()
+
+	c.Status(http.StatusOK)
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-keepalive.C:
+			_, err := io.WriteString(w, ": keepalive\n\n")
+			return err == nil
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.Render(-1, sse.Event{
+				Event: string(event.Type),
+				Id:    event.Object.ResourceVersion,
+				Data:  event,
+			})
+			return true
+		}
+	})
+}
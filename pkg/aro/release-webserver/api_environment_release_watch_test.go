@@ -0,0 +1,38 @@
+package release_webserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/stretchr/testify/assert"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestWatchWithoutARunningWatcher(t *testing.T) {
+	accessor := &contractTestAccessor{environmentReleases: &status.EnvironmentReleaseList{}}
+	watcher := release_inspection.NewEnvironmentReleaseWatcher(accessor, time.Minute, clocktesting.NewFakeClock(time.Now()))
+	httpRouter := gin.New()
+	httpRouter.GET("/api/aro-hcp/environmentreleases", ListEnvironmentReleases(accessor, watcher))
+	httpRouter.GET("/api/aro-hcp/environments/:name/environmentreleases", ListEnvironmentReleasesForEnvironment(accessor, watcher))
+
+	for _, requestURL := range []string{
+		"/api/aro-hcp/environmentreleases?watch=true",
+		"/api/aro-hcp/environments/int/environmentreleases?watch=true",
+	} {
+		t.Run(requestURL, func(t *testing.T) {
+			// a watch that was let through would only end with the request.
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			w := httptest.NewRecorder()
+			httpRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, requestURL, nil).WithContext(ctx))
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+			assert.NoError(t, ctx.Err())
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package release_webserver
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+)
+
+// writeError sends err as a Status.  The code and reason come from the status.StatusError it wraps, anything else is
+// an internal error.
+func writeError(c *gin.Context, err error) {
+	ret := status.StatusForError(err)
+	c.IndentedJSON(ret.Code, ret)
+}
@@ -0,0 +1,82 @@
+package release_webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/openshift-online/service-status/pkg/metrics"
+)
+
+// Healthz answers as long as the server is running.
+func Healthz() func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	}
+}
+
+// Readyz fails until every environment has been scanned once, so traffic isn't sent to a server whose pages would
+// hang on the first scan.  A nil scanStatusRecorder means nothing is scanned, like when serving a file-based API.
+func Readyz(accessor release_inspection.ReleaseAccessor, scanStatusRecorder *release_inspection.ScanStatusRecorder) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		if err := checkReady(c, accessor, scanStatusRecorder); err != nil {
+			writeError(c, err)
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	}
+}
+
+func checkReady(ctx context.Context, accessor release_inspection.ReleaseAccessor, scanStatusRecorder *release_inspection.ScanStatusRecorder) error {
+	if scanStatusRecorder == nil {
+		return nil
+	}
+	environments, err := accessor.ListEnvironments(ctx)
+	if err != nil {
+		return err
+	}
+	unscanned := []string{}
+	for _, environment := range environments {
+		if !scanStatusRecorder.HasCompletedScan(environment) {
+			unscanned = append(unscanned, environment)
+		}
+	}
+	if len(unscanned) > 0 {
+		return status.NewServiceUnavailable(fmt.Sprintf("waiting for the first scan of: %s", strings.Join(unscanned, ", ")))
+	}
+	return nil
+}
+
+// GetServerStatus reports what is being served: the scans of each environment, the repositories and caches they
+// read from and the latest failures talking to git, podman and sippy.
+func GetServerStatus(accessor release_inspection.CachingReleaseAccessor, scanStatusRecorder *release_inspection.ScanStatusRecorder, refresher *release_inspection.RepositoryRefresher) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ret := &status.ServerStatus{
+			TypeMeta: status.TypeMeta{
+				Kind:       "ServerStatus",
+				APIVersion: "service-status.hcm.openshift.io/v1",
+			},
+			Ready:           checkReady(c, accessor, scanStatusRecorder) == nil,
+			Environments:    []status.EnvironmentScanStatus{},
+			Repositories:    refresher.ListRepositories().Items,
+			Caches:          accessor.ListCaches().Items,
+			SubsystemErrors: []status.SubsystemError{},
+		}
+		if scanStatusRecorder != nil {
+			ret.Environments = scanStatusRecorder.ListScanStatuses()
+		}
+		for _, externalCallError := range metrics.LatestExternalCallErrors() {
+			ret.SubsystemErrors = append(ret.SubsystemErrors, status.SubsystemError{
+				System:    externalCallError.System,
+				Operation: externalCallError.Operation,
+				Time:      externalCallError.Time,
+				Error:     externalCallError.Err.Error(),
+			})
+		}
+		c.IndentedJSON(http.StatusOK, ret)
+	}
+}
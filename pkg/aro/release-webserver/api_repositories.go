@@ -0,0 +1,14 @@
+package release_webserver
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+)
+
+func ListRepositories(refresher *release_inspection.RepositoryRefresher) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		c.IndentedJSON(http.StatusOK, refresher.ListRepositories())
+	}
+}
@@ -0,0 +1,197 @@
+package release_webserver
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/openshift-online/service-status/pkg/openapi"
+)
+
+// APIRoute is a GET endpoint of the JSON API.  /openapi.json is generated from the routes, so every endpoint with a
+// schema belongs here.
+type APIRoute struct {
+	// Path uses gin's :name syntax.
+	Path string
+	// OperationID names the method of generated clients.
+	OperationID string
+	Summary     string
+	// Response is a value of the type sent on success.  Only its type is used.
+	Response any
+	// ContentType is empty for JSON.  Other content types are documented as strings.
+	ContentType     string
+	QueryParameters []openapi.Parameter
+	Handler         gin.HandlerFunc
+}
+
+var (
+	stringSchema  = openapi.Schema{Type: "string"}
+	timeSchema    = openapi.Schema{Type: "string", Format: "date-time"}
+	integerSchema = openapi.Schema{Type: "integer", Format: "int64"}
+	booleanSchema = openapi.Schema{Type: "boolean"}
+)
+
+var listQueryParameters = []openapi.Parameter{
+	queryParameter("since", timeSchema, "only lists releases at or after this RFC3339 time"),
+	queryParameter("until", timeSchema, "only lists releases before this RFC3339 time"),
+	queryParameter("component", stringSchema, "only lists releases with this component, without the other components"),
+	queryParameter("digest", stringSchema, "only lists releases with a component image of this digest"),
+	queryParameter("limit", integerSchema, "the most releases returned"),
+	queryParameter("continue", stringSchema, "the continue token of the previous page"),
+	queryParameter("fields", stringSchema, "comma separated JSON names of the release fields to return, the others are left out"),
+	queryParameter("resourceVersion", stringSchema, "with watch, only send the changes after this resource version"),
+	queryParameter("watch", booleanSchema, "true streams changes as server-sent events instead of listing"),
+}
+
+func queryParameter(name string, schema openapi.Schema, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: &schema}
+}
+
+func NewAPIRoutes(accessor release_inspection.CachingReleaseAccessor, watcher *release_inspection.EnvironmentReleaseWatcher, refresher *release_inspection.RepositoryRefresher, scanStatusRecorder *release_inspection.ScanStatusRecorder) []APIRoute {
+	return []APIRoute{
+		{
+			Path:        "/statusz",
+			OperationID: "getServerStatus",
+			Summary:     "What the server is serving and how its last scans went",
+			Response:    status.ServerStatus{},
+			Handler:     GetServerStatus(accessor, scanStatusRecorder, refresher),
+		},
+		{
+			Path:        "/api/aro-hcp/environments",
+			OperationID: "listEnvironments",
+			Summary:     "List environments",
+			Response:    status.EnvironmentList{},
+			Handler:     ListEnvironments(accessor),
+		},
+		{
+			Path:        "/api/aro-hcp/environments/:name",
+			OperationID: "getEnvironment",
+			Summary:     "Get an environment",
+			Response:    status.Environment{},
+			Handler:     GetEnvironment(accessor),
+		},
+		{
+			Path:            "/api/aro-hcp/environments/:name/environmentreleases",
+			OperationID:     "listEnvironmentReleasesForEnvironment",
+			Summary:         "List the releases of an environment, newest first",
+			Response:        status.EnvironmentReleaseList{},
+			QueryParameters: listQueryParameters,
+			Handler:         ListEnvironmentReleasesForEnvironment(accessor, watcher),
+		},
+		{
+			Path:        "/api/aro-hcp/environments/:name/cicoverage",
+			OperationID: "getCICoverage",
+			Summary:     "Which CI jobs were seen for an environment",
+			Response:    status.CICoverage{},
+			Handler:     GetCICoverage(accessor),
+		},
+		{
+			Path:        "/api/aro-hcp/environmentreleases",
+			OperationID: "listEnvironmentReleases",
+			Summary:     "List the releases of every environment",
+			Response:    status.EnvironmentReleaseList{},
+			QueryParameters: append([]openapi.Parameter{
+				queryParameter("environment", stringSchema, "only lists releases of this environment"),
+			}, listQueryParameters...),
+			Handler: ListEnvironmentReleases(accessor, watcher),
+		},
+		{
+			Path:        "/api/aro-hcp/environmentreleases/:name",
+			OperationID: "getEnvironmentRelease",
+			Summary:     "Get an environment release",
+			Response:    status.EnvironmentRelease{},
+			Handler:     GetEnvironmentRelease(accessor),
+		},
+		{
+			Path:        "/api/aro-hcp/environmentreleases/:name/diff/:otherName",
+			OperationID: "getEnvironmentReleaseDiff",
+			Summary:     "What changed in each component from otherName to name",
+			Response:    status.EnvironmentReleaseDiff{},
+			QueryParameters: []openapi.Parameter{
+				queryParameter("limit", integerSchema, "the most changes returned for each component, at most --max-changes-per-component"),
+				queryParameter("continue", stringSchema, "the continue token of the previous page"),
+			},
+			Handler: GetEnvironmentReleaseDiff(accessor),
+		},
+		{
+			Path:        "/api/aro-hcp/environmentreleases/:name/releasenotes/:otherName",
+			OperationID: "getEnvironmentReleaseNotes",
+			Summary:     "Release notes for promoting otherName to name",
+			Response:    "",
+			ContentType: "text/markdown",
+			QueryParameters: []openapi.Parameter{
+				queryParameter("format", stringSchema, "markdown, the default, or text"),
+			},
+			Handler: GetEnvironmentReleaseNotes(accessor),
+		},
+		{
+			Path:        "/api/aro-hcp/components",
+			OperationID: "listComponents",
+			Summary:     "The version of each component in the latest release of each environment",
+			Response:    status.ComponentStatusList{},
+			Handler:     ListComponents(accessor),
+		},
+		{
+			Path:        "/api/aro-hcp/components/:name",
+			OperationID: "getComponent",
+			Summary:     "The version of a component in the latest release of each environment",
+			Response:    status.ComponentStatus{},
+			Handler:     GetComponent(accessor),
+		},
+		{
+			Path:        "/api/aro-hcp/repositories",
+			OperationID: "listRepositories",
+			Summary:     "The refresh state of each local git mirror",
+			Response:    status.RepositoryList{},
+			Handler:     ListRepositories(refresher),
+		},
+		{
+			Path:        "/api/aro-hcp/caches",
+			OperationID: "listCaches",
+			Summary:     "The state of each cache",
+			Response:    status.CacheList{},
+			Handler:     ListCaches(accessor),
+		},
+	}
+}
+
+// NewOpenAPIDocument documents every route.  Errors are documented as a Status.
+func NewOpenAPIDocument(routes []APIRoute) *openapi.Document {
+	builder := openapi.NewBuilder("service-status", "service-status.hcm.openshift.io/v1")
+	errorResponse := &openapi.Response{
+		Description: "the request failed",
+		Content: map[string]*openapi.MediaType{
+			"application/json": {Schema: builder.SchemaFor(reflect.TypeOf(status.Status{}))},
+		},
+	}
+	for _, route := range routes {
+		contentType := route.ContentType
+		if len(contentType) == 0 {
+			contentType = "application/json"
+		}
+		builder.AddGet(route.Path, &openapi.Operation{
+			OperationID: route.OperationID,
+			Summary:     route.Summary,
+			Parameters:  append([]openapi.Parameter{}, route.QueryParameters...),
+			Responses: map[string]*openapi.Response{
+				"200": {
+					Description: route.Summary,
+					Content: map[string]*openapi.MediaType{
+						contentType: {Schema: builder.SchemaFor(reflect.TypeOf(route.Response))},
+					},
+				},
+				"default": errorResponse,
+			},
+		})
+	}
+	return builder.Document()
+}
+
+func ServeOpenAPI(routes []APIRoute) func(c *gin.Context) {
+	document := NewOpenAPIDocument(routes)
+	return func(c *gin.Context) {
+		c.IndentedJSON(http.StatusOK, document)
+	}
+}
@@ -0,0 +1,339 @@
+package release_webserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/openshift-online/service-status/pkg/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+)
+
+// TestAPIRoutesMatchOpenAPI calls every route and checks the response against /openapi.json, so a handler or type
+// changing without the document, or the other way around, fails here.
+func TestAPIRoutesMatchOpenAPI(t *testing.T) {
+	environmentReleasesJSON, err := testArtifacts.ReadFile("test-artifacts/ReleaseSummaryHTML/basic/api/aro-hcp/environmentreleases.json")
+	require.NoError(t, err)
+	accessor := &contractTestAccessor{environmentReleases: &status.EnvironmentReleaseList{}}
+	require.NoError(t, json.Unmarshal(environmentReleasesJSON, accessor.environmentReleases))
+	accessor.fillEveryField()
+
+	fakeClock := clocktesting.NewFakeClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	routes := NewAPIRoutes(
+		accessor,
+		release_inspection.NewEnvironmentReleaseWatcher(accessor, time.Minute, fakeClock),
+		release_inspection.NewRepositoryRefresher([]release_inspection.RefreshableRepository{contractTestRepository{}}, time.Hour, fakeClock),
+		release_inspection.NewScanStatusRecorder(fakeClock),
+	)
+	httpRouter := gin.New()
+	for _, route := range routes {
+		httpRouter.GET(route.Path, route.Handler)
+	}
+	httpRouter.GET("/openapi.json", ServeOpenAPI(routes))
+
+	w := httptest.NewRecorder()
+	httpRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	document := &openapi.Document{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), document))
+
+	newest := accessor.environmentReleases.Items[0].Name
+	previous := accessor.environmentReleases.Items[1].Name
+	// every route needs a request here.
+	routeToRequestURL := map[string]string{
+		"/statusz":                        "/statusz",
+		"/api/aro-hcp/environments":       "/api/aro-hcp/environments",
+		"/api/aro-hcp/environments/:name": "/api/aro-hcp/environments/int",
+		"/api/aro-hcp/environments/:name/environmentreleases":            "/api/aro-hcp/environments/int/environmentreleases?limit=2",
+		"/api/aro-hcp/environments/:name/cicoverage":                     "/api/aro-hcp/environments/int/cicoverage",
+		"/api/aro-hcp/environmentreleases":                               "/api/aro-hcp/environmentreleases",
+		"/api/aro-hcp/environmentreleases/:name":                         "/api/aro-hcp/environmentreleases/" + newest,
+		"/api/aro-hcp/environmentreleases/:name/diff/:otherName":         "/api/aro-hcp/environmentreleases/" + newest + "/diff/" + previous + "?limit=1",
+		"/api/aro-hcp/environmentreleases/:name/releasenotes/:otherName": "/api/aro-hcp/environmentreleases/" + newest + "/releasenotes/" + previous,
+		"/api/aro-hcp/components":                                        "/api/aro-hcp/components",
+		"/api/aro-hcp/components/:name":                                  "/api/aro-hcp/components/" + url.PathEscape(accessor.componentName()),
+		"/api/aro-hcp/repositories":                                      "/api/aro-hcp/repositories",
+		"/api/aro-hcp/caches":                                            "/api/aro-hcp/caches",
+	}
+	for _, route := range routes {
+		t.Run(route.Path, func(t *testing.T) {
+			requestURL, ok := routeToRequestURL[route.Path]
+			require.True(t, ok, "add a request for %s", route.Path)
+
+			pathItem := document.Paths[openapi.PathFromGin(route.Path)]
+			require.NotNil(t, pathItem, "%s is not in the document", route.Path)
+
+			w := httptest.NewRecorder()
+			httpRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, requestURL, nil))
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			if len(route.ContentType) > 0 {
+				assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), route.ContentType), w.Header().Get("Content-Type"))
+				return
+			}
+			schema := pathItem.Get.Responses["200"].Content["application/json"].Schema
+			assert.NoError(t, document.Validate(schema, w.Body.Bytes()))
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		httpRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/aro-hcp/environmentreleases/missing", nil))
+		require.Equal(t, http.StatusNotFound, w.Code)
+		schema := document.Paths["/api/aro-hcp/environmentreleases/{name}"].Get.Responses["default"].Content["application/json"].Schema
+		assert.NoError(t, document.Validate(schema, w.Body.Bytes()))
+	})
+}
+
+// contractTestAccessor serves the test artifacts with every optional field set, so the whole schema is exercised.
+type contractTestAccessor struct {
+	environmentReleases *status.EnvironmentReleaseList
+}
+
+func (a *contractTestAccessor) fillEveryField() {
+	jobRunResults := []status.JobRunResults{
+		{
+			JobName:         "periodic-ci-Azure-ARO-HCP-main-periodic-integration-e2e-parallel",
+			OverallResult:   status.JobTestFailure,
+			URL:             "https://prow.ci.openshift.org/view/gs/job/1",
+			Attribution:     status.JobRunAttributionTestedCommit,
+			TestedSHA:       "53c44",
+			TestFailures:    1,
+			FailedTestNames: []string{"creates a cluster"},
+			TestFlakes:      1,
+			FlakedTestNames: []string{"deletes a cluster"},
+		},
+	}
+	newest := &a.environmentReleases.Items[0]
+	newest.BlockingJobRunResults = map[string][]status.JobRunResults{"e2e-parallel": jobRunResults}
+	newest.InformingJobRunResults = map[string][]status.JobRunResults{"e2e-parallel": jobRunResults}
+	newest.Health = &status.ReleaseHealth{
+		Verdict:          status.ReleaseHealthBlockingRed,
+		Reason:           "e2e-parallel passed 0 of 1 runs",
+		MinimumRuns:      1,
+		RequiredPassRate: 0.9,
+		JobVariants:      []status.JobVariantHealth{{Name: "e2e-parallel", Category: "Blocking", Runs: 1}},
+	}
+	newest.TestResults = &status.ReleaseTestResults{
+		MostFailedTests:                []status.TestResultSummary{{Name: "creates a cluster", Failures: 1, Flakes: 1}},
+		NewlyFailingTests:              []status.TestResultSummary{{Name: "creates a cluster", Failures: 1}},
+		PreviousEnvironmentReleaseName: a.environmentReleases.Items[1].Name,
+	}
+	newest.Conditions = []status.Condition{
+		{Type: status.ConditionDegraded, Status: status.ConditionTrue, Reason: status.ConditionReasonComponentsDegraded, Message: "one component"},
+	}
+	for _, component := range newest.Components {
+		component.ImageCreationTime = ptr.To(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC))
+		component.SourceBranches = []string{"main"}
+		component.SourceDescription = "v0.1.52-12-gabc1234 on main"
+		component.Conditions = newest.Conditions
+	}
+}
+
+func (a *contractTestAccessor) componentName() string {
+	return set.KeySet(a.environmentReleases.Items[0].Components).SortedList()[0]
+}
+
+func (a *contractTestAccessor) ListEnvironments(ctx context.Context) ([]string, error) {
+	return []string{"int", "stg", "prod"}, nil
+}
+
+func (a *contractTestAccessor) ListEnvironmentReleases(ctx context.Context) (*status.EnvironmentReleaseList, error) {
+	return a.environmentReleases, nil
+}
+
+func (a *contractTestAccessor) ListEnvironmentReleasesForEnvironment(ctx context.Context, environment string) (*status.EnvironmentReleaseList, error) {
+	return status.FilterEnvironmentReleaseList(a.environmentReleases, status.ListOptions{Environment: environment})
+}
+
+func (a *contractTestAccessor) GetEnvironmentRelease(ctx context.Context, environmentReleaseName string) (*status.EnvironmentRelease, error) {
+	for i := range a.environmentReleases.Items {
+		if a.environmentReleases.Items[i].Name == environmentReleaseName {
+			return &a.environmentReleases.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (a *contractTestAccessor) GetReleaseEnvironmentDiff(ctx context.Context, environmentReleaseName string, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+	componentName := a.componentName()
+	return status.PageEnvironmentReleaseDiff(&status.EnvironmentReleaseDiff{
+		TypeMeta:                    status.TypeMeta{Kind: "EnvironmentReleaseDiff", APIVersion: "service-status.hcm.openshift.io/v1"},
+		Name:                        environmentReleaseName,
+		OtherEnvironmentReleaseName: otherEnvironmentReleaseName,
+		DifferentComponents: map[string]*status.ComponentDiff{
+			componentName: {
+				Name:                componentName,
+				NumberOfChanges:     4,
+				Truncated:           true,
+				SearchDepthExceeded: true,
+				Changes: []status.ComponentChange{
+					{
+						ChangeType: "GithubPRMerge",
+						GithubPRMerge: &status.GithubPRMerge{
+							PRNumber: 1, SHA: "abc", ChangeSummary: "fix", JIRARefs: []string{"ARO-1"},
+							Title: "fix", Author: "a", MergedBy: "b", Labels: []string{"lgtm"}, LinkedIssues: []string{"#2"}, URL: "https://github.com/o/r/pull/1",
+						},
+					},
+					{
+						ChangeType: "GitlabMRMerge",
+						GitlabMRMerge: &status.GitlabMRMerge{
+							MRNumber: 1, SHA: "def", ChangeSummary: "fix", JIRARefs: []string{"ARO-1"},
+							Title: "fix", Author: "a", MergedBy: "b", Labels: []string{"lgtm"}, LinkedIssues: []string{"#2"}, URL: "https://gitlab.com/o/r/-/merge_requests/1",
+						},
+					},
+					{ChangeType: "Unavailable", Unavailable: ptr.To("no git history")},
+				},
+			},
+		},
+		CIComparison: &status.CIComparison{
+			SignificanceLevel: 0.05,
+			JobVariants: []status.PassRateComparison{
+				{Name: "e2e-parallel", Category: "Blocking", Runs: 10, Succeeded: 5, OtherRuns: 10, OtherSucceeded: 10, PValue: 0.03, Verdict: status.ComparisonRegressed},
+			},
+			Tests: []status.PassRateComparison{},
+		},
+	}, options)
+}
+
+func (a *contractTestAccessor) GetCICoverage(ctx context.Context, environmentName string) (*status.CICoverage, error) {
+	return &status.CICoverage{
+		TypeMeta:    status.TypeMeta{Kind: "CICoverage", APIVersion: "service-status.hcm.openshift.io/v1"},
+		Environment: environmentName,
+		Jobs: []status.CIJobCoverage{
+			{JobName: "periodic-e2e", JobVariant: "e2e-parallel", Category: "Blocking", Runs: 2, AttributedRuns: 1},
+			{JobName: "periodic-new", Runs: 1, NeedsClassification: true},
+		},
+		UnattributedJobRuns: a.environmentReleases.Items[0].BlockingJobRunResults["e2e-parallel"],
+	}, nil
+}
+
+func (a *contractTestAccessor) ListComponents(ctx context.Context) (*status.ComponentStatusList, error) {
+	newest := a.environmentReleases.Items[0]
+	component := newest.Components[a.componentName()]
+	return &status.ComponentStatusList{
+		TypeMeta: status.TypeMeta{Kind: "ComponentStatusList", APIVersion: "service-status.hcm.openshift.io/v1"},
+		Items: []status.ComponentStatus{
+			{
+				TypeMeta: status.TypeMeta{Kind: "ComponentStatus", APIVersion: "service-status.hcm.openshift.io/v1"},
+				Name:     component.Name,
+				RepoURL:  component.RepoURL,
+				Environments: []status.ComponentEnvironmentStatus{
+					{
+						Environment:                      newest.Environment,
+						EnvironmentReleaseName:           newest.Name,
+						Image:                            component.ImageInfo,
+						SourceSHA:                        component.SourceSHA,
+						PermanentURLForSourceSHA:         component.PermanentURLForSourceSHA,
+						SourceDescription:                component.SourceDescription,
+						ImageCreationTime:                component.ImageCreationTime,
+						PreviousEnvironment:              "int",
+						CommitsBehindPreviousEnvironment: ptr.To(3),
+						Conditions:                       component.Conditions,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (a *contractTestAccessor) SetSelfLookupInstance(release_inspection.ReleaseAccessor) {}
+
+func (a *contractTestAccessor) Run(ctx context.Context) {}
+
+func (a *contractTestAccessor) ListCaches() *status.CacheList {
+	return &status.CacheList{
+		TypeMeta: status.TypeMeta{Kind: "CacheList", APIVersion: "service-status.hcm.openshift.io/v1"},
+		Items: []status.Cache{
+			{
+				TypeMeta:        status.TypeMeta{Kind: "Cache", APIVersion: "service-status.hcm.openshift.io/v1"},
+				Name:            release_inspection.CacheListEnvironments,
+				TTL:             "5m0s",
+				RefreshInterval: "1m0s",
+				Entries: []status.CacheEntry{
+					{
+						Key:             "",
+						LastRefreshTime: ptr.To(time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)),
+						Age:             "24h0m0s",
+						LastError:       "timed out",
+						LastErrorTime:   ptr.To(time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+			},
+		},
+	}
+}
+
+type contractTestRepository struct{}
+
+func (contractTestRepository) Name() string                      { return "ARO-HCP" }
+func (contractTestRepository) URL() string                       { return "https://github.com/Azure/ARO-HCP" }
+func (contractTestRepository) Refresh(ctx context.Context) error { return nil }
+
+// TestDiffWithCacheKeySeparator sends release names containing the separator of the diff cache keys through the
+// caching accessor.  They used to panic in a cache refresh, which ended the process.
+func TestDiffWithCacheKeySeparator(t *testing.T) {
+	environmentReleasesJSON, err := testArtifacts.ReadFile("test-artifacts/ReleaseSummaryHTML/basic/api/aro-hcp/environmentreleases.json")
+	require.NoError(t, err)
+	delegate := &contractTestAccessor{environmentReleases: &status.EnvironmentReleaseList{}}
+	require.NoError(t, json.Unmarshal(environmentReleasesJSON, delegate.environmentReleases))
+
+	fakeClock := clocktesting.NewFakeClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	accessor := release_inspection.NewCachingReleaseAccessor(delegate, nil, fakeClock)
+	routes := NewAPIRoutes(
+		accessor,
+		release_inspection.NewEnvironmentReleaseWatcher(accessor, time.Minute, fakeClock),
+		release_inspection.NewRepositoryRefresher([]release_inspection.RefreshableRepository{contractTestRepository{}}, time.Hour, fakeClock),
+		release_inspection.NewScanStatusRecorder(fakeClock),
+	)
+	httpRouter := gin.New()
+	for _, route := range routes {
+		httpRouter.GET(route.Path, route.Handler)
+	}
+
+	newest := delegate.environmentReleases.Items[0].Name
+	tests := []struct {
+		name         string
+		requestURL   string
+		expectedCode int
+		expectedName string
+	}{
+		{
+			name:         "not an environment release name",
+			requestURL:   "/api/aro-hcp/environmentreleases/int%23%23%23x/diff/stg",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "separator inside a release name",
+			requestURL:   "/api/aro-hcp/environmentreleases/int---a%23%23%23b/diff/" + newest,
+			expectedCode: http.StatusOK,
+			expectedName: "int---a###b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			httpRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.requestURL, nil))
+			require.Equal(t, tt.expectedCode, w.Code, w.Body.String())
+			if len(tt.expectedName) == 0 {
+				return
+			}
+			diff := &status.EnvironmentReleaseDiff{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), diff))
+			assert.Equal(t, tt.expectedName, diff.Name)
+			assert.Equal(t, newest, diff.OtherEnvironmentReleaseName)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package release_webserver
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+)
+
+type htmlCICoverage struct {
+	releaseClient client.ReleaseClient
+}
+
+func (h *htmlCICoverage) ServeGin(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	environments, err := h.releaseClient.ListEnvironments(ctx)
+	if err != nil {
+		c.String(status.StatusForError(err).Code, "failed to list environments: %v", err)
+		return
+	}
+
+	ciCoverages := []*status.CICoverage{}
+	for _, environment := range environments.Items {
+		ciCoverage, err := h.releaseClient.GetCICoverage(ctx, environment.Name)
+		if err != nil {
+			c.String(status.StatusForError(err).Code, "failed to get CI coverage for %q: %v", environment.Name, err)
+			return
+		}
+		ciCoverages = append(ciCoverages, ciCoverage)
+	}
+
+	c.HTML(http.StatusOK, "http/aro-hcp/admin/cicoverage.html", gin.H{
+		"ciCoverages": ciCoverages,
+	})
+}
+
+func ServeCICoverage(releaseClient client.ReleaseClient) func(c *gin.Context) {
+	h := &htmlCICoverage{
+		releaseClient: releaseClient,
+	}
+	return h.ServeGin
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/openshift-online/service-status/pkg/aro/client"
 	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
 	"github.com/openshift-online/service-status/pkg/aro/sippy"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
 )
@@ -26,24 +27,26 @@ type htmlEnvironmentReleaseSummary struct {
 
 func (h *htmlEnvironmentReleaseSummary) ServeGin(c *gin.Context) {
 	ctx := c.Request.Context()
+	logger := klog.FromContext(ctx)
 
 	environmentReleaseName := c.Param("name")
-	environmentName, releaseName, found := release_inspection.SplitEnvironmentReleaseName(environmentReleaseName)
+	environmentName, releaseName, found := status.SplitEnvironmentReleaseName(environmentReleaseName)
 	if !found {
 		c.IndentedJSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("%q must be in format <environmentName>---<releaseName>", environmentReleaseName)})
+		return
 	}
 
 	environmentReleaseInfo, err := h.releaseClient.GetEnvironmentRelease(ctx, environmentName, releaseName)
 	if err != nil {
-		c.String(500, "failed to get release environment: %v", err)
+		c.String(status.StatusForError(err).Code, "failed to get release environment: %v", err)
 		return
 	}
 
 	var prevReleaseEnvironmentInfo *status.EnvironmentRelease
 	if otherEnvironmentReleaseName := c.Query("from"); len(otherEnvironmentReleaseName) == 0 {
-		environmentReleases, err := h.releaseClient.ListEnvironmentReleasesForEnvironment(ctx, environmentName)
+		environmentReleases, err := h.releaseClient.ListEnvironmentReleasesForEnvironment(ctx, environmentName, status.ListOptions{})
 		if err != nil {
-			c.String(500, "failed to list releases: %v", err)
+			c.String(status.StatusForError(err).Code, "failed to list releases: %v", err)
 			return
 		}
 
@@ -54,27 +57,31 @@ func (h *htmlEnvironmentReleaseSummary) ServeGin(c *gin.Context) {
 			if currEnvironmentRelease.Name == environmentReleaseName {
 				prevReleaseEnvironmentInfo, err = h.releaseClient.GetEnvironmentRelease(ctx, environmentName, environmentReleases.Items[i+1].ReleaseName)
 				if err != nil {
-					fmt.Printf("failed to get prev release: %v", err)
+					logger.Info("Failed to get previous release", "environmentRelease", environmentReleaseName, "err", err)
 				}
 				break
 			}
 		}
 	} else {
-		otherEnvironmentName, otherReleaseName, _ := release_inspection.SplitEnvironmentReleaseName(otherEnvironmentReleaseName)
+		otherEnvironmentName, otherReleaseName, _ := status.SplitEnvironmentReleaseName(otherEnvironmentReleaseName)
 		var err error
 		prevReleaseEnvironmentInfo, err = h.releaseClient.GetEnvironmentRelease(ctx, otherEnvironmentName, otherReleaseName)
 		if err != nil {
-			c.String(500, "failed to get from environment release: %v", err)
+			c.String(status.StatusForError(err).Code, "failed to get from environment release: %v", err)
 			return
 		}
 	}
 
 	changedComponents := release_inspection.ChangedComponents(environmentReleaseInfo, prevReleaseEnvironmentInfo)
 	changedNameToDetails := map[string]template.HTML{}
+	ciComparisonHTML := ""
 	if prevReleaseEnvironmentInfo != nil {
-		diff, err := h.releaseClient.GetEnvironmentReleaseDiff(ctx, environmentReleaseInfo.Name, prevReleaseEnvironmentInfo.Name)
+		diff, err := h.releaseClient.GetEnvironmentReleaseDiff(ctx, environmentReleaseInfo.Name, prevReleaseEnvironmentInfo.Name, status.DiffOptions{})
 		if err != nil {
-			fmt.Printf("failed to get diff for %q and %q: %v", environmentReleaseInfo.Name, prevReleaseEnvironmentInfo.Name, err)
+			logger.Info("Failed to get diff", "environmentRelease", environmentReleaseInfo.Name, "otherEnvironmentRelease", prevReleaseEnvironmentInfo.Name, "err", err)
+		}
+		if diff != nil {
+			ciComparisonHTML = htmlForCIComparison(diff.CIComparison)
 		}
 		for _, componentName := range changedComponents.UnsortedList() {
 			var currImageDetails *status.Component
@@ -96,6 +103,10 @@ func (h *htmlEnvironmentReleaseSummary) ServeGin(c *gin.Context) {
 			if diff != nil {
 				componentDiff = diff.DifferentComponents[componentName]
 			}
+			if currImageDetails == nil {
+				// removed components have no details to show.
+				continue
+			}
 			detailsDiffHTML := htmlDetailsForComponentDiff(currImageDetails, prevImageDetails, prevReleaseEnvironmentInfo, componentDiff)
 			changedNameToDetails[currImageDetails.Name] = template.HTML(detailsDiffHTML)
 		}
@@ -115,9 +126,9 @@ func (h *htmlEnvironmentReleaseSummary) ServeGin(c *gin.Context) {
 		prevEnvReleaseNameURLEscaped = url.PathEscape(prevReleaseEnvironmentInfo.Name)
 	}
 
-	allEnvironmentReleases, err := h.releaseClient.ListEnvironmentReleases(ctx)
+	allEnvironmentReleases, err := h.releaseClient.ListEnvironmentReleases(ctx, status.ListOptions{})
 	if err != nil {
-		c.String(500, "failed to list allEnvironmentReleases: %v", err)
+		c.String(status.StatusForError(err).Code, "failed to list allEnvironmentReleases: %v", err)
 		return
 	}
 
@@ -133,6 +144,9 @@ func (h *htmlEnvironmentReleaseSummary) ServeGin(c *gin.Context) {
 		"allEnvironmentReleases":        allEnvironmentReleases.Items,
 		"blockingCIHTML":                template.HTML(htmlForCIResults(environmentReleaseInfo.Environment, environmentReleaseInfo.BlockingJobRunResults)),
 		"informingCIHTML":               template.HTML(htmlForCIResults(environmentReleaseInfo.Environment, environmentReleaseInfo.InformingJobRunResults)),
+		"testResultsHTML":               template.HTML(htmlForTestResults(environmentReleaseInfo.Environment, environmentReleaseInfo.TestResults)),
+		"ciComparisonHTML":              template.HTML(ciComparisonHTML),
+		"conditionsHTML":                template.HTML(htmlConditionsAlert(environmentReleaseInfo.Conditions)),
 	})
 }
 
@@ -179,14 +193,24 @@ func htmlForCIResults(environmentName string, ciResults map[string][]status.JobR
 		currURL.RawQuery = queryParams.Encode()
 
 		currResultsHTML += fmt.Sprintf(`<a href=%q>%s</a>: `, currURL.String(), jobName)
+		hasWallClockResults := false
 		for _, currResult := range currResults {
+			// results only attributed by start time may have tested the previous release, so mark them.
+			resultString := string(currResult.OverallResult)
+			if currResult.Attribution == status.JobRunAttributionWallClock {
+				resultString += "*"
+				hasWallClockResults = true
+			}
 			if currResult.OverallResult == status.JobSucceeded {
-				currResultsHTML += fmt.Sprintf(`<a href=%q class="text-success">%s</a> `, currResult.URL, currResult.OverallResult)
+				currResultsHTML += fmt.Sprintf(`<a href=%q class="text-success">%s</a> `, currResult.URL, resultString)
 			} else {
-				currResultsHTML += fmt.Sprintf(`<a href=%q class="text-danger">%s</a> `, currResult.URL, currResult.OverallResult)
+				currResultsHTML += fmt.Sprintf(`<a href=%q class="text-danger">%s</a> `, currResult.URL, resultString)
 
 			}
 		}
+		if hasWallClockResults {
+			currResultsHTML += `<small class="text-muted">* attributed by start time, the tested commit is unknown</small>`
+		}
 		currResultsHTML += "</li>\n"
 		retHTML += currResultsHTML
 	}
@@ -194,6 +218,84 @@ func htmlForCIResults(environmentName string, ciResults map[string][]status.JobR
 	return retHTML
 }
 
+func htmlForTestResults(environmentName string, testResults *status.ReleaseTestResults) string {
+	if testResults == nil {
+		return ""
+	}
+
+	testLines := func(testSummaries []status.TestResultSummary) string {
+		lines := []string{}
+		for _, testSummary := range testSummaries {
+			currURL := &url.URL{
+				Scheme: "https",
+				Host:   "sippy.dptools.openshift.org",
+				Path:   "sippy-ng/tests/" + release_inspection.EnvironmentToSippyReleaseName(environmentName) + "/analysis",
+			}
+			queryParams := currURL.Query()
+			queryParams.Add("test", testSummary.Name)
+			currURL.RawQuery = queryParams.Encode()
+
+			lines = append(lines, fmt.Sprintf(`<li><a target="_blank" href=%q>%s</a>: %d failures, %d flakes</li>`,
+				currURL.String(), template.HTMLEscapeString(testSummary.Name), testSummary.Failures, testSummary.Flakes))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	retHTML := ""
+	if len(testResults.PreviousEnvironmentReleaseName) > 0 {
+		if len(testResults.NewlyFailingTests) == 0 {
+			retHTML += fmt.Sprintf("<li>No tests newly failing since %s</li>\n", testResults.PreviousEnvironmentReleaseName)
+		} else {
+			retHTML += fmt.Sprintf("<li>Newly failing since %s\n<ul>\n%s\n</ul>\n</li>\n", testResults.PreviousEnvironmentReleaseName, testLines(testResults.NewlyFailingTests))
+		}
+	}
+	if len(testResults.MostFailedTests) == 0 {
+		retHTML += "<li>No failing tests</li>\n"
+	} else {
+		retHTML += fmt.Sprintf("<li>Most failed tests\n<ul>\n%s\n</ul>\n</li>\n", testLines(testResults.MostFailedTests))
+	}
+	return retHTML
+}
+
+func htmlForCIComparison(ciComparison *status.CIComparison) string {
+	if ciComparison == nil {
+		return ""
+	}
+
+	verdictHTML := func(comparison status.PassRateComparison) string {
+		switch comparison.Verdict {
+		case status.ComparisonRegressed:
+			return `<span class="text-danger">Regressed</span>`
+		case status.ComparisonImproved:
+			return `<span class="text-success">Improved</span>`
+		default:
+			return "No significant change"
+		}
+	}
+	rowHTML := func(name string, comparison status.PassRateComparison) string {
+		return fmt.Sprintf("<tr><td>%s</td><td>%d/%d</td><td>%d/%d</td><td>%.3f</td><td>%s</td></tr>\n",
+			template.HTMLEscapeString(name), comparison.Succeeded, comparison.Runs, comparison.OtherSucceeded, comparison.OtherRuns, comparison.PValue, verdictHTML(comparison))
+	}
+
+	retHTML := fmt.Sprintf("<p>Fisher's exact test, differences with p &lt; %.2f are significant.</p>\n", ciComparison.SignificanceLevel)
+	retHTML += `<table class="table small"><tr><th>Job variant</th><th>This release</th><th>Previous release</th><th>p-value</th><th>Verdict</th></tr>` + "\n"
+	for _, comparison := range ciComparison.JobVariants {
+		retHTML += rowHTML(fmt.Sprintf("%s (%s)", comparison.Name, comparison.Category), comparison)
+	}
+	retHTML += "</table>\n"
+
+	if len(ciComparison.Tests) == 0 {
+		retHTML += "<p>No test pass rate changed significantly.</p>\n"
+		return retHTML
+	}
+	retHTML += `<table class="table small"><tr><th>Test</th><th>This release</th><th>Previous release</th><th>p-value</th><th>Verdict</th></tr>` + "\n"
+	for _, comparison := range ciComparison.Tests {
+		retHTML += rowHTML(comparison.Name, comparison)
+	}
+	retHTML += "</table>\n"
+	return retHTML
+}
+
 func htmlDetailsForComponent(imageDetails *status.Component) string {
 	imageAgeString := "Unknown age"
 	imageTimeString := "Unknown time"
@@ -203,19 +305,22 @@ func htmlDetailsForComponent(imageDetails *status.Component) string {
 	}
 
 	imageSourceSHAString := imageDetails.SourceSHA
-	if !strings.HasPrefix(imageDetails.SourceSHA, "ERROR") {
-		switch {
-		case len(imageDetails.SourceSHA) == 0:
-			imageSourceSHAString = "MISSING"
-		case imageDetails.PermanentURLForSourceSHA != nil && len(imageDetails.SourceSHA) > 0:
-			imageSourceSHAString = fmt.Sprintf("<a href=%q>%s</a>", *imageDetails.PermanentURLForSourceSHA, imageDetails.SourceSHA)
-		default:
-			imageSourceSHAString = "DEFAULT"
-		}
+	switch {
+	case len(imageDetails.SourceSHA) == 0 && status.IsConditionTrue(imageDetails.Conditions, status.ConditionDegraded):
+		imageSourceSHAString = "UNKNOWN"
+	case len(imageDetails.SourceSHA) == 0:
+		imageSourceSHAString = "MISSING"
+	case imageDetails.PermanentURLForSourceSHA != nil && len(imageDetails.SourceSHA) > 0:
+		imageSourceSHAString = fmt.Sprintf("<a href=%q>%s</a>", *imageDetails.PermanentURLForSourceSHA, imageDetails.SourceSHA)
+	default:
+		imageSourceSHAString = "DEFAULT"
+	}
+	if len(imageDetails.SourceDescription) > 0 {
+		imageSourceSHAString += fmt.Sprintf(" (%s)", template.HTMLEscapeString(imageDetails.SourceDescription))
 	}
 
 	detailsHTML := fmt.Sprintf(`
-		<h4><a target="_blank" href=%q>%s (%s)</a></h4>
+		<h4><a target="_blank" href=%q>%s (%s)</a>%s</h4>
         <details>
             <summary class="small mb-3">click to expand details</summary>
             <ul>
@@ -223,19 +328,64 @@ func htmlDetailsForComponent(imageDetails *status.Component) string {
                 <ul>
                     <li>Image built %s</li>
                 </ul>
-                <li>Commit: %s</li>
+                <li>Commit: %s</li>%s
             </ul>
         </details>
 `,
-		ptr.Deref(imageDetails.RepoURL, "MISSING"), imageDetails.Name, imageAgeString,
+		ptr.Deref(imageDetails.RepoURL, "MISSING"), imageDetails.Name, imageAgeString, htmlConditionBadges(imageDetails.Conditions),
 		fmt.Sprintf("%s/%s@%s", imageDetails.ImageInfo.Registry, imageDetails.ImageInfo.Repository, imageDetails.ImageInfo.Digest),
 		imageTimeString,
 		imageSourceSHAString,
+		htmlConditionListItems(imageDetails.Conditions),
 	)
 
 	return detailsHTML
 }
 
+// htmlConditionBadges shows a warning badge for each true condition, with the message on hover.
+func htmlConditionBadges(conditions []status.Condition) string {
+	ret := ""
+	for _, condition := range conditions {
+		if condition.Status != status.ConditionTrue {
+			continue
+		}
+		ret += fmt.Sprintf(` <span class="badge badge-warning" title="%s">%s: %s</span>`,
+			template.HTMLEscapeString(condition.Message),
+			template.HTMLEscapeString(string(condition.Type)),
+			template.HTMLEscapeString(condition.Reason),
+		)
+	}
+	return ret
+}
+
+// htmlConditionListItems spells out the true conditions, for the expanded details.
+func htmlConditionListItems(conditions []status.Condition) string {
+	ret := ""
+	for _, condition := range conditions {
+		if condition.Status != status.ConditionTrue {
+			continue
+		}
+		ret += fmt.Sprintf("\n                <li class=\"text-warning\">%s (%s): %s</li>",
+			template.HTMLEscapeString(string(condition.Type)),
+			template.HTMLEscapeString(condition.Reason),
+			template.HTMLEscapeString(condition.Message),
+		)
+	}
+	return ret
+}
+
+// htmlConditionsAlert explains why a release is degraded, so missing information isn't taken for no change.
+func htmlConditionsAlert(conditions []status.Condition) string {
+	items := htmlConditionListItems(conditions)
+	if len(items) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`<div class="alert alert-warning">Some of this release could not be read:<ul>%s</ul></div>`, items)
+}
+
+// maxInlineChanges is how many changes are listed before the rest are collapsed.
+const maxInlineChanges = 20
+
 func htmlDetailsForComponentDiff(currImageDetails, prevImageDetails *status.Component, prevReleaseEnvironmentInfo *status.EnvironmentRelease, diff *status.ComponentDiff) string {
 	prevReleaseString := fmt.Sprintf("<a href=/http/aro-hcp/environmentreleases/%s/summary.html>%s</a>", prevReleaseEnvironmentInfo.Name, prevReleaseEnvironmentInfo.Name)
 
@@ -254,15 +404,18 @@ func htmlDetailsForComponentDiff(currImageDetails, prevImageDetails *status.Comp
 	}
 
 	imageSourceSHAString := currImageDetails.SourceSHA
-	if !strings.HasPrefix(currImageDetails.SourceSHA, "ERROR") {
-		switch {
-		case len(currImageDetails.SourceSHA) == 0:
-			imageSourceSHAString = "MISSING"
-		case currImageDetails.PermanentURLForSourceSHA != nil && len(currImageDetails.SourceSHA) > 0:
-			imageSourceSHAString = fmt.Sprintf("<a href=%q>%s</a>", *currImageDetails.PermanentURLForSourceSHA, currImageDetails.SourceSHA)
-		default:
-			imageSourceSHAString = "DEFAULT"
-		}
+	switch {
+	case len(currImageDetails.SourceSHA) == 0 && status.IsConditionTrue(currImageDetails.Conditions, status.ConditionDegraded):
+		imageSourceSHAString = "UNKNOWN"
+	case len(currImageDetails.SourceSHA) == 0:
+		imageSourceSHAString = "MISSING"
+	case currImageDetails.PermanentURLForSourceSHA != nil && len(currImageDetails.SourceSHA) > 0:
+		imageSourceSHAString = fmt.Sprintf("<a href=%q>%s</a>", *currImageDetails.PermanentURLForSourceSHA, currImageDetails.SourceSHA)
+	default:
+		imageSourceSHAString = "DEFAULT"
+	}
+	if len(currImageDetails.SourceDescription) > 0 {
+		imageSourceSHAString += fmt.Sprintf(" (%s)", template.HTMLEscapeString(currImageDetails.SourceDescription))
 	}
 
 	numberOfChangesString := "Unknown changes"
@@ -271,6 +424,9 @@ func htmlDetailsForComponentDiff(currImageDetails, prevImageDetails *status.Comp
 		if diff.NumberOfChanges >= 0 {
 			numberOfChangesString = fmt.Sprintf("%d changes", diff.NumberOfChanges)
 		}
+		if diff.SearchDepthExceeded {
+			numberOfChangesString = fmt.Sprintf("More than %d changes", diff.NumberOfChanges)
+		}
 		for _, change := range diff.Changes {
 			switch {
 			case change.ChangeType == "Unavailable":
@@ -283,24 +439,35 @@ func htmlDetailsForComponentDiff(currImageDetails, prevImageDetails *status.Comp
 				if change.GithubPRMerge == nil {
 					diffLines = append(diffLines, "<li>PR merge, no info</li>")
 				} else {
-					diffLines = append(diffLines, fmt.Sprintf("<li>%s <a target=\"_blank\" href=%q>#%d</a></li>",
-						change.GithubPRMerge.ChangeSummary,
+					diffLines = append(diffLines, fmt.Sprintf("<li>%s <a target=\"_blank\" href=%q>#%d</a>%s</li>",
+						template.HTMLEscapeString(change.GithubPRMerge.ChangeSummary),
 						fmt.Sprintf("%s/pull/%d", ptr.Deref(currImageDetails.RepoURL, ""), change.GithubPRMerge.PRNumber),
 						change.GithubPRMerge.PRNumber,
+						htmlPullRequestAuthors(change.GithubPRMerge.Author, change.GithubPRMerge.MergedBy),
 					))
 				}
 			case change.ChangeType == "GitlabMRMerge":
 				if change.GitlabMRMerge == nil {
 					diffLines = append(diffLines, "<li>MR merge, no info</li>")
 				} else {
-					diffLines = append(diffLines, fmt.Sprintf("<li>%s <a target=\"_blank\" href=%q>#%d</a></li>",
-						change.GitlabMRMerge.ChangeSummary,
+					diffLines = append(diffLines, fmt.Sprintf("<li>%s <a target=\"_blank\" href=%q>#%d</a>%s</li>",
+						template.HTMLEscapeString(change.GitlabMRMerge.ChangeSummary),
 						fmt.Sprintf("%s/-/merge_requests/%d", ptr.Deref(currImageDetails.RepoURL, ""), change.GitlabMRMerge.MRNumber),
 						change.GitlabMRMerge.MRNumber,
+						htmlPullRequestAuthors(change.GitlabMRMerge.Author, change.GitlabMRMerge.MergedBy),
 					))
 				}
 			}
 		}
+		if len(diffLines) > maxInlineChanges {
+			collapsedLines := diffLines[maxInlineChanges:]
+			diffLines = append(diffLines[:maxInlineChanges:maxInlineChanges],
+				fmt.Sprintf("<li><details><summary>%d more changes</summary><ul>%s</ul></details></li>", len(collapsedLines), strings.Join(collapsedLines, "\n")),
+			)
+		}
+		if diff.Truncated {
+			diffLines = append(diffLines, fmt.Sprintf("<li>Only the newest %d of %d changes are listed</li>", len(diff.Changes), diff.NumberOfChanges))
+		}
 	}
 	if len(ptr.Deref(currImageDetails.RepoURL, "")) > 0 && prevImageDetails != nil && len(prevImageDetails.SourceSHA) > 0 {
 		diffLines = append(diffLines,
@@ -312,7 +479,7 @@ func htmlDetailsForComponentDiff(currImageDetails, prevImageDetails *status.Comp
 	}
 
 	detailsHTML := fmt.Sprintf(`
-		<h4><a target="_blank" href=%q>%s (%s, %s, %s)</a></h4>
+		<h4><a target="_blank" href=%q>%s (%s, %s, %s)</a>%s</h4>
         <details>
             <summary class="small mb-3">click to expand details</summary>
             <ul>
@@ -331,7 +498,7 @@ func htmlDetailsForComponentDiff(currImageDetails, prevImageDetails *status.Comp
             </ul>
         </details>
 `,
-		ptr.Deref(currImageDetails.RepoURL, "MISSING"), currImageDetails.Name, imageAgeString, newerString, numberOfChangesString,
+		ptr.Deref(currImageDetails.RepoURL, "MISSING"), currImageDetails.Name, imageAgeString, newerString, numberOfChangesString, htmlConditionBadges(currImageDetails.Conditions),
 		fmt.Sprintf("%s/%s@%s", currImageDetails.ImageInfo.Registry, currImageDetails.ImageInfo.Repository, currImageDetails.ImageInfo.Digest),
 		newerString,
 		imageTimeString,
@@ -343,3 +510,15 @@ func htmlDetailsForComponentDiff(currImageDetails, prevImageDetails *status.Comp
 
 	return detailsHTML
 }
+
+// htmlPullRequestAuthors is empty unless pull request enrichment found the author.
+func htmlPullRequestAuthors(author, mergedBy string) string {
+	switch {
+	case len(author) == 0:
+		return ""
+	case len(mergedBy) == 0 || mergedBy == author:
+		return fmt.Sprintf(" by %s", template.HTMLEscapeString(author))
+	default:
+		return fmt.Sprintf(" by %s, merged by %s", template.HTMLEscapeString(author), template.HTMLEscapeString(mergedBy))
+	}
+}
@@ -0,0 +1,165 @@
+package release_webserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+)
+
+type htmlPromotionMatrix struct {
+	releaseClient client.ReleaseClient
+}
+
+// promotionMatrixRow is one component across every environment.
+type promotionMatrixRow struct {
+	Name    string
+	RepoURL string
+	Cells   []promotionMatrixCell
+}
+
+type promotionMatrixCell struct {
+	Present     bool
+	SourceSHA   string
+	SourceURL   string
+	Description string
+	ImageAge    string
+	// Degraded is the message of the Degraded condition, if any.
+	Degraded string
+
+	// Lagging is set when the environment doesn't run what the previous environment runs.
+	Lagging             bool
+	PreviousEnvironment string
+	// CommitsBehind is empty when the gap couldn't be counted.
+	CommitsBehind string
+	// DiffURL shows what promoting the previous environment's release would bring.
+	DiffURL string
+	// diffEnvironmentReleaseName is compared from diffFromEnvironmentReleaseName at DiffURL.
+	diffEnvironmentReleaseName     string
+	diffFromEnvironmentReleaseName string
+}
+
+func (h *htmlPromotionMatrix) ServeGin(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	environments, err := h.releaseClient.ListEnvironments(ctx)
+	if err != nil {
+		c.String(status.StatusForError(err).Code, "failed to list environments: %v", err)
+		return
+	}
+	components, err := h.releaseClient.ListComponents(ctx)
+	if err != nil {
+		c.String(status.StatusForError(err).Code, "failed to list components: %v", err)
+		return
+	}
+
+	environmentNames := []string{}
+	for _, environment := range environments.Items {
+		environmentNames = append(environmentNames, environment.Name)
+	}
+	rows := []promotionMatrixRow{}
+	for _, component := range components.Items {
+		rows = append(rows, newPromotionMatrixRow(time.Now(), environmentNames, component))
+	}
+
+	c.HTML(http.StatusOK, "http/aro-hcp/promotion-matrix.html", gin.H{
+		"environmentNames": environmentNames,
+		"rows":             rows,
+	})
+}
+
+func newPromotionMatrixRow(now time.Time, environmentNames []string, component status.ComponentStatus) promotionMatrixRow {
+	ret := promotionMatrixRow{
+		Name: component.Name,
+	}
+	if component.RepoURL != nil {
+		ret.RepoURL = *component.RepoURL
+	}
+
+	environmentToStatus := map[string]status.ComponentEnvironmentStatus{}
+	for _, environmentStatus := range component.Environments {
+		environmentToStatus[environmentStatus.Environment] = environmentStatus
+	}
+
+	for _, environmentName := range environmentNames {
+		environmentStatus, ok := environmentToStatus[environmentName]
+		if !ok {
+			ret.Cells = append(ret.Cells, promotionMatrixCell{})
+			continue
+		}
+
+		cell := promotionMatrixCell{
+			Present:     true,
+			SourceSHA:   release_inspection.ShortSHA(environmentStatus.SourceSHA),
+			Description: environmentStatus.SourceDescription,
+			ImageAge:    "unknown age",
+		}
+		if environmentStatus.PermanentURLForSourceSHA != nil {
+			cell.SourceURL = *environmentStatus.PermanentURLForSourceSHA
+		}
+		if environmentStatus.ImageCreationTime != nil {
+			cell.ImageAge = humanize.RelTime(now, *environmentStatus.ImageCreationTime, "INVALID", "old")
+		}
+		if condition := status.FindCondition(environmentStatus.Conditions, status.ConditionDegraded); condition != nil && condition.Status == status.ConditionTrue {
+			cell.Degraded = condition.Message
+		}
+
+		previousStatus, hasPrevious := environmentToStatus[environmentStatus.PreviousEnvironment]
+		if hasPrevious && previousStatus.Image.Digest != environmentStatus.Image.Digest {
+			behind := environmentStatus.CommitsBehindPreviousEnvironment
+			// an unknown gap is still a different image, so it is shown as lagging.
+			cell.Lagging = behind == nil || *behind > 0
+			if behind != nil {
+				cell.CommitsBehind = fmt.Sprintf("%d commits behind", *behind)
+			}
+			cell.PreviousEnvironment = environmentStatus.PreviousEnvironment
+			cell.diffEnvironmentReleaseName = previousStatus.EnvironmentReleaseName
+			cell.diffFromEnvironmentReleaseName = environmentStatus.EnvironmentReleaseName
+			cell.DiffURL = promotionDiffURL(cell.diffEnvironmentReleaseName, cell.diffFromEnvironmentReleaseName)
+		}
+		ret.Cells = append(ret.Cells, cell)
+	}
+	return ret
+}
+
+// promotionDiffURL is the summary of environmentReleaseName compared from fromEnvironmentReleaseName.
+func promotionDiffURL(environmentReleaseName, fromEnvironmentReleaseName string) string {
+	return fmt.Sprintf("/http/aro-hcp/environmentreleases/%s/summary.html?from=%s",
+		url.PathEscape(environmentReleaseName),
+		url.QueryEscape(fromEnvironmentReleaseName),
+	)
+}
+
+// promotionMatrixDiffPairs are the comparisons the promotion matrix links to, each as the release and the release it
+// is compared from.
+func promotionMatrixDiffPairs(environments *status.EnvironmentList, components *status.ComponentStatusList) [][2]string {
+	environmentNames := []string{}
+	for _, environment := range environments.Items {
+		environmentNames = append(environmentNames, environment.Name)
+	}
+
+	ret := [][2]string{}
+	for _, component := range components.Items {
+		for _, cell := range newPromotionMatrixRow(time.Now(), environmentNames, component).Cells {
+			pair := [2]string{cell.diffEnvironmentReleaseName, cell.diffFromEnvironmentReleaseName}
+			if len(cell.DiffURL) > 0 && !slices.Contains(ret, pair) {
+				ret = append(ret, pair)
+			}
+		}
+	}
+	return ret
+}
+
+func ServePromotionMatrix(releaseClient client.ReleaseClient) func(c *gin.Context) {
+	h := &htmlPromotionMatrix{
+		releaseClient: releaseClient,
+	}
+	return h.ServeGin
+}
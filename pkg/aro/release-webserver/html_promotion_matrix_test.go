@@ -0,0 +1,29 @@
+package release_webserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewPromotionMatrixRow(t *testing.T) {
+	row := newPromotionMatrixRow(time.Now(), []string{"int", "stg", "prod"}, status.ComponentStatus{
+		Name: "Cluster Service",
+		Environments: []status.ComponentEnvironmentStatus{
+			{Environment: "int", EnvironmentReleaseName: "int---b", Image: status.ContainerImage{Digest: "sha256:new"}, SourceSHA: "0123456789abcdef"},
+			{Environment: "stg", EnvironmentReleaseName: "stg---a", Image: status.ContainerImage{Digest: "sha256:old"}, PreviousEnvironment: "int", CommitsBehindPreviousEnvironment: ptr.To(4)},
+		},
+	})
+
+	require.Len(t, row.Cells, 3)
+	assert.Equal(t, "0123456789ab", row.Cells[0].SourceSHA)
+	assert.False(t, row.Cells[0].Lagging)
+	assert.True(t, row.Cells[1].Lagging)
+	assert.Equal(t, "4 commits behind", row.Cells[1].CommitsBehind)
+	assert.Equal(t, "/http/aro-hcp/environmentreleases/int---b/summary.html?from=stg---a", row.Cells[1].DiffURL)
+	assert.False(t, row.Cells[2].Present)
+}
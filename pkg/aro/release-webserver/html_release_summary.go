@@ -11,11 +11,13 @@ import (
 	"github.com/openshift-online/service-status/pkg/apis/status"
 	"github.com/openshift-online/service-status/pkg/aro/client"
 	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"k8s.io/utils/clock"
 	"k8s.io/utils/set"
 )
 
 type htmlReleaseSummary struct {
 	releaseClient client.ReleaseClient
+	clock         clock.PassiveClock
 }
 
 func (h *htmlReleaseSummary) ServeGin(c *gin.Context) {
@@ -23,15 +25,15 @@ func (h *htmlReleaseSummary) ServeGin(c *gin.Context) {
 
 	environments, err := h.releaseClient.ListEnvironments(ctx)
 	if err != nil {
-		c.String(500, "failed to list environments: %v", err)
+		c.String(status.StatusForError(err).Code, "failed to list environments: %v", err)
 		return
 	}
 
 	environmentToEnvironmentReleases := map[string]*status.EnvironmentReleaseList{}
 	for _, environment := range environments.Items {
-		environmentReleases, err := h.releaseClient.ListEnvironmentReleasesForEnvironment(ctx, environment.Name)
+		environmentReleases, err := h.releaseClient.ListEnvironmentReleasesForEnvironment(ctx, environment.Name, status.ListOptions{})
 		if err != nil {
-			c.String(500, "failed to list environments: %v", err)
+			c.String(status.StatusForError(err).Code, "failed to list environments: %v", err)
 			return
 		}
 		environmentToEnvironmentReleases[environment.Name] = environmentReleases
@@ -69,7 +71,7 @@ func (h *htmlReleaseSummary) ServeGin(c *gin.Context) {
 		}
 
 		if len(environmentToSummaryHTML[environment.Name]) == 0 { // first one is the summary one
-			environmentToSummaryHTML[environment.Name] = summaryForEnvironment(environment.Name, environmentToEnvironmentReleases)
+			environmentToSummaryHTML[environment.Name] = summaryForEnvironment(h.clock.Now(), environment.Name, environmentToEnvironmentReleases)
 		}
 
 	}
@@ -99,7 +101,7 @@ func perEnvironmentReleaseRow(currReleaseEnvironmentInfo status.EnvironmentRelea
 		fmt.Sprintf(`
         <tr>
             <td class="text-monospace">
-                <a href=%q>%s</a>
+                <a href=%q>%s</a>%s%s
             </td>
             <td >
                 %s
@@ -112,8 +114,10 @@ func perEnvironmentReleaseRow(currReleaseEnvironmentInfo status.EnvironmentRelea
             </td>
         </tr>
 `,
-			fmt.Sprintf("/http/aro-hcp/environmentreleases/%s/summary.html", url.PathEscape(release_inspection.MakeEnvironmentReleaseName(currReleaseEnvironmentInfo.Environment, currReleaseEnvironmentInfo.ReleaseName))),
+			fmt.Sprintf("/http/aro-hcp/environmentreleases/%s/summary.html", url.PathEscape(status.MakeEnvironmentReleaseName(currReleaseEnvironmentInfo.Environment, currReleaseEnvironmentInfo.ReleaseName))),
 			currReleaseEnvironmentInfo.ReleaseName,
+			htmlReleaseHealthBadge(currReleaseEnvironmentInfo.Health),
+			htmlReleaseConditionBadges(currReleaseEnvironmentInfo.Conditions),
 			jobRunsHTML,
 			matchingReleasesHTML,
 			changesList,
@@ -121,6 +125,29 @@ func perEnvironmentReleaseRow(currReleaseEnvironmentInfo status.EnvironmentRelea
 	)
 }
 
+// htmlReleaseConditionBadges goes on its own line below the health badge.
+func htmlReleaseConditionBadges(conditions []status.Condition) string {
+	badges := htmlConditionBadges(conditions)
+	if len(badges) == 0 {
+		return ""
+	}
+	return "<br/>" + badges
+}
+
+func htmlReleaseHealthBadge(health *status.ReleaseHealth) string {
+	if health == nil {
+		return ""
+	}
+	badgeClass, badgeText := "badge-secondary", "Insufficient data"
+	switch health.Verdict {
+	case status.ReleaseHealthBlockingGreen:
+		badgeClass, badgeText = "badge-success", "Blocking green"
+	case status.ReleaseHealthBlockingRed:
+		badgeClass, badgeText = "badge-danger", "Blocking red"
+	}
+	return fmt.Sprintf(`<br/><span class="badge %s" title="%s">%s</span>`, badgeClass, template.HTMLEscapeString(health.Reason), badgeText)
+}
+
 func htmlCellMatchingReleases(currReleaseEnvironmentInfo status.EnvironmentRelease, environmentToEnvironmentReleases map[string]*status.EnvironmentReleaseList) string {
 	retMatchingReleases := []string{}
 
@@ -188,8 +215,7 @@ func htmlRowsForCIResults(ciResults map[string][]status.JobRunResults) string {
 	return retHTML
 }
 
-func summaryForEnvironment(environmentName string, environmentToEnvironmentReleases map[string]*status.EnvironmentReleaseList) template.HTML {
-	now := time.Now()
+func summaryForEnvironment(now time.Time, environmentName string, environmentToEnvironmentReleases map[string]*status.EnvironmentReleaseList) template.HTML {
 	if environmentName == "int" {
 		if len(environmentToEnvironmentReleases[environmentName].Items) == 0 {
 			return "No releases found."
@@ -197,24 +223,11 @@ func summaryForEnvironment(environmentName string, environmentToEnvironmentRelea
 
 		lines := []string{}
 		environmentRelease := environmentToEnvironmentReleases[environmentName].Items[0]
-		for _, componentName := range set.KeySet(environmentRelease.Components).SortedList() {
-			component := environmentRelease.Components[componentName]
-			if component.ImageCreationTime == nil {
-				continue
-			}
-			acceptableLatency := release_inspection.HardcodedComponents[component.Name].LatencyThreshold
-			if acceptableLatency == 0 {
-				continue
-			}
-
-			daysOld := now.Sub(*component.ImageCreationTime) / (24 * time.Hour)
-			roughWorkingDuration := now.Sub(*component.ImageCreationTime) - daysOld
-			if roughWorkingDuration > acceptableLatency {
-				lines = append(lines,
-					fmt.Sprintf("<li><b>%s</b> needs to be updated.  It is about %d days old and should be updated every %d days.</li>",
-						component.Name, roughWorkingDuration/(24*time.Hour), acceptableLatency/(24*time.Hour)),
-				)
-			}
+		for _, staleComponent := range release_inspection.StaleComponents(now, &environmentRelease) {
+			lines = append(lines,
+				fmt.Sprintf("<li><b>%s</b> needs to be updated.  It is about %d days old and should be updated every %d days.</li>",
+					staleComponent.Name, staleComponent.Age/(24*time.Hour), staleComponent.LatencyThreshold/(24*time.Hour)),
+			)
 		}
 
 		if len(lines) == 0 {
@@ -233,8 +246,13 @@ func summaryForEnvironment(environmentName string, environmentToEnvironmentRelea
 		stageEnvironmentReleases := environmentToEnvironmentReleases[environmentName]
 		if len(stageEnvironmentReleases.Items) > 0 {
 			stageEnvironmentRelease := stageEnvironmentReleases.Items[0]
-			minChangedRelease, minChangedComponents := findMatchingEnvironmentRelease(environmentToEnvironmentReleases["int"], &stageEnvironmentRelease)
-			if len(minChangedComponents) > 0 {
+			minChangedRelease, minChangedComponents := release_inspection.FindMatchingEnvironmentRelease(environmentToEnvironmentReleases["int"], &stageEnvironmentRelease)
+			switch {
+			case minChangedRelease == nil:
+				lines = append(lines,
+					fmt.Sprintf("<li><b>%s</b> was never tested in integration. No release could be compared with it.</li>", stageEnvironmentRelease.Name),
+				)
+			case len(minChangedComponents) > 0:
 				lines = append(lines,
 					fmt.Sprintf("<li><b>%s</b> was never tested in integration. Closest release is %s which differs by %s.</li>",
 						stageEnvironmentRelease.Name, minChangedRelease.Name, strings.Join(minChangedComponents.SortedList(), ", ")),
@@ -258,8 +276,13 @@ func summaryForEnvironment(environmentName string, environmentToEnvironmentRelea
 		prodEnvironmentReleases := environmentToEnvironmentReleases[environmentName]
 		if len(prodEnvironmentReleases.Items) > 0 {
 			prodEnvironmentRelease := prodEnvironmentReleases.Items[0]
-			minChangedRelease, minChangedComponents := findMatchingEnvironmentRelease(environmentToEnvironmentReleases["stg"], &prodEnvironmentRelease)
-			if len(minChangedComponents) > 0 {
+			minChangedRelease, minChangedComponents := release_inspection.FindMatchingEnvironmentRelease(environmentToEnvironmentReleases["stg"], &prodEnvironmentRelease)
+			switch {
+			case minChangedRelease == nil:
+				lines = append(lines,
+					fmt.Sprintf("<li><b>%s</b> was never tested in stage. No release could be compared with it.</li>", prodEnvironmentRelease.Name),
+				)
+			case len(minChangedComponents) > 0:
 				lines = append(lines,
 					fmt.Sprintf("<li><b>%s</b> was never tested in stage. Closest release is %s which differs by %s.</li>",
 						prodEnvironmentRelease.Name, minChangedRelease.Name, strings.Join(minChangedComponents.SortedList(), ", ")),
@@ -281,29 +304,10 @@ func summaryForEnvironment(environmentName string, environmentToEnvironmentRelea
 	return ""
 }
 
-func findMatchingEnvironmentRelease(haystack *status.EnvironmentReleaseList, needle *status.EnvironmentRelease) (*status.EnvironmentRelease, set.Set[string]) {
-	var minChangedComponents set.Set[string]
-	var minChangedRelease *status.EnvironmentRelease
-	for i := range haystack.Items {
-		currEnvironmentRelease := haystack.Items[i]
-
-		currChangedComponents := release_inspection.ChangedComponents(&currEnvironmentRelease, needle)
-		if len(currChangedComponents) == 0 {
-			return &currEnvironmentRelease, nil
-		}
-
-		if minChangedComponents.Len() == 0 || len(currChangedComponents) < minChangedComponents.Len() {
-			minChangedComponents = currChangedComponents
-			minChangedRelease = &currEnvironmentRelease
-		}
-	}
-
-	return minChangedRelease, minChangedComponents
-}
-
 func ServeReleaseSummary(releaseClient client.ReleaseClient) func(c *gin.Context) {
 	h := &htmlReleaseSummary{
 		releaseClient: releaseClient,
+		clock:         clock.RealClock{},
 	}
 	return h.ServeGin
 }
@@ -7,10 +7,12 @@ import (
 	"net/http/httptest"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/openshift-online/service-status/pkg/aro/client"
 	"github.com/stretchr/testify/assert"
+	clocktesting "k8s.io/utils/clock/testing"
 )
 
 //go:embed test-artifacts
@@ -31,8 +33,12 @@ func TestReleaseSummaryHTML(t *testing.T) {
 			basicClient := client.NewFileSystemReleaseClient(testFS)
 
 			httpRouter := gin.Default()
-			httpRouter.LoadHTMLGlob("/home/deads/workspaces/service-status/src/gitub.com/openshift-online/service-status/pkg/aro/release-webserver/html-templates/*")
-			httpRouter.GET("/http/aro-hcp/summary.html", ServeReleaseSummary(basicClient))
+			httpRouter.LoadHTMLGlob("html-templates/*")
+			h := &htmlReleaseSummary{
+				releaseClient: basicClient,
+				clock:         clocktesting.NewFakePassiveClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)),
+			}
+			httpRouter.GET("/http/aro-hcp/summary.html", h.ServeGin)
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest(http.MethodGet, "/http/aro-hcp/summary.html", nil)
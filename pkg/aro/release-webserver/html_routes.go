@@ -0,0 +1,15 @@
+package release_webserver
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+)
+
+// RegisterHTMLRoutes adds every page of the site.  The router must have loaded the html-templates.
+func RegisterHTMLRoutes(router gin.IRoutes, releaseClient client.ReleaseClient) {
+	router.GET("", ServeReleaseSummary(releaseClient))
+	router.GET("/http/aro-hcp/summary.html", ServeReleaseSummary(releaseClient))
+	router.GET("/http/aro-hcp/environmentreleases/:name/summary.html", ServeEnvironmentReleaseSummary(releaseClient))
+	router.GET("/http/aro-hcp/promotion-matrix.html", ServePromotionMatrix(releaseClient))
+	router.GET("/http/aro-hcp/admin/cicoverage.html", ServeCICoverage(releaseClient))
+}
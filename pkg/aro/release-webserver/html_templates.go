@@ -0,0 +1,33 @@
+package release_webserver
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed html-templates
+var htmlTemplatesFS embed.FS
+
+// LoadHTMLTemplates loads the page templates from templatesDir.  When templatesDir is empty the templates built into
+// the binary are used, so the pages render no matter which directory the command runs from.
+func LoadHTMLTemplates(router *gin.Engine, templatesDir string) error {
+	if len(templatesDir) > 0 {
+		templates, err := template.New("").Funcs(router.FuncMap).ParseGlob(filepath.Join(templatesDir, "*"))
+		if err != nil {
+			return fmt.Errorf("failed to parse templates in %q: %w", templatesDir, err)
+		}
+		router.SetHTMLTemplate(templates)
+		return nil
+	}
+
+	templates, err := template.New("").Funcs(router.FuncMap).ParseFS(htmlTemplatesFS, "html-templates/*")
+	if err != nil {
+		return fmt.Errorf("failed to parse built in templates: %w", err)
+	}
+	router.SetHTMLTemplate(templates)
+	return nil
+}
@@ -0,0 +1,217 @@
+package release_webserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+	"k8s.io/klog/v2"
+)
+
+// SiteExporter writes every page of the site and a JSON snapshot of the API to a directory.  The snapshot is in the
+// layout NewFileSystemReleaseClient reads, so the directory can be served as is by static hosting or by
+// --filebased-api-dir.
+type SiteExporter struct {
+	releaseClient client.ReleaseClient
+	// templatesDir is empty for the templates built into the binary.
+	templatesDir string
+	outputDir    string
+}
+
+func NewSiteExporter(releaseClient client.ReleaseClient, templatesDir, outputDir string) *SiteExporter {
+	return &SiteExporter{
+		releaseClient: releaseClient,
+		templatesDir:  templatesDir,
+		outputDir:     outputDir,
+	}
+}
+
+// Export fails when the environments or releases can't be read.  Anything the release client doesn't have, like the
+// CI coverage of a snapshot that never recorded it, is left out.  Diffs that can't be computed are left out too, the
+// pages show the changed components without their change lists.
+func (e *SiteExporter) Export(ctx context.Context) error {
+	environments, err := e.releaseClient.ListEnvironments(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list environments: %w", err)
+	}
+	environmentReleases, err := e.releaseClient.ListEnvironmentReleases(ctx, status.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list environment releases: %w", err)
+	}
+	components, err := e.releaseClient.ListComponents(ctx)
+	switch {
+	case status.IsNotFound(err):
+		klog.FromContext(ctx).Info("Skipping missing components")
+		components = nil
+	case err != nil:
+		return fmt.Errorf("failed to list components: %w", err)
+	}
+
+	promotionPairs := [][2]string{}
+	if components != nil {
+		promotionPairs = promotionMatrixDiffPairs(environments, components)
+	}
+	if err := e.exportJSON(ctx, environments, environmentReleases, components, promotionPairs); err != nil {
+		return err
+	}
+	return e.exportHTML(ctx, environmentReleases, promotionPairs)
+}
+
+func (e *SiteExporter) exportJSON(ctx context.Context, environments *status.EnvironmentList, environmentReleases *status.EnvironmentReleaseList, components *status.ComponentStatusList, promotionPairs [][2]string) error {
+	logger := klog.FromContext(ctx)
+
+	if err := e.writeJSON(client.FileBasedEnvironmentsPath, environments); err != nil {
+		return err
+	}
+	if err := e.writeJSON(client.FileBasedEnvironmentReleasesPath, environmentReleases); err != nil {
+		return err
+	}
+
+	for _, environment := range environments.Items {
+		ciCoverage, err := e.releaseClient.GetCICoverage(ctx, environment.Name)
+		switch {
+		case status.IsNotFound(err):
+			logger.Info("Skipping missing CI coverage", "environment", environment.Name)
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to get CI coverage for %q: %w", environment.Name, err)
+		}
+		if err := e.writeJSON(client.FileBasedCICoveragePath(environment.Name), ciCoverage); err != nil {
+			return err
+		}
+	}
+
+	if components != nil {
+		if err := e.writeJSON(client.FileBasedComponentsPath, components); err != nil {
+			return err
+		}
+	}
+
+	for _, pair := range environmentReleaseDiffPairs(environments, environmentReleases, promotionPairs) {
+		// every page is written, so release notes and pages read from the export list every change.
+		diff, err := status.GetAllEnvironmentReleaseDiffPages(ctx, func(ctx context.Context, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+			return e.releaseClient.GetEnvironmentReleaseDiff(ctx, pair[0], pair[1], options)
+		})
+		if err != nil {
+			logger.Info("Skipping diff", "environmentRelease", pair[0], "otherEnvironmentRelease", pair[1], "err", err)
+			continue
+		}
+		if err := e.writeJSON(client.FileBasedEnvironmentReleaseDiffPath(pair[0], pair[1]), diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// environmentReleaseDiffPairs are the diffs the exported pages show: each release against the previous release of its
+// environment, and the promotion matrix comparisons.  The pages read them when the export is served by
+// --filebased-api-dir.
+func environmentReleaseDiffPairs(environments *status.EnvironmentList, environmentReleases *status.EnvironmentReleaseList, promotionPairs [][2]string) [][2]string {
+	environmentToNames := map[string][]string{}
+	for _, environmentRelease := range environmentReleases.Items {
+		environmentToNames[environmentRelease.Environment] = append(environmentToNames[environmentRelease.Environment], environmentRelease.Name)
+	}
+
+	ret := [][2]string{}
+	for _, environment := range environments.Items {
+		names := environmentToNames[environment.Name]
+		for i := 0; i+1 < len(names); i++ {
+			ret = append(ret, [2]string{names[i], names[i+1]})
+		}
+	}
+	for _, pair := range promotionPairs {
+		if !slices.Contains(ret, pair) {
+			ret = append(ret, pair)
+		}
+	}
+	return ret
+}
+
+// staticPromotionDiffPath is where the page at promotionDiffURL is written.  Static hosting ignores query parameters,
+// so each comparison gets its own file.
+func staticPromotionDiffPath(environmentReleaseName, fromEnvironmentReleaseName string) string {
+	return path.Join("http/aro-hcp/environmentreleases", environmentReleaseName, "summary-from-"+fromEnvironmentReleaseName+".html")
+}
+
+func staticPromotionDiffURL(environmentReleaseName, fromEnvironmentReleaseName string) string {
+	return "/http/aro-hcp/environmentreleases/" + url.PathEscape(environmentReleaseName) + "/summary-from-" + url.PathEscape(fromEnvironmentReleaseName) + ".html"
+}
+
+func (e *SiteExporter) exportHTML(ctx context.Context, environmentReleases *status.EnvironmentReleaseList, promotionPairs [][2]string) error {
+	logger := klog.FromContext(ctx)
+
+	httpRouter := gin.New()
+	if err := LoadHTMLTemplates(httpRouter, e.templatesDir); err != nil {
+		return err
+	}
+	RegisterHTMLRoutes(httpRouter, e.releaseClient)
+
+	pages := map[string]string{
+		"/":                                   "index.html",
+		"/http/aro-hcp/summary.html":          "http/aro-hcp/summary.html",
+		"/http/aro-hcp/promotion-matrix.html": "http/aro-hcp/promotion-matrix.html",
+		"/http/aro-hcp/admin/cicoverage.html": "http/aro-hcp/admin/cicoverage.html",
+	}
+	for _, environmentRelease := range environmentReleases.Items {
+		pages["/http/aro-hcp/environmentreleases/"+url.PathEscape(environmentRelease.Name)+"/summary.html"] =
+			path.Join("http/aro-hcp/environmentreleases", environmentRelease.Name, "summary.html")
+	}
+	// the links to the comparisons are rewritten to the files they are written to.
+	linkReplacements := []string{}
+	for _, pair := range promotionPairs {
+		pages[promotionDiffURL(pair[0], pair[1])] = staticPromotionDiffPath(pair[0], pair[1])
+		linkReplacements = append(linkReplacements, promotionDiffURL(pair[0], pair[1]), staticPromotionDiffURL(pair[0], pair[1]))
+	}
+	linkRewriter := strings.NewReplacer(linkReplacements...)
+
+	for pageURL, pagePath := range pages {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request for %q: %w", pageURL, err)
+		}
+		w := httptest.NewRecorder()
+		httpRouter.ServeHTTP(w, req)
+		switch {
+		case w.Code == http.StatusNotFound:
+			logger.Info("Skipping page without data", "page", pageURL, "response", w.Body.String())
+			continue
+		case w.Code != http.StatusOK:
+			return fmt.Errorf("failed to render %q: %d %s", pageURL, w.Code, w.Body.String())
+		}
+		if err := e.writeFile(pagePath, []byte(linkRewriter.Replace(w.Body.String()))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *SiteExporter) writeJSON(relativePath string, obj any) error {
+	// indented like the server's responses.
+	content, err := json.MarshalIndent(obj, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %q: %w", relativePath, err)
+	}
+	return e.writeFile(relativePath, append(content, '\n'))
+}
+
+func (e *SiteExporter) writeFile(relativePath string, content []byte) error {
+	filePath := filepath.Join(e.outputDir, filepath.FromSlash(relativePath))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %q: %w", filePath, err)
+	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %q: %w", filePath, err)
+	}
+	return nil
+}
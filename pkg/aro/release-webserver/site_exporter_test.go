@@ -0,0 +1,181 @@
+package release_webserver
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+// diffingReleaseClient adds diffs to the test artifacts, which have none.
+type diffingReleaseClient struct {
+	client.ReleaseClient
+}
+
+func (c diffingReleaseClient) GetEnvironmentReleaseDiff(ctx context.Context, environmentReleaseName, otherEnvironmentReleaseName string, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+	return &status.EnvironmentReleaseDiff{
+		TypeMeta:                    status.TypeMeta{Kind: "EnvironmentReleaseDiff", APIVersion: "service-status.hcm.openshift.io/v1"},
+		Name:                        environmentReleaseName,
+		OtherEnvironmentReleaseName: otherEnvironmentReleaseName,
+		DifferentComponents:         map[string]*status.ComponentDiff{},
+	}, nil
+}
+
+func TestSiteExporter(t *testing.T) {
+	ctx := context.Background()
+	testFS, err := fs.Sub(testArtifacts, "test-artifacts/ReleaseSummaryHTML/basic")
+	require.NoError(t, err)
+	sourceClient := diffingReleaseClient{ReleaseClient: client.NewFileSystemReleaseClient(testFS)}
+
+	outputDir := t.TempDir()
+	require.NoError(t, NewSiteExporter(sourceClient, "", outputDir).Export(ctx))
+
+	exportedClient := client.NewFileSystemReleaseClient(os.DirFS(outputDir))
+	sourceEnvironmentReleases, err := sourceClient.ListEnvironmentReleases(ctx, status.ListOptions{})
+	require.NoError(t, err)
+	exportedEnvironmentReleases, err := exportedClient.ListEnvironmentReleases(ctx, status.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, sourceEnvironmentReleases, exportedEnvironmentReleases)
+
+	newest, previous := sourceEnvironmentReleases.Items[0], sourceEnvironmentReleases.Items[1]
+	diff, err := exportedClient.GetEnvironmentReleaseDiff(ctx, newest.Name, previous.Name, status.DiffOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, previous.Name, diff.OtherEnvironmentReleaseName)
+
+	for _, page := range []string{
+		"index.html",
+		"http/aro-hcp/summary.html",
+		filepath.Join("http/aro-hcp/environmentreleases", newest.Name, "summary.html"),
+	} {
+		assert.FileExists(t, filepath.Join(outputDir, page))
+	}
+	// the test artifacts have no components or CI coverage, so neither they nor their pages are exported.
+	assert.NoFileExists(t, filepath.Join(outputDir, client.FileBasedComponentsPath))
+	assert.NoFileExists(t, filepath.Join(outputDir, "http/aro-hcp/promotion-matrix.html"))
+}
+
+// promotingReleaseClient adds components to the test artifacts, with stg behind int.
+type promotingReleaseClient struct {
+	diffingReleaseClient
+	intName, stgName string
+}
+
+func (c promotingReleaseClient) ListComponents(ctx context.Context) (*status.ComponentStatusList, error) {
+	return &status.ComponentStatusList{Items: []status.ComponentStatus{
+		{
+			Name: "Backend",
+			Environments: []status.ComponentEnvironmentStatus{
+				{Environment: "int", EnvironmentReleaseName: c.intName, Image: status.ContainerImage{Digest: "sha256:new"}},
+				{Environment: "stg", EnvironmentReleaseName: c.stgName, Image: status.ContainerImage{Digest: "sha256:old"}, PreviousEnvironment: "int"},
+			},
+		},
+	}}, nil
+}
+
+func TestSiteExporterPromotionMatrix(t *testing.T) {
+	ctx := context.Background()
+	testFS, err := fs.Sub(testArtifacts, "test-artifacts/ReleaseSummaryHTML/basic")
+	require.NoError(t, err)
+	sourceClient := diffingReleaseClient{ReleaseClient: client.NewFileSystemReleaseClient(testFS)}
+	intReleases, err := sourceClient.ListEnvironmentReleasesForEnvironment(ctx, "int", status.ListOptions{})
+	require.NoError(t, err)
+	stgReleases, err := sourceClient.ListEnvironmentReleasesForEnvironment(ctx, "stg", status.ListOptions{})
+	require.NoError(t, err)
+	intName, stgName := intReleases.Items[0].Name, stgReleases.Items[1].Name
+
+	outputDir := t.TempDir()
+	require.NoError(t, NewSiteExporter(promotingReleaseClient{diffingReleaseClient: sourceClient, intName: intName, stgName: stgName}, "", outputDir).Export(ctx))
+
+	// the comparison is its own page and the matrix links to it.
+	promotionMatrix, err := os.ReadFile(filepath.Join(outputDir, "http/aro-hcp/promotion-matrix.html"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(promotionMatrix), "?from=")
+	assert.Contains(t, string(promotionMatrix), `href="`+staticPromotionDiffURL(intName, stgName)+`"`)
+	assert.FileExists(t, filepath.Join(outputDir, filepath.FromSlash(staticPromotionDiffPath(intName, stgName))))
+
+	// the diff backing the comparison is exported for --filebased-api-dir.
+	exportedClient := client.NewFileSystemReleaseClient(os.DirFS(outputDir))
+	diff, err := exportedClient.GetEnvironmentReleaseDiff(ctx, intName, stgName, status.DiffOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, stgName, diff.OtherEnvironmentReleaseName)
+}
+
+func TestEnvironmentReleaseDiffPairs(t *testing.T) {
+	environments := &status.EnvironmentList{Items: []status.Environment{{Name: "int"}, {Name: "stg"}}}
+	environmentReleases := &status.EnvironmentReleaseList{Items: []status.EnvironmentRelease{
+		{Name: "int---3", Environment: "int"},
+		{Name: "int---2", Environment: "int"},
+		{Name: "int---1", Environment: "int"},
+		{Name: "stg---2", Environment: "stg"},
+	}}
+
+	promotionPairs := [][2]string{{"int---3", "stg---2"}, {"int---2", "int---1"}}
+
+	assert.Equal(t, [][2]string{
+		{"int---3", "int---2"},
+		{"int---2", "int---1"},
+		{"int---3", "stg---2"},
+	}, environmentReleaseDiffPairs(environments, environmentReleases, promotionPairs))
+}
+
+// TestSnapshotAPIRoutes serves an export through the JSON routes, as release-website --filebased-api-dir does.
+func TestSnapshotAPIRoutes(t *testing.T) {
+	ctx := context.Background()
+	testFS, err := fs.Sub(testArtifacts, "test-artifacts/ReleaseSummaryHTML/basic")
+	require.NoError(t, err)
+	sourceClient := diffingReleaseClient{ReleaseClient: client.NewFileSystemReleaseClient(testFS)}
+	outputDir := t.TempDir()
+	require.NoError(t, NewSiteExporter(sourceClient, "", outputDir).Export(ctx))
+
+	fakeClock := clocktesting.NewFakeClock(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC))
+	accessor := release_inspection.NewCachingReleaseAccessor(release_inspection.NewSnapshotReleaseAccessor(os.DirFS(outputDir)), nil, fakeClock)
+	httpRouter := gin.New()
+	for _, route := range NewAPIRoutes(
+		accessor,
+		release_inspection.NewEnvironmentReleaseWatcher(accessor, time.Minute, fakeClock),
+		release_inspection.NewRepositoryRefresher(nil, time.Hour, fakeClock),
+		nil,
+	) {
+		httpRouter.GET(route.Path, route.Handler)
+	}
+	get := func(t *testing.T, requestURL string, into any) {
+		w := httptest.NewRecorder()
+		httpRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, requestURL, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), into))
+	}
+
+	expectedIntReleases, err := sourceClient.ListEnvironmentReleasesForEnvironment(ctx, "int", status.ListOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, expectedIntReleases.Items)
+	intReleases := &status.EnvironmentReleaseList{}
+	get(t, "/api/aro-hcp/environments/int/environmentreleases", intReleases)
+	assert.Equal(t, expectedIntReleases.Items, intReleases.Items)
+
+	newest, previous := expectedIntReleases.Items[0], expectedIntReleases.Items[1]
+	environmentRelease := &status.EnvironmentRelease{}
+	get(t, "/api/aro-hcp/environmentreleases/"+url.PathEscape(newest.Name), environmentRelease)
+	assert.Equal(t, newest, *environmentRelease)
+
+	diff := &status.EnvironmentReleaseDiff{}
+	get(t, "/api/aro-hcp/environmentreleases/"+url.PathEscape(newest.Name)+"/diff/"+url.PathEscape(previous.Name), diff)
+	assert.Equal(t, previous.Name, diff.OtherEnvironmentReleaseName)
+
+	w := httptest.NewRecorder()
+	httpRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/aro-hcp/environments/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
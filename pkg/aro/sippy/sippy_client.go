@@ -4,134 +4,252 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/openshift-online/service-status/pkg/metrics"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 )
 
-type SippyQueryStruct struct {
-	Items        []SippyQueryItem `json:"items"`
-	LinkOperator string           `json:"linkOperator"`
+const DefaultBaseURL = "https://sippy.dptools.openshift.org"
+
+type Client interface {
+	// ListJobRuns reads every page of job runs matching options.
+	ListJobRuns(ctx context.Context, options ListJobRunsOptions) ([]JobRun, error)
 }
 
-type SippyQueryItem struct {
-	ColumnField   string `json:"columnField"`
-	Not           bool   `json:"not"`
-	OperatorValue string `json:"operatorValue"`
-	Value         string `json:"value"`
+type ListJobRunsOptions struct {
+	// Release is the name of the release in sippy, NOT ARO HCP.  Sippy's releases are our environments.
+	Release string
+	// Filter is sent as sippy's filter parameter.  The time range is added to it, so it must use the "and" link operator
+	// when Since or Until is set.
+	Filter *SippyQueryStruct
+	// Since and Until bound the job run timestamps when they are not zero.
+	Since time.Time
+	Until time.Time
 }
 
-type JobRunPaginationResult struct {
-	Rows      []JobRun `json:"rows"`
-	PageSize  int64    `json:"page_size"`
-	Page      int      `json:"page"`
-	TotalRows int64    `json:"total_rows"`
+type ClientOptions struct {
+	// BaseURL defaults to DefaultBaseURL.
+	BaseURL string
+	// InsecureSkipTLSVerify is only acceptable because we don't send anything sensitive.  Fix this before you do.
+	InsecureSkipTLSVerify bool
+	// PageSize defaults to 1000.
+	PageSize int
+	// MaxRetries is how many times a failed page is retried.  Defaults to 4.
+	MaxRetries int
+	// InitialBackoff doubles on every retry.  Defaults to one second.
+	InitialBackoff time.Duration
+
+	Clock clock.Clock
 }
 
-type JobRun struct {
-	ID                    int              `json:"id"`
-	BriefName             string           `json:"brief_name"`
-	Variants              []string         `json:"variants" gorm:"type:text[]"`
-	Tags                  []string         `json:"tags" gorm:"type:text[]"`
-	TestGridURL           string           `json:"test_grid_url"`
-	ProwID                uint             `json:"prow_id"`
-	Job                   string           `json:"job"`
-	Cluster               string           `json:"cluster"`
-	URL                   string           `json:"url"`
-	TestFlakes            int              `json:"test_flakes"`
-	FlakedTestNames       []string         `json:"flaked_test_names" gorm:"type:text[]"`
-	TestFailures          int              `json:"test_failures"`
-	FailedTestNames       []string         `json:"failed_test_names" gorm:"type:text[]"`
-	Failed                bool             `json:"failed"`
-	InfrastructureFailure bool             `json:"infrastructure_failure"`
-	KnownFailure          bool             `json:"known_failure"`
-	Succeeded             bool             `json:"succeeded"`
-	Timestamp             int64            `json:"timestamp"`
-	OverallResult         JobOverallResult `json:"overall_result"`
-	PullRequestOrg        string           `json:"pull_request_org"`
-	PullRequestRepo       string           `json:"pull_request_repo"`
-	PullRequestLink       string           `json:"pull_request_link"`
-	PullRequestSHA        string           `json:"pull_request_sha"`
-	PullRequestAuthor     string           `json:"pull_request_author"`
+type client struct {
+	baseURL        string
+	pageSize       int
+	maxRetries     int
+	initialBackoff time.Duration
+	httpClient     *http.Client
+	clock          clock.Clock
 }
-type JobOverallResult string
-
-const (
-	JobSucceeded             JobOverallResult = "S"
-	JobRunning               JobOverallResult = "R"
-	JobInfrastructureFailure JobOverallResult = "N"
-	JobInstallFailure        JobOverallResult = "I"
-	JobUpgradeFailure        JobOverallResult = "U"
-	JobTestFailure           JobOverallResult = "F"
-	JobFailureBeforeSetup    JobOverallResult = "n"
-	JobAborted               JobOverallResult = "A"
-	JobUnknown               JobOverallResult = "f"
-)
 
-// sippyRelease is the name of the release in sippy, NOT ARO HCP.  Sippy's releases are our environments.
-func ListJobRunsForEnvironment(ctx context.Context, sippyRelease string) ([]JobRun, error) {
-	defaultTransport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig: &tls.Config{
-			// we aren't sending anything sensitive.  Fix this before you do.
-			InsecureSkipVerify: true,
+func NewClient(options ClientOptions) Client {
+	ret := &client{
+		baseURL:        options.BaseURL,
+		pageSize:       options.PageSize,
+		maxRetries:     options.MaxRetries,
+		initialBackoff: options.InitialBackoff,
+		clock:          options.Clock,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				ForceAttemptHTTP2:     true,
+				MaxIdleConns:          100,
+				IdleConnTimeout:       90 * time.Second,
+				TLSHandshakeTimeout:   10 * time.Second,
+				ExpectContinueTimeout: 1 * time.Second,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: options.InsecureSkipTLSVerify,
+				},
+			},
 		},
 	}
-	sippyClient := &http.Client{
-		Timeout:   30 * time.Second,
-		Transport: defaultTransport,
+	if len(ret.baseURL) == 0 {
+		ret.baseURL = DefaultBaseURL
 	}
-
-	currURL := &url.URL{
-		Scheme: "https",
-		Host:   "sippy.dptools.openshift.org",
-		Path:   "api/jobs/runs",
+	if ret.pageSize <= 0 {
+		ret.pageSize = 1000
+	}
+	if ret.maxRetries <= 0 {
+		ret.maxRetries = 4
+	}
+	if ret.initialBackoff <= 0 {
+		ret.initialBackoff = time.Second
 	}
-	queryParams := currURL.Query()
-	queryParams.Add("release", sippyRelease)
-	queryParams.Add("period", "default")
-	// have a look in openshift/api to see how we can further filter if we desire
-	//filterJSON, err := json.Marshal(currQuery)
-	//if err != nil {
-	//	return nil, err
-	//}
-	//queryParams.Add("filter", string(filterJSON))
-	currURL.RawQuery = queryParams.Encode()
+	if ret.clock == nil {
+		ret.clock = clock.RealClock{}
+	}
+	return ret
+}
+
+func (c *client) ListJobRuns(ctx context.Context, options ListJobRunsOptions) ([]JobRun, error) {
+	logger := klog.LoggerWithValues(klog.FromContext(ctx), "sippyRelease", options.Release)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, currURL.String(), nil)
+	filter, err := filterWithTimeRange(options)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := sippyClient.Do(req)
+	ret := []JobRun{}
+	for page := 0; ; page++ {
+		currURL, err := url.Parse(c.baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sippy URL: %w", err)
+		}
+		currURL = currURL.JoinPath("api", "jobs", "runs")
+		queryParams := currURL.Query()
+		queryParams.Add("release", options.Release)
+		queryParams.Add("sortField", "timestamp")
+		queryParams.Add("sort", "desc")
+		queryParams.Add("perPage", strconv.Itoa(c.pageSize))
+		queryParams.Add("page", strconv.Itoa(page))
+		if filter != nil {
+			filterJSON, err := json.Marshal(filter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal filter: %w", err)
+			}
+			queryParams.Add("filter", string(filterJSON))
+		}
+		currURL.RawQuery = queryParams.Encode()
+
+		paginatedJobRuns, err := c.getPageWithRetries(ctx, currURL.String())
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, paginatedJobRuns.Rows...)
+
+		if len(paginatedJobRuns.Rows) == 0 || int64(len(ret)) >= paginatedJobRuns.TotalRows {
+			break
+		}
+	}
+	logger.Info("Listed job runs", "count", len(ret))
+
+	return ret, nil
+}
+
+func (c *client) getPageWithRetries(ctx context.Context, pageURL string) (*JobRunPaginationResult, error) {
+	logger := klog.FromContext(ctx)
+
+	backoff := c.initialBackoff
+	for attempt := 0; ; attempt++ {
+		startTime := time.Now()
+		ret, err := c.getPage(ctx, pageURL)
+		metrics.ObserveExternalCall("sippy", "list_job_runs", startTime, err)
+		if err == nil {
+			return ret, nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= c.maxRetries {
+			return nil, err
+		}
+
+		logger.Info("Retrying sippy request", "url", pageURL, "attempt", attempt+1, "backoff", backoff, "err", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.clock.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// permanentError is returned for responses that won't succeed on retry.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func (c *client) getPage(ctx context.Context, pageURL string) (*JobRunPaginationResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, &permanentError{err: fmt.Errorf("failed to create request: %w", err)}
 	}
-	if response.StatusCode < 200 || response.StatusCode > 299 {
-		return nil, fmt.Errorf("error getting sippy results (status=%d) for: %v", response.StatusCode, currURL.String())
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do request: %w", err)
 	}
+	defer response.Body.Close()
 	queryResultBytes, err := io.ReadAll(response.Body)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch {
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return nil, fmt.Errorf("error getting sippy results (status=%d) for: %v", response.StatusCode, pageURL)
+	case response.StatusCode < 200 || response.StatusCode > 299:
+		return nil, &permanentError{err: fmt.Errorf("error getting sippy results (status=%d) for: %v: %v", response.StatusCode, pageURL, string(queryResultBytes))}
 	}
-	response.Body.Close()
 
 	paginatedJobRuns := &JobRunPaginationResult{}
 	if err := json.Unmarshal(queryResultBytes, paginatedJobRuns); err != nil {
-		return nil, fmt.Errorf("error parsing sippy results (status=%d) for: %v", response.StatusCode, err)
+		return nil, &permanentError{err: fmt.Errorf("error parsing sippy results for %v: %w", pageURL, err)}
 	}
-	if paginatedJobRuns.TotalRows != paginatedJobRuns.PageSize {
-		klog.InfoS("more than one page of results")
+	return paginatedJobRuns, nil
+}
+
+// filterWithTimeRange adds the time range from options to a copy of its filter.
+func filterWithTimeRange(options ListJobRunsOptions) (*SippyQueryStruct, error) {
+	if options.Since.IsZero() && options.Until.IsZero() {
+		return options.Filter, nil
+	}
+
+	ret := &SippyQueryStruct{LinkOperator: "and"}
+	if options.Filter != nil {
+		if len(options.Filter.Items) > 1 && options.Filter.LinkOperator != "and" {
+			return nil, fmt.Errorf("filter must use the \"and\" link operator to be combined with a time range, not %q", options.Filter.LinkOperator)
+		}
+		ret.Items = append(ret.Items, options.Filter.Items...)
+	}
+	if !options.Since.IsZero() {
+		ret.Items = append(ret.Items, SippyQueryItem{
+			ColumnField:   "timestamp",
+			OperatorValue: ">=",
+			Value:         strconv.FormatInt(options.Since.UnixMilli(), 10),
+		})
 	}
+	if !options.Until.IsZero() {
+		ret.Items = append(ret.Items, SippyQueryItem{
+			ColumnField:   "timestamp",
+			OperatorValue: "<",
+			Value:         strconv.FormatInt(options.Until.UnixMilli(), 10),
+		})
+	}
+	return ret, nil
+}
+
+type dummyClient struct{}
+
+// NewDummyClient returns no job runs.  It is useful when sippy is unreachable, for instance in tests.
+func NewDummyClient() Client {
+	return &dummyClient{}
+}
 
-	return paginatedJobRuns.Rows, nil
+func (c *dummyClient) ListJobRuns(ctx context.Context, options ListJobRunsOptions) ([]JobRun, error) {
+	return nil, nil
 }
@@ -0,0 +1,77 @@
+package sippy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListJobRuns(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api/jobs/runs", r.URL.Path)
+		assert.Equal(t, "aro-stg", r.URL.Query().Get("release"))
+		assert.Equal(t, "2", r.URL.Query().Get("perPage"))
+
+		filter := &SippyQueryStruct{}
+		require.NoError(t, json.Unmarshal([]byte(r.URL.Query().Get("filter")), filter))
+		assert.Equal(t, &SippyQueryStruct{
+			LinkOperator: "and",
+			Items: []SippyQueryItem{
+				{ColumnField: "name", OperatorValue: "contains", Value: "e2e"},
+				{ColumnField: "timestamp", OperatorValue: ">=", Value: "1754352000000"},
+			},
+		}, filter)
+
+		// the first attempt at the second page fails, the retry must succeed.
+		if requests == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		require.NoError(t, err)
+		rows := []JobRun{}
+		for i := page * 2; i < min(page*2+2, 3); i++ {
+			rows = append(rows, JobRun{ID: i})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(JobRunPaginationResult{Rows: rows, PageSize: 2, Page: page, TotalRows: 3}))
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, PageSize: 2, InitialBackoff: time.Millisecond})
+	actual, err := client.ListJobRuns(context.Background(), ListJobRunsOptions{
+		Release: "aro-stg",
+		Filter: &SippyQueryStruct{
+			Items: []SippyQueryItem{{ColumnField: "name", OperatorValue: "contains", Value: "e2e"}},
+		},
+		Since: time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []JobRun{{ID: 0}, {ID: 1}, {ID: 2}}, actual)
+	assert.Equal(t, 3, requests)
+}
+
+func TestListJobRunsDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "bad filter")
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, InitialBackoff: time.Millisecond})
+	_, err := client.ListJobRuns(context.Background(), ListJobRunsOptions{Release: "aro-stg"})
+	assert.ErrorContains(t, err, "bad filter")
+	assert.Equal(t, 1, requests)
+}
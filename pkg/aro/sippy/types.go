@@ -0,0 +1,60 @@
+package sippy
+
+type SippyQueryStruct struct {
+	Items        []SippyQueryItem `json:"items"`
+	LinkOperator string           `json:"linkOperator"`
+}
+
+type SippyQueryItem struct {
+	ColumnField   string `json:"columnField"`
+	Not           bool   `json:"not"`
+	OperatorValue string `json:"operatorValue"`
+	Value         string `json:"value"`
+}
+
+type JobRunPaginationResult struct {
+	Rows      []JobRun `json:"rows"`
+	PageSize  int64    `json:"page_size"`
+	Page      int      `json:"page"`
+	TotalRows int64    `json:"total_rows"`
+}
+
+type JobRun struct {
+	ID                    int              `json:"id"`
+	BriefName             string           `json:"brief_name"`
+	Variants              []string         `json:"variants" gorm:"type:text[]"`
+	Tags                  []string         `json:"tags" gorm:"type:text[]"`
+	TestGridURL           string           `json:"test_grid_url"`
+	ProwID                uint             `json:"prow_id"`
+	Job                   string           `json:"job"`
+	Cluster               string           `json:"cluster"`
+	URL                   string           `json:"url"`
+	TestFlakes            int              `json:"test_flakes"`
+	FlakedTestNames       []string         `json:"flaked_test_names" gorm:"type:text[]"`
+	TestFailures          int              `json:"test_failures"`
+	FailedTestNames       []string         `json:"failed_test_names" gorm:"type:text[]"`
+	Failed                bool             `json:"failed"`
+	InfrastructureFailure bool             `json:"infrastructure_failure"`
+	KnownFailure          bool             `json:"known_failure"`
+	Succeeded             bool             `json:"succeeded"`
+	Timestamp             int64            `json:"timestamp"`
+	OverallResult         JobOverallResult `json:"overall_result"`
+	PullRequestOrg        string           `json:"pull_request_org"`
+	PullRequestRepo       string           `json:"pull_request_repo"`
+	PullRequestLink       string           `json:"pull_request_link"`
+	PullRequestSHA        string           `json:"pull_request_sha"`
+	PullRequestAuthor     string           `json:"pull_request_author"`
+}
+type JobOverallResult string
+
+const (
+	JobSucceeded             JobOverallResult = "S"
+	JobRunning               JobOverallResult = "R"
+	JobInfrastructureFailure JobOverallResult = "N"
+	JobInstallFailure        JobOverallResult = "I"
+	JobUpgradeFailure        JobOverallResult = "U"
+	JobTestFailure           JobOverallResult = "F"
+	JobFailureBeforeSetup    JobOverallResult = "n"
+	JobAborted               JobOverallResult = "A"
+	JobUnknown               JobOverallResult = "f"
+)
@@ -3,6 +3,8 @@ package arohcp
 import (
 	"fmt"
 
+	"github.com/openshift-online/service-status/pkg/cmd/aro/arohcp/export"
+	release_notes "github.com/openshift-online/service-status/pkg/cmd/aro/arohcp/release-notes"
 	release_website "github.com/openshift-online/service-status/pkg/cmd/aro/arohcp/release-website"
 	"github.com/openshift-online/service-status/pkg/util"
 	"github.com/spf13/cobra"
@@ -21,6 +23,8 @@ func NewHCPCommand(streams util.IOStreams) *cobra.Command {
 
 	cmd.AddCommand(
 		release_website.NewReleaseWebsiteCommand(streams),
+		release_notes.NewReleaseNotesCommand(streams),
+		export.NewExportCommand(streams),
 	)
 
 	return cmd
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/openshift-online/service-status/pkg/aro/client"
+	"github.com/openshift-online/service-status/pkg/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+)
+
+// ExportFlags gets bound to cobra commands and arguments.  It is used to validate input and then produce
+// the Options struct.  Options struct is intended to be embeddable and re-useable without cobra.
+type ExportFlags struct {
+	ServerURL       string
+	FileBasedAPIDir string
+
+	OutputDir    string
+	TemplatesDir string
+
+	util.IOStreams
+}
+
+func NewExportCommand(streams util.IOStreams) *cobra.Command {
+	f := NewExportFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:   "export --server-url <url> --output-dir <dir>",
+		Short: "Write the HTML site and a JSON snapshot of the API to a directory",
+		Long: `Write the HTML site and a JSON snapshot of the API to a directory.
+
+The directory can be published to static hosting, or served with release-website --filebased-api-dir.  Static
+hosting can't serve query parameters, so each comparison linked from the promotion matrix is written to its own page
+and the links point to those pages.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			logger := klog.FromContext(ctx)
+
+			err := f.Validate()
+			if err != nil {
+				return err
+			}
+
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+
+			return o.Run(klog.NewContext(context.TODO(), klog.LoggerWithName(logger, "aro hcp export")))
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func NewExportFlags(streams util.IOStreams) *ExportFlags {
+	return &ExportFlags{
+		IOStreams: streams,
+	}
+}
+
+func (f *ExportFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.ServerURL, "server-url", f.ServerURL, "The URL of a running release-website server to read releases from.")
+	flags.StringVar(&f.FileBasedAPIDir, "filebased-api-dir", f.FileBasedAPIDir, "The directory to read canned responses.")
+
+	flags.StringVar(&f.OutputDir, "output-dir", f.OutputDir, "The directory to write the site and the snapshot to.")
+	flags.StringVar(&f.TemplatesDir, "templates-dir", f.TemplatesDir, "The directory of the HTML templates.  The templates built into the binary are used when it isn't set.")
+}
+
+func (f *ExportFlags) Validate() error {
+	switch {
+	case len(f.ServerURL) > 0 && len(f.FileBasedAPIDir) > 0:
+		return fmt.Errorf("only one of --server-url and --filebased-api-dir can be specified")
+	case len(f.ServerURL) == 0 && len(f.FileBasedAPIDir) == 0:
+		return fmt.Errorf("one of --server-url and --filebased-api-dir must be specified")
+	}
+
+	if len(f.OutputDir) == 0 {
+		return fmt.Errorf("--output-dir must be specified")
+	}
+	return nil
+}
+
+func (f *ExportFlags) ToOptions() (*ExportOptions, error) {
+	var releaseClient client.ReleaseClient
+	switch {
+	case len(f.FileBasedAPIDir) > 0:
+		releaseClient = client.NewFileSystemReleaseClient(os.DirFS(f.FileBasedAPIDir))
+	default:
+		releaseClient = client.NewBasicReleaseClient(f.ServerURL)
+	}
+
+	return &ExportOptions{
+		ReleaseClient: releaseClient,
+		OutputDir:     f.OutputDir,
+		TemplatesDir:  f.TemplatesDir,
+
+		IOStreams: f.IOStreams,
+	}, nil
+}
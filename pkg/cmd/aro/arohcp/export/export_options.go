@@ -0,0 +1,32 @@
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+	release_webserver "github.com/openshift-online/service-status/pkg/aro/release-webserver"
+	"github.com/openshift-online/service-status/pkg/util"
+)
+
+type ExportOptions struct {
+	ReleaseClient client.ReleaseClient
+
+	OutputDir    string
+	TemplatesDir string
+
+	util.IOStreams
+}
+
+func (o *ExportOptions) Run(ctx context.Context) error {
+	// pages are rendered in process, gin's debug output would only be noise.
+	gin.SetMode(gin.ReleaseMode)
+
+	exporter := release_webserver.NewSiteExporter(o.ReleaseClient, o.TemplatesDir, o.OutputDir)
+	if err := exporter.Export(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "exported to %s\n", o.OutputDir)
+	return nil
+}
@@ -0,0 +1,115 @@
+package release_notes
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/openshift-online/service-status/pkg/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+)
+
+// ReleaseNotesFlags gets bound to cobra commands and arguments.  It is used to validate input and then produce
+// the Options struct.  Options struct is intended to be embeddable and re-useable without cobra.
+type ReleaseNotesFlags struct {
+	ServerURL       string
+	FileBasedAPIDir string
+
+	From   string
+	To     string
+	Format string
+
+	util.IOStreams
+}
+
+func NewReleaseNotesCommand(streams util.IOStreams) *cobra.Command {
+	f := NewReleaseNotesFlags(streams)
+
+	cmd := &cobra.Command{
+		Use:           "release-notes --from <environmentRelease> --to <environmentRelease>",
+		Short:         "Write release notes for the changes between two environment releases",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			logger := klog.FromContext(ctx)
+
+			err := f.Validate()
+			if err != nil {
+				return err
+			}
+
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+
+			return o.Run(klog.NewContext(context.TODO(), klog.LoggerWithName(logger, "aro hcp release-notes")))
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func NewReleaseNotesFlags(streams util.IOStreams) *ReleaseNotesFlags {
+	return &ReleaseNotesFlags{
+		IOStreams: streams,
+		Format:    "markdown",
+	}
+}
+
+func (f *ReleaseNotesFlags) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.ServerURL, "server-url", f.ServerURL, "The URL of a running release-website server to read releases from.")
+	flags.StringVar(&f.FileBasedAPIDir, "filebased-api-dir", f.FileBasedAPIDir, "The directory to read canned responses.")
+
+	flags.StringVar(&f.From, "from", f.From, "The environment release to start from, for instance stg---2025-07-31T15:13:04+02:00-dbe7a.")
+	flags.StringVar(&f.To, "to", f.To, "The environment release to end at, for instance prod---2025-08-04T14:40:09-04:00-53c44.")
+	flags.StringVar(&f.Format, "format", f.Format, "The output format: markdown or text.")
+}
+
+func (f *ReleaseNotesFlags) Validate() error {
+	switch {
+	case len(f.ServerURL) > 0 && len(f.FileBasedAPIDir) > 0:
+		return fmt.Errorf("only one of --server-url and --filebased-api-dir can be specified")
+	case len(f.ServerURL) == 0 && len(f.FileBasedAPIDir) == 0:
+		return fmt.Errorf("one of --server-url and --filebased-api-dir must be specified")
+	}
+
+	if _, _, ok := status.SplitEnvironmentReleaseName(f.From); !ok {
+		return fmt.Errorf("--from must be in format <environmentName>---<releaseName>")
+	}
+	if _, _, ok := status.SplitEnvironmentReleaseName(f.To); !ok {
+		return fmt.Errorf("--to must be in format <environmentName>---<releaseName>")
+	}
+	if err := release_inspection.ValidateReleaseNotesFormat(f.Format); err != nil {
+		return fmt.Errorf("--format: %w", err)
+	}
+	return nil
+}
+
+func (f *ReleaseNotesFlags) ToOptions() (*ReleaseNotesOptions, error) {
+	var releaseClient client.ReleaseClient
+	switch {
+	case len(f.FileBasedAPIDir) > 0:
+		releaseClient = client.NewFileSystemReleaseClient(os.DirFS(f.FileBasedAPIDir))
+	default:
+		releaseClient = client.NewBasicReleaseClient(f.ServerURL)
+	}
+
+	return &ReleaseNotesOptions{
+		ReleaseClient:              releaseClient,
+		FromEnvironmentReleaseName: f.From,
+		ToEnvironmentReleaseName:   f.To,
+		Format:                     f.Format,
+
+		IOStreams: f.IOStreams,
+	}, nil
+}
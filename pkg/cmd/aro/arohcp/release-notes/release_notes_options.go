@@ -0,0 +1,46 @@
+package release_notes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-online/service-status/pkg/apis/status"
+	"github.com/openshift-online/service-status/pkg/aro/client"
+	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/openshift-online/service-status/pkg/util"
+)
+
+type ReleaseNotesOptions struct {
+	ReleaseClient client.ReleaseClient
+
+	FromEnvironmentReleaseName string
+	ToEnvironmentReleaseName   string
+	Format                     string
+
+	util.IOStreams
+}
+
+func (o *ReleaseNotesOptions) Run(ctx context.Context) error {
+	fromEnvironmentName, fromReleaseName, _ := status.SplitEnvironmentReleaseName(o.FromEnvironmentReleaseName)
+	toEnvironmentName, toReleaseName, _ := status.SplitEnvironmentReleaseName(o.ToEnvironmentReleaseName)
+
+	fromEnvironmentRelease, err := o.ReleaseClient.GetEnvironmentRelease(ctx, fromEnvironmentName, fromReleaseName)
+	if err != nil {
+		return fmt.Errorf("failed to get --from environment release: %w", err)
+	}
+	toEnvironmentRelease, err := o.ReleaseClient.GetEnvironmentRelease(ctx, toEnvironmentName, toReleaseName)
+	if err != nil {
+		return fmt.Errorf("failed to get --to environment release: %w", err)
+	}
+	// without a diff we can still describe the version and config changes, so only warn.  The notes go into change
+	// tickets, so every page of changes is read.
+	diff, err := status.GetAllEnvironmentReleaseDiffPages(ctx, func(ctx context.Context, options status.DiffOptions) (*status.EnvironmentReleaseDiff, error) {
+		return o.ReleaseClient.GetEnvironmentReleaseDiff(ctx, o.ToEnvironmentReleaseName, o.FromEnvironmentReleaseName, options)
+	})
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "warning: change lists are missing, failed to get environment release diff: %v\n", err)
+	}
+
+	releaseNotes := release_inspection.NewReleaseNotes(fromEnvironmentRelease, toEnvironmentRelease, diff)
+	return release_inspection.WriteReleaseNotes(o.Out, o.Format, releaseNotes)
+}
@@ -3,13 +3,23 @@ package release_website
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/openshift-online/service-status/pkg/aro/ciresults"
+	"github.com/openshift-online/service-status/pkg/aro/notifications"
+	"github.com/openshift-online/service-status/pkg/aro/pullrequests"
 	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
+	"github.com/openshift-online/service-status/pkg/aro/sippy"
 	"github.com/openshift-online/service-status/pkg/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 )
 
 // ReleaseMarkdownFlags gets bound to cobra commands and arguments.  It is used to validate input and then produce
@@ -23,6 +33,28 @@ type ReleaseMarkdownFlags struct {
 	PullSecretDir             string
 	ComponentGitRepoParentDir string
 	NumberOfDays              int
+	RepositoryRefreshInterval time.Duration
+	CachePolicies             map[string]string
+	WatchPollInterval         time.Duration
+	MaxChangesPerComponent    int
+	MaxCommitSearchDepth      int
+
+	EnrichPullRequests  bool
+	PullRequestCacheDir string
+	GithubAPIURL        string
+	GithubTokenFile     string
+	GitlabAPIURL        string
+	GitlabTokenFile     string
+
+	SippyURL                   string
+	SippyInsecureSkipTLSVerify bool
+	ResolveTestedCommits       bool
+	CIProwArtifactsDir         string
+	CIJSONFeeds                map[string]string
+	CIArtifactURL              string
+
+	NotificationConfigFile string
+	NotificationInterval   time.Duration
 
 	util.IOStreams
 }
@@ -32,7 +64,7 @@ func NewReleaseWebsiteCommand(streams util.IOStreams) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:           "release-website",
-		Short:         "Write markdown summarizing what is in a particular release",
+		Short:         "Serve the release website and its JSON API",
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -50,7 +82,7 @@ func NewReleaseWebsiteCommand(streams util.IOStreams) *cobra.Command {
 				return err
 			}
 
-			return o.Run(klog.NewContext(context.TODO(), klog.LoggerWithName(logger, "aro hcp release-markdown")))
+			return o.Run(klog.NewContext(context.TODO(), klog.LoggerWithName(logger, "aro hcp release-website")))
 		},
 	}
 
@@ -61,14 +93,37 @@ func NewReleaseWebsiteCommand(streams util.IOStreams) *cobra.Command {
 
 func NewReleaseMarkdownFlags(streams util.IOStreams) *ReleaseMarkdownFlags {
 	return &ReleaseMarkdownFlags{
-		IOStreams:    streams,
-		NumberOfDays: 14,
+		IOStreams:                 streams,
+		NumberOfDays:              14,
+		RepositoryRefreshInterval: 30 * time.Minute,
+		WatchPollInterval:         time.Minute,
+		CachePolicies:             defaultCachePolicies(),
+		MaxChangesPerComponent:    100,
+		MaxCommitSearchDepth:      release_inspection.DefaultMaxCommitSearchDepth,
+		GithubAPIURL:              "https://api.github.com",
+		GitlabAPIURL:              "https://gitlab.cee.redhat.com/api/v4",
+		SippyURL:                  sippy.DefaultBaseURL,
+		// we aren't sending anything sensitive to sippy.
+		SippyInsecureSkipTLSVerify: true,
+		ResolveTestedCommits:       true,
+		CIArtifactURL:              "https://storage.googleapis.com",
+		NotificationInterval:       5 * time.Minute,
+	}
+}
+
+// defaultCachePolicies keeps the lists warm, they back every page and the watch.  Diffs are per pair of releases, too
+// many to recompute on a schedule.
+func defaultCachePolicies() map[string]string {
+	return map[string]string{
+		release_inspection.CacheListEnvironments:                      "1h/30m",
+		release_inspection.CacheListEnvironmentReleases:               "1h/30m",
+		release_inspection.CacheListEnvironmentReleasesForEnvironment: "1h/30m",
 	}
 }
 
 func (f *ReleaseMarkdownFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&f.FileBasedAPIDir, "filebased-api-dir", f.FileBasedAPIDir, "The directory to read canned responses.")
-	flags.StringVar(&f.AROHCPDir, "aro-hcp-dir", f.AROHCPDir, "The directory where the https://github.com/Azure/ARO-HCP repo is extracted.")
+	flags.StringVar(&f.AROHCPDir, "aro-hcp-dir", f.AROHCPDir, "The directory where the https://github.com/Azure/ARO-HCP repo is extracted.  It is cloned in the background if it doesn't exist, and the checkout is moved to the latest origin/main unless it has local changes.")
 	flags.StringVar(&f.PullSecretDir, "pull-secret-dir", f.PullSecretDir, "The directory where dockerconfig.json's are located.")
 	flags.StringVar(&f.ComponentGitRepoParentDir, "component-git-repo-storage-dir", f.ComponentGitRepoParentDir, "The parent directory where components will be extracted for diff analysis.")
 
@@ -76,6 +131,25 @@ func (f *ReleaseMarkdownFlags) BindFlags(flags *pflag.FlagSet) {
 	flags.IntVar(&f.BindPort, "bind-port", f.BindPort, "The port on which to serve HTTP with authentication and authorization.")
 
 	flags.IntVar(&f.NumberOfDays, "num-days", f.NumberOfDays, "The number of days to look back for releases.")
+	flags.BoolVar(&f.EnrichPullRequests, "enrich-pull-requests", f.EnrichPullRequests, "Look up PR and MR titles, authors and labels from the GitHub and GitLab APIs for component changes.")
+	flags.StringVar(&f.PullRequestCacheDir, "pull-request-cache-dir", f.PullRequestCacheDir, "The directory where PR and MR metadata is cached between restarts.")
+	flags.StringVar(&f.GithubAPIURL, "github-api-url", f.GithubAPIURL, "The base URL of the GitHub REST API.")
+	flags.StringVar(&f.GithubTokenFile, "github-token-file", f.GithubTokenFile, "A file containing a GitHub token.  Anonymous requests are heavily rate limited.")
+	flags.StringVar(&f.GitlabAPIURL, "gitlab-api-url", f.GitlabAPIURL, "The base URL of the GitLab REST API.")
+	flags.StringVar(&f.GitlabTokenFile, "gitlab-token-file", f.GitlabTokenFile, "A file containing a GitLab token.")
+	flags.DurationVar(&f.RepositoryRefreshInterval, "repository-refresh-interval", f.RepositoryRefreshInterval, "How often the ARO-HCP checkout and the component git repositories are fetched in the background.")
+	flags.StringToStringVar(&f.CachePolicies, "cache-policy", f.CachePolicies, fmt.Sprintf("cache=TTL or cache=TTL/refresh interval.  Stale values are served while they are recomputed, and values older than the refresh interval are recomputed in the background.  Caches not given keep their default policy.  Caches are %s.", strings.Join(release_inspection.CacheNames, ", ")))
+	flags.DurationVar(&f.WatchPollInterval, "watch-poll-interval", f.WatchPollInterval, "How often environment releases are compared to find changes for watches.  Releases are only recomputed when their cache expires, so this mostly bounds the delay before a change is sent.")
+	flags.IntVar(&f.MaxChangesPerComponent, "max-changes-per-component", f.MaxChangesPerComponent, "The maximum number of changes listed for a component in one page of a diff.  All changes are still counted, and later pages list the rest.")
+	flags.StringVar(&f.SippyURL, "sippy-url", f.SippyURL, "The base URL of the sippy server to read CI job runs from.")
+	flags.BoolVar(&f.SippyInsecureSkipTLSVerify, "sippy-insecure-skip-tls-verify", f.SippyInsecureSkipTLSVerify, "Skip verifying the sippy server certificate.")
+	flags.StringVar(&f.CIProwArtifactsDir, "ci-prow-artifacts-dir", f.CIProwArtifactsDir, "A directory of downloaded prow job artifacts, one subdirectory per environment, read in addition to sippy.")
+	flags.StringToStringVar(&f.CIJSONFeeds, "ci-json-feed", f.CIJSONFeeds, "environment=URL of a JSON feed of job runs read in addition to sippy for that environment.")
+	flags.BoolVar(&f.ResolveTestedCommits, "resolve-tested-commits", f.ResolveTestedCommits, "Attribute CI job runs to the release containing the ARO-HCP commit they tested, read from the prow job metadata.  Job runs without that metadata, or all of them when disabled, are attributed by start time.")
+	flags.StringVar(&f.CIArtifactURL, "ci-artifact-url", f.CIArtifactURL, "The base URL serving the prow artifact buckets.")
+	flags.StringVar(&f.NotificationConfigFile, "notification-config", f.NotificationConfigFile, "A YAML file listing webhooks to notify about new releases, untested releases, stale images and red blocking CI.")
+	flags.DurationVar(&f.NotificationInterval, "notification-interval", f.NotificationInterval, "How often releases are checked for notifications.")
+	flags.IntVar(&f.MaxCommitSearchDepth, "max-commit-search-depth", f.MaxCommitSearchDepth, "The maximum number of commits to walk looking for the previous release of a component.  Beyond it a diff reports more than the changes found.  Zero means no limit.")
 
 }
 
@@ -87,8 +161,29 @@ func (f *ReleaseMarkdownFlags) Validate() error {
 		return fmt.Errorf("one of --filebased-api-dir and --aro-hcp-dir must be specified")
 	}
 
-	if len(f.PullSecretDir) == 0 {
-		return fmt.Errorf("--pull-secret-dir must be specified")
+	if len(f.AROHCPDir) > 0 && len(f.PullSecretDir) == 0 {
+		return fmt.Errorf("--pull-secret-dir must be specified with --aro-hcp-dir")
+	}
+	if f.RepositoryRefreshInterval <= 0 {
+		return fmt.Errorf("--repository-refresh-interval must be positive")
+	}
+	if _, err := parseCachePolicies(f.CachePolicies); err != nil {
+		return err
+	}
+	if f.WatchPollInterval <= 0 {
+		return fmt.Errorf("--watch-poll-interval must be positive")
+	}
+	if f.MaxChangesPerComponent <= 0 {
+		return fmt.Errorf("--max-changes-per-component must be positive")
+	}
+	if len(f.NotificationConfigFile) > 0 && len(f.AROHCPDir) == 0 {
+		return fmt.Errorf("--notification-config requires --aro-hcp-dir")
+	}
+	if f.NotificationInterval <= 0 {
+		return fmt.Errorf("--notification-interval must be positive")
+	}
+	if f.MaxCommitSearchDepth < 0 {
+		return fmt.Errorf("--max-commit-search-depth must not be negative")
 	}
 	if len(f.AROHCPDir) > 0 {
 		return nil
@@ -108,18 +203,128 @@ func (f *ReleaseMarkdownFlags) Validate() error {
 func (f *ReleaseMarkdownFlags) ToOptions() (*ReleaseMarkdownOptions, error) {
 	gitAccessor := release_inspection.NewDummyComponentsGitInfo()
 	if len(f.ComponentGitRepoParentDir) > 0 {
-		gitAccessor = release_inspection.NewComponentsGitInfo(f.ComponentGitRepoParentDir)
+		gitAccessor = release_inspection.NewComponentsGitInfo(f.ComponentGitRepoParentDir, f.MaxCommitSearchDepth)
+	}
+
+	ciResultSources := []ciresults.CIResultSource{
+		ciresults.NewSippyCIResultSource(
+			sippy.NewClient(sippy.ClientOptions{
+				BaseURL:               f.SippyURL,
+				InsecureSkipTLSVerify: f.SippyInsecureSkipTLSVerify,
+			}),
+			release_inspection.EnvironmentToSippyReleaseName,
+		),
+	}
+	if len(f.CIProwArtifactsDir) > 0 {
+		ciResultSources = append(ciResultSources, ciresults.NewProwArtifactsCIResultSource(f.CIProwArtifactsDir))
+	}
+	environmentToCIResultSource := map[string]ciresults.CIResultSource{}
+	for environmentName, feedURL := range f.CIJSONFeeds {
+		environmentToCIResultSource[environmentName] = ciresults.NewMultiCIResultSource(
+			slices.Concat(ciResultSources, []ciresults.CIResultSource{ciresults.NewJSONFeedCIResultSource(feedURL)})...,
+		)
+	}
+	ciResultSource := ciresults.NewPerEnvironmentCIResultSource(environmentToCIResultSource, ciresults.NewMultiCIResultSource(ciResultSources...))
+
+	testedCommitResolver := release_inspection.NewDummyTestedCommitResolver()
+	if f.ResolveTestedCommits {
+		testedCommitResolver = release_inspection.NewProwTestedCommitResolver(f.CIArtifactURL)
+	}
+
+	pullRequestAccessor := pullrequests.NewDummyPullRequestInfoAccessor()
+	if f.EnrichPullRequests {
+		githubToken, err := readTokenFile(f.GithubTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		gitlabToken, err := readTokenFile(f.GitlabTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		pullRequestAccessor = pullrequests.NewPullRequestInfoAccessor(
+			pullrequests.NewGithubClient(f.GithubAPIURL, githubToken, clock.RealClock{}),
+			pullrequests.NewGitlabClient(f.GitlabAPIURL, gitlabToken, clock.RealClock{}),
+		)
+		if len(f.PullRequestCacheDir) > 0 {
+			pullRequestAccessor = pullrequests.NewDiskCachingPullRequestInfoAccessor(pullRequestAccessor, f.PullRequestCacheDir, 24*time.Hour, clock.RealClock{})
+		}
+		// every diff of a release asks for the same pull requests, they are kept in memory whether or not there is a disk cache.
+		pullRequestAccessor = pullrequests.NewMemoryCachingPullRequestInfoAccessor(pullRequestAccessor, 24*time.Hour, clock.RealClock{})
+	}
+
+	// the flag replaces the whole default map when it is set, so the values given are merged over the defaults.
+	cachePolicyValues := defaultCachePolicies()
+	maps.Copy(cachePolicyValues, f.CachePolicies)
+	cachePolicies, err := parseCachePolicies(cachePolicyValues)
+	if err != nil {
+		return nil, err
+	}
+
+	var notificationConfig *notifications.Config
+	notificationWebhooks := []notifications.Webhook{}
+	if len(f.NotificationConfigFile) > 0 {
+		var err error
+		notificationConfig, notificationWebhooks, err = notifications.ReadConfig(f.NotificationConfigFile, clock.RealClock{})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &ReleaseMarkdownOptions{
-		BindAddress:       f.BindAddress,
-		BindPort:          f.BindPort,
-		FileBasedAPIDir:   f.FileBasedAPIDir,
-		AROHCPDir:         f.AROHCPDir,
-		NumberOfDays:      f.NumberOfDays,
-		ImageInfoAccessor: release_inspection.NewThreadSafeImageInfoAccessor(f.PullSecretDir),
-		GitAccessor:       gitAccessor,
+		BindAddress:               f.BindAddress,
+		BindPort:                  f.BindPort,
+		FileBasedAPIDir:           f.FileBasedAPIDir,
+		AROHCPDir:                 f.AROHCPDir,
+		NumberOfDays:              f.NumberOfDays,
+		RepositoryRefreshInterval: f.RepositoryRefreshInterval,
+		CachePolicies:             cachePolicies,
+		WatchPollInterval:         f.WatchPollInterval,
+		MaxChangesPerComponent:    f.MaxChangesPerComponent,
+		ImageInfoAccessor:         release_inspection.NewThreadSafeImageInfoAccessor(f.PullSecretDir),
+		GitAccessor:               gitAccessor,
+		PullRequestAccessor:       pullRequestAccessor,
+		TestedCommitResolver:      testedCommitResolver,
+		CIResultSource:            ciResultSource,
+		NotificationConfig:        notificationConfig,
+		NotificationWebhooks:      notificationWebhooks,
+		NotificationInterval:      f.NotificationInterval,
 
 		IOStreams: f.IOStreams,
 	}, nil
 }
+
+func readTokenFile(tokenFile string) (string, error) {
+	if len(tokenFile) == 0 {
+		return "", nil
+	}
+	tokenBytes, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	return strings.TrimSpace(string(tokenBytes)), nil
+}
+
+// parseCachePolicies parses the --cache-policy values, TTL or TTL/refresh interval.
+func parseCachePolicies(cachePolicies map[string]string) (map[string]release_inspection.CachePolicy, error) {
+	ret := map[string]release_inspection.CachePolicy{}
+	for cacheName, value := range cachePolicies {
+		if !slices.Contains(release_inspection.CacheNames, cacheName) {
+			return nil, fmt.Errorf("--cache-policy: unknown cache %q, must be one of %s", cacheName, strings.Join(release_inspection.CacheNames, ", "))
+		}
+		ttlString, refreshIntervalString, hasRefreshInterval := strings.Cut(value, "/")
+		policy := release_inspection.CachePolicy{}
+		var err error
+		policy.TTL, err = time.ParseDuration(ttlString)
+		if err != nil || policy.TTL <= 0 {
+			return nil, fmt.Errorf("--cache-policy: %s TTL must be a positive duration, not %q", cacheName, ttlString)
+		}
+		if hasRefreshInterval {
+			policy.RefreshInterval, err = time.ParseDuration(refreshIntervalString)
+			if err != nil || policy.RefreshInterval < 0 {
+				return nil, fmt.Errorf("--cache-policy: %s refresh interval must be a non-negative duration, not %q", cacheName, refreshIntervalString)
+			}
+		}
+		ret[cacheName] = policy
+	}
+	return ret, nil
+}
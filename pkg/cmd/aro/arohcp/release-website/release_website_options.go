@@ -5,11 +5,16 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/openshift-online/service-status/pkg/aro/ciresults"
 	"github.com/openshift-online/service-status/pkg/aro/client"
+	"github.com/openshift-online/service-status/pkg/aro/notifications"
+	"github.com/openshift-online/service-status/pkg/aro/pullrequests"
 	release_inspection "github.com/openshift-online/service-status/pkg/aro/release-inspection"
 	release_webserver "github.com/openshift-online/service-status/pkg/aro/release-webserver"
+	"github.com/openshift-online/service-status/pkg/metrics"
 	"github.com/openshift-online/service-status/pkg/util"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
@@ -19,54 +24,109 @@ type ReleaseMarkdownOptions struct {
 	BindAddress net.IP
 	BindPort    int
 
-	FileBasedAPIDir string
-	AROHCPDir       string
-	NumberOfDays    int
+	FileBasedAPIDir        string
+	AROHCPDir              string
+	NumberOfDays           int
+	MaxChangesPerComponent int
+
+	RepositoryRefreshInterval time.Duration
+	CachePolicies             map[string]release_inspection.CachePolicy
+	WatchPollInterval         time.Duration
 
 	ImageInfoAccessor release_inspection.ImageInfoAccessor
 	GitAccessor       release_inspection.ComponentsGitInfo
 
+	PullRequestAccessor pullrequests.PullRequestInfoAccessor
+	CIResultSource      ciresults.CIResultSource
+
+	TestedCommitResolver release_inspection.TestedCommitResolver
+
+	// NotificationConfig is nil when notifications are disabled.
+	NotificationConfig   *notifications.Config
+	NotificationWebhooks []notifications.Webhook
+	NotificationInterval time.Duration
+
 	util.IOStreams
 }
 
 func (o *ReleaseMarkdownOptions) Run(ctx context.Context) error {
 	logger := klog.FromContext(ctx)
 
-	releaseAccessor := release_inspection.NewCachingReleaseAccessor(
-		release_inspection.NewReleaseAccessor(
-			o.AROHCPDir,
-			o.NumberOfDays,
-			o.ImageInfoAccessor,
-			o.GitAccessor,
-		),
-		clock.RealClock{})
-
-	var releaseClient client.ReleaseClient
+	var (
+		releaseAccessor     release_inspection.CachingReleaseAccessor
+		releaseClient       client.ReleaseClient
+		repositoryRefresher *release_inspection.RepositoryRefresher
+		// readiness waits for scans, which only happen when serving from an ARO-HCP checkout.
+		readinessScanStatusRecorder *release_inspection.ScanStatusRecorder
+	)
 	switch {
 	case len(o.FileBasedAPIDir) > 0 && len(o.AROHCPDir) > 0:
-		return fmt.Errorf("cannot specify both --file-based-api-dir and --aro-hcp-dir")
+		return fmt.Errorf("cannot specify both --filebased-api-dir and --aro-hcp-dir")
 	case len(o.FileBasedAPIDir) > 0:
+		// both the JSON and the HTML routes serve the snapshot, nothing is scanned or refreshed.
 		apiFS := os.DirFS(o.FileBasedAPIDir)
+		releaseAccessor = release_inspection.NewCachingReleaseAccessor(release_inspection.NewSnapshotReleaseAccessor(apiFS), o.CachePolicies, clock.RealClock{})
 		releaseClient = client.NewFileSystemReleaseClient(apiFS)
+		repositoryRefresher = release_inspection.NewRepositoryRefresher(nil, o.RepositoryRefreshInterval, clock.RealClock{})
 	case len(o.AROHCPDir) > 0:
+		aroHCPRepository := release_inspection.NewAROHCPRepository(o.AROHCPDir)
+		scanStatusRecorder := release_inspection.NewScanStatusRecorder(clock.RealClock{})
+		releaseAccessor = release_inspection.NewCachingReleaseAccessor(
+			release_inspection.NewReleaseAccessor(
+				aroHCPRepository,
+				o.NumberOfDays,
+				o.MaxChangesPerComponent,
+				o.ImageInfoAccessor,
+				o.GitAccessor,
+				o.PullRequestAccessor,
+				o.CIResultSource,
+				o.TestedCommitResolver,
+				scanStatusRecorder,
+				clock.RealClock{},
+			),
+			o.CachePolicies,
+			clock.RealClock{})
 		releaseClient = client.NewBasicReleaseClient("http://" + net.JoinHostPort("localhost", fmt.Sprintf("%d", o.BindPort)))
+		readinessScanStatusRecorder = scanStatusRecorder
+
+		refreshableRepositories := append(o.GitAccessor.ListRefreshableRepositories(), aroHCPRepository)
+		repositoryRefresher = release_inspection.NewRepositoryRefresher(refreshableRepositories, o.RepositoryRefreshInterval, clock.RealClock{})
+		go repositoryRefresher.Run(ctx)
+
+		releaseMetrics := release_inspection.NewReleaseMetrics(releaseAccessor, o.WatchPollInterval, clock.RealClock{})
+		metrics.Registry.MustRegister(releaseMetrics)
+		go releaseMetrics.Run(ctx)
+		if o.NotificationConfig != nil {
+			notifier := notifications.NewNotifier(releaseAccessor, o.NotificationWebhooks, o.NotificationConfig.ExternalURL, o.NotificationConfig.StateFile, o.NotificationInterval, clock.RealClock{})
+			go notifier.Run(ctx)
+		}
+	default:
+		return fmt.Errorf("one of --filebased-api-dir and --aro-hcp-dir must be specified")
 	}
 
+	go releaseAccessor.Run(ctx)
+	environmentReleaseWatcher := release_inspection.NewEnvironmentReleaseWatcher(releaseAccessor, o.WatchPollInterval, clock.RealClock{})
+	go environmentReleaseWatcher.Run(ctx)
+
 	httpRouter := gin.Default()
 
-	// JSON endpoints
-	httpRouter.GET("/api/aro-hcp/environments", release_webserver.ListEnvironments(releaseAccessor))
-	httpRouter.GET("/api/aro-hcp/environments/:name", release_webserver.GetEnvironment(releaseAccessor))
-	httpRouter.GET("/api/aro-hcp/environments/:name/environmentreleases", release_webserver.ListEnvironmentReleasesForEnvironment(releaseAccessor))
-	httpRouter.GET("/api/aro-hcp/environmentreleases", release_webserver.ListEnvironmentReleases(releaseAccessor))
-	httpRouter.GET("/api/aro-hcp/environmentreleases/:name", release_webserver.GetEnvironmentRelease(releaseAccessor))
-	httpRouter.GET("/api/aro-hcp/environmentreleases/:name/diff/:otherName", release_webserver.GetEnvironmentReleaseDiff(releaseAccessor))
+	// health endpoints
+	httpRouter.GET("/healthz", release_webserver.Healthz())
+	httpRouter.GET("/readyz", release_webserver.Readyz(releaseAccessor, readinessScanStatusRecorder))
+
+	// JSON endpoints, documented by /openapi.json
+	apiRoutes := release_webserver.NewAPIRoutes(releaseAccessor, environmentReleaseWatcher, repositoryRefresher, readinessScanStatusRecorder)
+	for _, apiRoute := range apiRoutes {
+		httpRouter.GET(apiRoute.Path, apiRoute.Handler)
+	}
+	httpRouter.GET("/openapi.json", release_webserver.ServeOpenAPI(apiRoutes))
+	httpRouter.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	// HTML endpoints
-	httpRouter.LoadHTMLGlob("pkg/aro/release-webserver/html-templates/*")
-	httpRouter.GET("", release_webserver.ServeReleaseSummary(releaseClient))
-	httpRouter.GET("/http/aro-hcp/summary.html", release_webserver.ServeReleaseSummary(releaseClient))
-	httpRouter.GET("/http/aro-hcp/environmentreleases/:name/summary.html", release_webserver.ServeEnvironmentReleaseSummary(releaseClient))
+	if err := release_webserver.LoadHTMLTemplates(httpRouter, ""); err != nil {
+		return err
+	}
+	release_webserver.RegisterHTMLRoutes(httpRouter, releaseClient)
 
 	listener, err := net.Listen("tcp", net.JoinHostPort(o.BindAddress.String(), fmt.Sprintf("%d", o.BindPort)))
 	if err != nil {
@@ -0,0 +1,91 @@
+// Package metrics holds the Prometheus registry this server exposes and the metrics shared by every package.
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric this server exposes.  Packages register their metrics with promauto.With(Registry).
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// DefaultDurationBuckets suit calls from a fraction of a second up to a slow git clone.
+var DefaultDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+var (
+	externalCallDuration = promauto.With(Registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "service_status_external_call_duration_seconds",
+			Help:    "How long calls to git, podman and sippy took.",
+			Buckets: DefaultDurationBuckets,
+		},
+		[]string{"system", "operation"},
+	)
+	externalCallErrors = promauto.With(Registry).NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "service_status_external_call_errors_total",
+			Help: "How many calls to git, podman and sippy failed.",
+		},
+		[]string{"system", "operation"},
+	)
+)
+
+// ExternalCallError is the latest failure of one system.
+type ExternalCallError struct {
+	System    string
+	Operation string
+	Time      time.Time
+	Err       error
+}
+
+var (
+	latestExternalCallErrorsLock sync.Mutex
+	latestExternalCallErrors     = map[string]ExternalCallError{}
+)
+
+// ObserveExternalCall records the duration of a call that started at startTime and whether it failed.
+func ObserveExternalCall(system, operation string, startTime time.Time, err error) {
+	externalCallDuration.WithLabelValues(system, operation).Observe(time.Since(startTime).Seconds())
+	if err != nil {
+		externalCallErrors.WithLabelValues(system, operation).Inc()
+
+		latestExternalCallErrorsLock.Lock()
+		defer latestExternalCallErrorsLock.Unlock()
+		latestExternalCallErrors[system] = ExternalCallError{System: system, Operation: operation, Time: time.Now(), Err: err}
+	}
+}
+
+// LatestExternalCallErrors returns the latest failure of each system, sorted by system.  Systems that never failed
+// are left out.
+func LatestExternalCallErrors() []ExternalCallError {
+	latestExternalCallErrorsLock.Lock()
+	defer latestExternalCallErrorsLock.Unlock()
+
+	ret := make([]ExternalCallError, 0, len(latestExternalCallErrors))
+	for _, externalCallError := range latestExternalCallErrors {
+		ret = append(ret, externalCallError)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].System < ret[j].System
+	})
+	return ret
+}
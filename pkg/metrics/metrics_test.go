@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveExternalCall(t *testing.T) {
+	ObserveExternalCall("test-system", "clone", time.Now(), nil)
+	ObserveExternalCall("test-system", "clone", time.Now(), errors.New("timed out"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(externalCallErrors.WithLabelValues("test-system", "clone")))
+
+	latestErrors := LatestExternalCallErrors()
+	require.Len(t, latestErrors, 1)
+	assert.Equal(t, "test-system", latestErrors[0].System)
+	assert.EqualError(t, latestErrors[0].Err, "timed out")
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `service_status_external_call_duration_seconds_count{operation="clone",system="test-system"} 2`)
+	assert.Contains(t, recorder.Body.String(), `service_status_external_call_errors_total{operation="clone",system="test-system"} 1`)
+}
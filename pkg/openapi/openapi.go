@@ -0,0 +1,255 @@
+// Package openapi builds an OpenAPI 3 document from Go types, reading their JSON tags the way encoding/json does, and
+// validates JSON against it.  It only covers what the status API uses: structs, maps with string keys, slices,
+// pointers, times and scalars.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type PathItem struct {
+	Get *Operation `json:"get,omitempty"`
+}
+
+type Operation struct {
+	Summary     string               `json:"summary,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Deprecated           bool               `json:"deprecated,omitempty"`
+}
+
+// DeprecatedJSONNamer is implemented by types whose MarshalJSON also writes some fields under the names they had
+// before they were renamed.  DeprecatedJSONNames maps each old name to the current one.
+type DeprecatedJSONNamer interface {
+	DeprecatedJSONNames() map[string]string
+}
+
+const refPrefix = "#/components/schemas/"
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+	deprecatedJSONNamerType = reflect.TypeOf((*DeprecatedJSONNamer)(nil)).Elem()
+)
+
+// Builder adds operations to a document, adding the schema of every named struct they return to its components.
+type Builder struct {
+	document *Document
+	// typeToName keeps two types with the same name from sharing a schema.
+	typeToName map[reflect.Type]string
+}
+
+func NewBuilder(title, version string) *Builder {
+	return &Builder{
+		document: &Document{
+			OpenAPI:    Version,
+			Info:       Info{Title: title, Version: version},
+			Paths:      map[string]*PathItem{},
+			Components: Components{Schemas: map[string]*Schema{}},
+		},
+		typeToName: map[reflect.Type]string{},
+	}
+}
+
+func (b *Builder) Document() *Document {
+	return b.document
+}
+
+var ginPathParameter = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// AddGet documents a GET of ginPath, which uses gin's :name syntax.  Every path parameter is a required string.
+func (b *Builder) AddGet(ginPath string, operation *Operation) {
+	for _, match := range ginPathParameter.FindAllStringSubmatch(ginPath, -1) {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	path := PathFromGin(ginPath)
+	if b.document.Paths[path] == nil {
+		b.document.Paths[path] = &PathItem{}
+	}
+	b.document.Paths[path].Get = operation
+}
+
+// PathFromGin turns gin's /things/:name into OpenAPI's /things/{name}.
+func PathFromGin(ginPath string) string {
+	return ginPathParameter.ReplaceAllString(ginPath, "{$1}")
+}
+
+// SchemaFor returns the schema of t.  Named structs are added to the components and referenced.
+func (b *Builder) SchemaFor(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(b.SchemaFor(t.Elem()))
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.SchemaFor(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.SchemaFor(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if len(t.Name()) == 0 {
+			return b.structSchema(t)
+		}
+		return &Schema{Ref: refPrefix + b.addStruct(t)}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Interface:
+		return &Schema{}
+	}
+	panic(fmt.Sprintf("openapi: %v has no schema", t))
+}
+
+func (b *Builder) addStruct(t reflect.Type) string {
+	if name, ok := b.typeToName[t]; ok {
+		return name
+	}
+	name := t.Name()
+	for i := 2; b.document.Components.Schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", t.Name(), i)
+	}
+	b.typeToName[t] = name
+	// reserve the name before recursing so self references resolve.
+	b.document.Components.Schemas[name] = &Schema{}
+	*b.document.Components.Schemas[name] = *b.structSchema(t)
+	return name
+}
+
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	ret := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	b.addStructFields(ret, t)
+	if t.Implements(deprecatedJSONNamerType) {
+		b.addDeprecatedFields(ret, reflect.Zero(t).Interface().(DeprecatedJSONNamer).DeprecatedJSONNames())
+	}
+	sort.Strings(ret.Required)
+	return ret
+}
+
+// addDeprecatedFields documents the old names next to the current ones, required when the current one is.
+func (b *Builder) addDeprecatedFields(schema *Schema, oldToCurrent map[string]string) {
+	for old, current := range oldToCurrent {
+		currentSchema, ok := schema.Properties[current]
+		if !ok {
+			panic(fmt.Sprintf("openapi: deprecated %q refers to unknown %q", old, current))
+		}
+		// like nullable, deprecated can't be set next to a $ref.
+		oldSchema := &Schema{AllOf: []*Schema{currentSchema}, Deprecated: true}
+		if len(currentSchema.Ref) == 0 {
+			copied := *currentSchema
+			oldSchema = &copied
+			oldSchema.Deprecated = true
+		}
+		schema.Properties[old] = oldSchema
+		if slices.Contains(schema.Required, current) {
+			schema.Required = append(schema.Required, old)
+		}
+	}
+}
+
+func (b *Builder) addStructFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		// encoding/json promotes the fields of untagged embedded structs, the ",inline" option is only for readers.
+		if field.Anonymous && len(name) == 0 && field.Type.Kind() == reflect.Struct {
+			b.addStructFields(schema, field.Type)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		schema.Properties[name] = b.SchemaFor(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// nullable can't be set next to a $ref in OpenAPI 3.0, so references are wrapped in an allOf.
+func nullable(schema *Schema) *Schema {
+	if len(schema.Ref) > 0 {
+		return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+	}
+	schema.Nullable = true
+	return schema
+}
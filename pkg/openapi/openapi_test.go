@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMeta struct {
+	Kind string `json:"kind"`
+}
+
+type testChild struct {
+	Name string `json:"name"`
+}
+
+type testParent struct {
+	testMeta `json:",inline"`
+	Count    int                   `json:"count"`
+	Time     *time.Time            `json:"time,omitempty"`
+	Child    *testChild            `json:"child"`
+	Children map[string]*testChild `json:"children,omitempty"`
+	Tags     []string              `json:"tags,omitempty"`
+	Untagged string
+	Ignored  string `json:"-"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	builder := NewBuilder("test", "v1")
+	schema := builder.SchemaFor(reflect.TypeOf(testParent{}))
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/testParent"}, schema)
+
+	document := builder.Document()
+	parent := document.Components.Schemas["testParent"]
+	require.NotNil(t, parent)
+	assert.Equal(t, []string{"Untagged", "child", "count", "kind"}, parent.Required)
+	assert.ElementsMatch(t, []string{"kind", "count", "time", "child", "children", "tags", "Untagged"}, keys(parent.Properties))
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time", Nullable: true}, parent.Properties["time"])
+	assert.Equal(t, &Schema{AllOf: []*Schema{{Ref: "#/components/schemas/testChild"}}, Nullable: true}, parent.Properties["child"])
+	assert.Contains(t, document.Components.Schemas, "testChild")
+}
+
+func TestAddGet(t *testing.T) {
+	builder := NewBuilder("test", "v1")
+	builder.AddGet("/things/:name/diff/:otherName", &Operation{})
+
+	pathItem := builder.Document().Paths["/things/{name}/diff/{otherName}"]
+	require.NotNil(t, pathItem)
+	require.Len(t, pathItem.Get.Parameters, 2)
+	assert.Equal(t, "name", pathItem.Get.Parameters[0].Name)
+	assert.Equal(t, "otherName", pathItem.Get.Parameters[1].Name)
+	assert.True(t, pathItem.Get.Parameters[1].Required)
+}
+
+func TestValidate(t *testing.T) {
+	builder := NewBuilder("test", "v1")
+	schema := builder.SchemaFor(reflect.TypeOf(testParent{}))
+	document := builder.Document()
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr []string
+	}{
+		{
+			name: "valid",
+			json: `{"kind":"Parent","count":3,"time":"2025-08-05T00:00:00Z","child":{"name":"a"},"children":{"b":{"name":"b"}},"Untagged":""}`,
+		},
+		{
+			name: "null pointer",
+			json: `{"kind":"Parent","count":3,"child":null,"Untagged":""}`,
+		},
+		{
+			name: "drift",
+			json: `{"kind":"Parent","count":1.5,"time":"yesterday","child":{"Name":"a"},"Untagged":"","extra":true}`,
+			wantErr: []string{
+				`$.count: must be integer, not float64`,
+				`$.time: must be an RFC3339 time, not "yesterday"`,
+				`$.child: missing required "name"`,
+				`$.child.Name: not in the schema`,
+				`$.extra: not in the schema`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := document.Validate(schema, []byte(tt.json))
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, wantErr := range tt.wantErr {
+				assert.Contains(t, err.Error(), wantErr)
+			}
+		})
+	}
+}
+
+func keys[V any](m map[string]V) []string {
+	ret := []string{}
+	for key := range m {
+		ret = append(ret, key)
+	}
+	return ret
+}
+
+type testRenamed struct {
+	Child testChild `json:"child"`
+	Name  string    `json:"name,omitempty"`
+}
+
+func (testRenamed) DeprecatedJSONNames() map[string]string {
+	return map[string]string{"Child": "child", "Name": "name"}
+}
+
+func TestSchemaForDeprecatedJSONNames(t *testing.T) {
+	builder := NewBuilder("test", "v1")
+	builder.SchemaFor(reflect.TypeOf(testRenamed{}))
+
+	renamed := builder.Document().Components.Schemas["testRenamed"]
+	require.NotNil(t, renamed)
+	assert.Equal(t, []string{"Child", "child"}, renamed.Required)
+	assert.Equal(t, &Schema{AllOf: []*Schema{renamed.Properties["child"]}, Deprecated: true}, renamed.Properties["Child"])
+	assert.Equal(t, &Schema{Type: "string", Deprecated: true}, renamed.Properties["Name"])
+	assert.False(t, renamed.Properties["name"].Deprecated)
+}
@@ -0,0 +1,114 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Validate checks that the JSON in data matches schema.  Objects with properties must not have other keys, so a field
+// added to a response without being in the document is reported.  Every mismatch is returned, not only the first.
+func (d *Document) Validate(schema *Schema, data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	errs := []error{}
+	d.validate("$", schema, value, &errs)
+	return errors.Join(errs...)
+}
+
+func (d *Document) validate(path string, schema *Schema, value any, errs *[]error) {
+	if len(schema.Ref) > 0 {
+		referenced, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, refPrefix)]
+		if !ok {
+			*errs = append(*errs, fmt.Errorf("%s: unknown schema %q", path, schema.Ref))
+			return
+		}
+		d.validate(path, referenced, value, errs)
+		return
+	}
+	if value == nil {
+		if !schema.Nullable && len(schema.Type) > 0 {
+			*errs = append(*errs, fmt.Errorf("%s: must not be null", path))
+		}
+		return
+	}
+	for _, subSchema := range schema.AllOf {
+		d.validate(path, subSchema, value, errs)
+	}
+
+	mismatch := func() {
+		*errs = append(*errs, fmt.Errorf("%s: must be %s, not %T", path, schema.Type, value))
+	}
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			mismatch()
+			return
+		}
+		d.validateObject(path, schema, object, errs)
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			mismatch()
+			return
+		}
+		for i, item := range array {
+			d.validate(fmt.Sprintf("%s[%d]", path, i), schema.Items, item, errs)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			mismatch()
+			return
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				*errs = append(*errs, fmt.Errorf("%s: must be an RFC3339 time, not %q", path, str))
+			}
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != float64(int64(number)) {
+			mismatch()
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			mismatch()
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			mismatch()
+		}
+	}
+}
+
+func (d *Document) validateObject(path string, schema *Schema, object map[string]any, errs *[]error) {
+	for _, required := range schema.Required {
+		if _, ok := object[required]; !ok {
+			*errs = append(*errs, fmt.Errorf("%s: missing required %q", path, required))
+		}
+	}
+
+	keys := []string{}
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		keyPath := path + "." + key
+		switch {
+		case schema.Properties[key] != nil:
+			d.validate(keyPath, schema.Properties[key], object[key], errs)
+		case schema.AdditionalProperties != nil:
+			d.validate(keyPath, schema.AdditionalProperties, object[key], errs)
+		case len(schema.Properties) > 0:
+			*errs = append(*errs, fmt.Errorf("%s: not in the schema", keyPath))
+		}
+	}
+}